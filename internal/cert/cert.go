@@ -1,15 +1,21 @@
 package cert
 
 import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/base64"
+	"encoding/pem"
 	"errors"
 	"fmt"
+	"math/big"
+	"net"
 	"os"
 	"path/filepath"
-	"strings"
 	"time"
-
-	"github.com/mdelapenya/tlscert"
 )
 
 const (
@@ -24,6 +30,11 @@ const (
 
 	// DefaultDirPermission is the default permission for certificate directories.
 	DefaultDirPermission = 0o750
+
+	// DefaultFilePermission is the default permission for certificate and key files.
+	DefaultFilePermission = 0o600
+
+	caCommonName = "k3senv-ca"
 )
 
 // Data contains the certificate and key data in PEM format.
@@ -39,53 +50,340 @@ func (d *Data) CABundle() []byte {
 	return []byte(base64.StdEncoding.EncodeToString(d.CACert))
 }
 
+// TLSCertificate returns the server certificate and key as a tls.Certificate.
+func (d *Data) TLSCertificate() (tls.Certificate, error) {
+	pair, err := tls.X509KeyPair(d.ServerCert, d.ServerKey)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to load server key pair: %w", err)
+	}
+
+	return pair, nil
+}
+
+// TLSConfig returns a tls.Config serving the server certificate and trusting the CA.
+func (d *Data) TLSConfig() (*tls.Config, error) {
+	pair, err := d.TLSCertificate()
+	if err != nil {
+		return nil, err
+	}
+
+	pool, err := d.CACertPool()
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{pair},
+		RootCAs:      pool,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+// CACertPool returns a certificate pool containing only the CA certificate.
+func (d *Data) CACertPool() (*x509.CertPool, error) {
+	return d.appendCACert(x509.NewCertPool())
+}
+
+// SystemPlusCACertPool returns a copy of the system certificate pool with the
+// CA certificate added, so that both the test CA and public CAs are trusted.
+func (d *Data) SystemPlusCACertPool() (*x509.CertPool, error) {
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load system certificate pool: %w", err)
+	}
+
+	return d.appendCACert(pool)
+}
+
+func (d *Data) appendCACert(pool *x509.CertPool) (*x509.CertPool, error) {
+	if len(d.CACert) == 0 {
+		return nil, errors.New("CA certificate is empty")
+	}
+
+	if !pool.AppendCertsFromPEM(d.CACert) {
+		return nil, errors.New("failed to parse CA certificate: no valid PEM encoded certificate found")
+	}
+
+	return pool, nil
+}
+
+// ExpiresAt returns the expiration time of the server certificate.
+func (d *Data) ExpiresAt() (time.Time, error) {
+	c, err := d.serverCertificate()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return c.NotAfter, nil
+}
+
+// IsValid reports whether the server certificate is currently within its validity period.
+func (d *Data) IsValid() bool {
+	return d.IsValidAt(time.Now())
+}
+
+// IsValidAt reports whether the server certificate is valid at the given time.
+// Returns false if the certificate cannot be parsed.
+func (d *Data) IsValidAt(t time.Time) bool {
+	c, err := d.serverCertificate()
+	if err != nil {
+		return false
+	}
+
+	return !t.Before(c.NotBefore) && !t.After(c.NotAfter)
+}
+
+func (d *Data) serverCertificate() (*x509.Certificate, error) {
+	block, _ := pem.Decode(d.ServerCert)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("failed to decode server certificate PEM")
+	}
+
+	c, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse server certificate: %w", err)
+	}
+
+	return c, nil
+}
+
 // New generates TLS certificates in the specified path with the given validity and SANs.
-// Returns the certificate data in PEM format.
-func New(path string, validity time.Duration, sans []string) (*Data, error) {
-	if err := os.MkdirAll(path, DefaultDirPermission); err != nil {
-		return nil, fmt.Errorf("failed to create cert directory: %w", err)
+// Unless a CA is provided through WithCA, a self-signed CA is generated to sign the
+// server certificate. Returns the certificate data in PEM format.
+func New(path string, validity time.Duration, sans []string, opts ...Option) (*Data, error) {
+	options := (&Options{KeyAlgorithm: DefaultKeyAlgorithm}).ApplyOptions(opts)
+
+	if err := options.KeyAlgorithm.Validate(); err != nil {
+		return nil, err
 	}
 
-	caCert := tlscert.SelfSignedFromRequest(tlscert.Request{
-		Name:      "ca",
-		Host:      "k3senv-ca",
-		ValidFor:  validity,
-		IsCA:      true,
-		ParentDir: path,
-	})
+	var (
+		caCertPEM []byte
+		caCert    *x509.Certificate
+		caKey     crypto.Signer
+		err       error
+	)
 
-	serverCert := tlscert.SelfSignedFromRequest(tlscert.Request{
-		Name:      "tls",
-		Host:      strings.Join(sans, ","),
-		ValidFor:  validity,
-		Parent:    caCert,
-		ParentDir: path,
-	})
+	if len(options.CACert) > 0 || len(options.CAKey) > 0 {
+		caCert, caKey, err = ParseCA(options.CACert, options.CAKey)
+		if err != nil {
+			return nil, err
+		}
 
-	if caCert == nil || serverCert == nil {
-		return nil, errors.New("failed to generate certificates")
+		caCertPEM = options.CACert
+	} else {
+		caCertPEM, caCert, caKey, err = newCACertificate(options.KeyAlgorithm, validity)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate CA certificate: %w", err)
+		}
 	}
 
-	caCertPEM, err := readFile(path, CACertFileName)
+	serverCertPEM, serverKeyPEM, err := newServerCertificate(caCert, caKey, options.KeyAlgorithm, validity, sans)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read CA cert: %w", err)
+		return nil, fmt.Errorf("failed to generate server certificate: %w", err)
+	}
+
+	data := &Data{
+		CACert:     caCertPEM,
+		ServerCert: serverCertPEM,
+		ServerKey:  serverKeyPEM,
 	}
 
-	serverCertPEM, err := readFile(path, CertFileName)
+	if err := data.Write(path); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// Load reads existing PEM encoded certificate, key and CA files. It returns an
+// error if the key does not match the certificate or the certificate is not
+// signed by the CA. The returned data has no Path until Write is called.
+func Load(certFile string, keyFile string, caFile string) (*Data, error) {
+	serverCertPEM, err := readFile(certFile)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read server cert: %w", err)
 	}
 
-	serverKeyPEM, err := readFile(path, KeyFileName)
+	serverKeyPEM, err := readFile(keyFile)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read server key: %w", err)
 	}
 
-	return &Data{
-		Path:       path,
+	caCertPEM, err := readFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA cert: %w", err)
+	}
+
+	data := &Data{
 		CACert:     caCertPEM,
 		ServerCert: serverCertPEM,
 		ServerKey:  serverKeyPEM,
+	}
+
+	if _, err := data.TLSCertificate(); err != nil {
+		return nil, err
+	}
+
+	leaf, err := data.serverCertificate()
+	if err != nil {
+		return nil, err
+	}
+
+	pool, err := data.CACertPool()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = leaf.Verify(x509.VerifyOptions{
+		Roots:     pool,
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("server certificate is not signed by the CA: %w", err)
+	}
+
+	return data, nil
+}
+
+// Write stores the certificates in the specified path using the standard file
+// names and sets the data Path accordingly.
+func (d *Data) Write(path string) error {
+	if err := os.MkdirAll(path, DefaultDirPermission); err != nil {
+		return fmt.Errorf("failed to create cert directory: %w", err)
+	}
+
+	files := map[string][]byte{
+		CACertFileName: d.CACert,
+		CertFileName:   d.ServerCert,
+		KeyFileName:    d.ServerKey,
+	}
+
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(path, name), data, DefaultFilePermission); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+
+	d.Path = path
+
+	return nil
+}
+
+// ParseCA parses a PEM encoded CA certificate and its private key.
+// Returns an error if the certificate is not a CA or the key does not match it.
+func ParseCA(certPEM []byte, keyPEM []byte) (*x509.Certificate, crypto.Signer, error) {
+	pair, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load CA key pair: %w", err)
+	}
+
+	caCert, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse CA certificate: %w", err)
+	}
+
+	if !caCert.BasicConstraintsValid || !caCert.IsCA {
+		return nil, nil, fmt.Errorf("certificate %q is not a CA", caCert.Subject.CommonName)
+	}
+
+	signer, ok := pair.PrivateKey.(crypto.Signer)
+	if !ok {
+		return nil, nil, fmt.Errorf("unsupported CA private key type %T", pair.PrivateKey)
+	}
+
+	return caCert, signer, nil
+}
+
+func newCACertificate(alg KeyAlgorithm, validity time.Duration) ([]byte, *x509.Certificate, crypto.Signer, error) {
+	key, err := alg.generateKey()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	template, err := newTemplate(alg, validity)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	template.Subject = pkix.Name{CommonName: caCommonName}
+	template.DNSNames = []string{caCommonName}
+	template.IsCA = true
+	template.KeyUsage |= x509.KeyUsageCertSign
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to create certificate: %w", err)
+	}
+
+	caCert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to parse certificate: %w", err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), caCert, key, nil
+}
+
+func newServerCertificate(
+	caCert *x509.Certificate,
+	caKey crypto.Signer,
+	alg KeyAlgorithm,
+	validity time.Duration,
+	sans []string,
+) ([]byte, []byte, error) {
+	key, err := alg.generateKey()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	template, err := newTemplate(alg, validity)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+
+	for _, san := range sans {
+		if ip := net.ParseIP(san); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, san)
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, caCert, key.Public(), caKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create certificate: %w", err)
+	}
+
+	keyPEM, err := encodeKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), keyPEM, nil
+}
+
+func newTemplate(alg KeyAlgorithm, validity time.Duration) (*x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate serial number: %w", err)
+	}
+
+	keyUsage := x509.KeyUsageDigitalSignature
+	if alg.isRSA() {
+		keyUsage |= x509.KeyUsageKeyEncipherment
+	}
+
+	// certificate is not valid before 1 minute ago to tolerate clock skew
+	notBefore := time.Now().Add(-time.Minute)
+
+	return &x509.Certificate{
+		SerialNumber:          serial,
+		NotBefore:             notBefore,
+		NotAfter:              notBefore.Add(validity),
+		KeyUsage:              keyUsage,
+		BasicConstraintsValid: true,
 	}, nil
 }
 
@@ -102,3 +400,17 @@ func readFile(path string, elements ...string) ([]byte, error) {
 
 	return data, nil
 }
+
+// encodeKey encodes RSA keys as PKCS#1 and any other key as PKCS#8.
+func encodeKey(key crypto.Signer) ([]byte, error) {
+	if k, ok := key.(*rsa.PrivateKey); ok {
+		return pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(k)}), nil
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal private key: %w", err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
@@ -0,0 +1,65 @@
+package cert
+
+// Option is an interface for applying configuration to Options.
+type Option interface {
+	ApplyToOptions(opts *Options)
+}
+
+// optionFunc is an adapter that allows a simple function to be used as an Option.
+type optionFunc func(*Options)
+
+func (f optionFunc) ApplyToOptions(o *Options) {
+	f(o)
+}
+
+// Options contains configuration for certificate generation.
+type Options struct {
+	// CACert is the PEM encoded CA certificate used to sign the server certificate.
+	// If empty, a self-signed CA is generated.
+	CACert []byte
+
+	// CAKey is the PEM encoded private key of CACert.
+	CAKey []byte
+
+	// KeyAlgorithm is the algorithm used to generate the certificate keys.
+	// Default: RSA2048
+	KeyAlgorithm KeyAlgorithm
+}
+
+// ApplyOptions applies a list of Options to the Options.
+func (o *Options) ApplyOptions(opts []Option) *Options {
+	for _, opt := range opts {
+		opt.ApplyToOptions(o)
+	}
+	return o
+}
+
+// ApplyToOptions implements the Option interface, allowing Options
+// to be used directly as an option (struct style initialization).
+func (o *Options) ApplyToOptions(target *Options) {
+	if len(o.CACert) > 0 {
+		target.CACert = o.CACert
+	}
+	if len(o.CAKey) > 0 {
+		target.CAKey = o.CAKey
+	}
+	if o.KeyAlgorithm != "" {
+		target.KeyAlgorithm = o.KeyAlgorithm
+	}
+}
+
+// WithCA configures an existing CA to sign the server certificate instead of
+// generating a self-signed one.
+func WithCA(certPEM []byte, keyPEM []byte) Option {
+	return optionFunc(func(o *Options) {
+		o.CACert = certPEM
+		o.CAKey = keyPEM
+	})
+}
+
+// WithKeyAlgorithm configures the algorithm used to generate the certificate keys.
+func WithKeyAlgorithm(alg KeyAlgorithm) Option {
+	return optionFunc(func(o *Options) {
+		o.KeyAlgorithm = alg
+	})
+}
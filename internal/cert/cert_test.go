@@ -0,0 +1,317 @@
+package cert_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lburgazzoli/k3s-envtest/internal/cert"
+
+	. "github.com/onsi/gomega"
+)
+
+func newTestCA(t *testing.T, isCA bool) ([]byte, []byte) {
+	t.Helper()
+	g := NewWithT(t)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	template := x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  isCA,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, key.Public(), key)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func parseCertificate(t *testing.T, data []byte) *x509.Certificate {
+	t.Helper()
+	g := NewWithT(t)
+
+	block, _ := pem.Decode(data)
+	g.Expect(block).NotTo(BeNil())
+
+	c, err := x509.ParseCertificate(block.Bytes)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	return c
+}
+
+func TestNew(t *testing.T) {
+	g := NewWithT(t)
+
+	data, err := cert.New(t.TempDir(), time.Hour, []string{"localhost", "127.0.0.1"})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	caCert := parseCertificate(t, data.CACert)
+	g.Expect(caCert.IsCA).To(BeTrue())
+
+	serverCert := parseCertificate(t, data.ServerCert)
+	g.Expect(serverCert.DNSNames).To(ContainElement("localhost"))
+	g.Expect(serverCert.CheckSignatureFrom(caCert)).To(Succeed())
+}
+
+func TestNew_WithCA(t *testing.T) {
+	g := NewWithT(t)
+
+	caCertPEM, caKeyPEM := newTestCA(t, true)
+
+	data, err := cert.New(t.TempDir(), time.Hour, []string{"localhost", "127.0.0.1"}, cert.WithCA(caCertPEM, caKeyPEM))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(data.CACert).To(Equal(caCertPEM))
+	g.Expect(data.ServerKey).NotTo(BeEmpty())
+
+	serverCert := parseCertificate(t, data.ServerCert)
+	g.Expect(serverCert.DNSNames).To(ConsistOf("localhost"))
+	g.Expect(serverCert.IPAddresses).To(HaveLen(1))
+	g.Expect(serverCert.CheckSignatureFrom(parseCertificate(t, caCertPEM))).To(Succeed())
+}
+
+func TestNew_WithKeyAlgorithm(t *testing.T) {
+	expected := map[cert.KeyAlgorithm]x509.PublicKeyAlgorithm{
+		cert.RSA2048:  x509.RSA,
+		cert.RSA4096:  x509.RSA,
+		cert.ECDSA256: x509.ECDSA,
+		cert.ECDSA384: x509.ECDSA,
+		cert.Ed25519:  x509.Ed25519,
+	}
+
+	for alg, pka := range expected {
+		t.Run(string(alg), func(t *testing.T) {
+			g := NewWithT(t)
+
+			data, err := cert.New(t.TempDir(), time.Hour, []string{"localhost"}, cert.WithKeyAlgorithm(alg))
+			g.Expect(err).NotTo(HaveOccurred())
+
+			caCert := parseCertificate(t, data.CACert)
+			g.Expect(caCert.PublicKeyAlgorithm).To(Equal(pka))
+
+			serverCert := parseCertificate(t, data.ServerCert)
+			g.Expect(serverCert.PublicKeyAlgorithm).To(Equal(pka))
+			g.Expect(serverCert.CheckSignatureFrom(caCert)).To(Succeed())
+
+			_, err = tls.X509KeyPair(data.ServerCert, data.ServerKey)
+			g.Expect(err).NotTo(HaveOccurred())
+		})
+	}
+}
+
+func TestNew_InvalidKeyAlgorithm(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := cert.New(t.TempDir(), time.Hour, []string{"localhost"}, cert.WithKeyAlgorithm("DSA1024"))
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("unsupported key algorithm"))
+}
+
+func TestParseCA(t *testing.T) {
+	t.Run("valid CA", func(t *testing.T) {
+		g := NewWithT(t)
+
+		caCertPEM, caKeyPEM := newTestCA(t, true)
+
+		caCert, caKey, err := cert.ParseCA(caCertPEM, caKeyPEM)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(caCert.IsCA).To(BeTrue())
+		g.Expect(caKey).NotTo(BeNil())
+	})
+
+	t.Run("rejects non-CA certificate", func(t *testing.T) {
+		g := NewWithT(t)
+
+		certPEM, keyPEM := newTestCA(t, false)
+
+		_, _, err := cert.ParseCA(certPEM, keyPEM)
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("is not a CA"))
+	})
+
+	t.Run("rejects mismatched key", func(t *testing.T) {
+		g := NewWithT(t)
+
+		caCertPEM, _ := newTestCA(t, true)
+		_, otherKeyPEM := newTestCA(t, true)
+
+		_, _, err := cert.ParseCA(caCertPEM, otherKeyPEM)
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("failed to load CA key pair"))
+	})
+}
+
+func TestData_TLSConfig(t *testing.T) {
+	g := NewWithT(t)
+
+	data, err := cert.New(t.TempDir(), time.Hour, []string{"localhost"}, cert.WithKeyAlgorithm(cert.ECDSA256))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	pair, err := data.TLSCertificate()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(pair.Certificate).To(HaveLen(1))
+
+	cfg, err := data.TLSConfig()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cfg.Certificates).To(HaveLen(1))
+	g.Expect(cfg.RootCAs).NotTo(BeNil())
+
+	leaf := parseCertificate(t, data.ServerCert)
+	_, err = leaf.Verify(x509.VerifyOptions{DNSName: "localhost", Roots: cfg.RootCAs})
+	g.Expect(err).NotTo(HaveOccurred())
+}
+
+func TestData_TLSCertificate_Invalid(t *testing.T) {
+	g := NewWithT(t)
+
+	data := &cert.Data{ServerCert: []byte("invalid"), ServerKey: []byte("invalid")}
+
+	_, err := data.TLSCertificate()
+	g.Expect(err).To(HaveOccurred())
+
+	_, err = data.TLSConfig()
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestData_CACertPool(t *testing.T) {
+	g := NewWithT(t)
+
+	data, err := cert.New(t.TempDir(), time.Hour, []string{"localhost"})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	leaf := parseCertificate(t, data.ServerCert)
+
+	pool, err := data.CACertPool()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	_, err = leaf.Verify(x509.VerifyOptions{DNSName: "localhost", Roots: pool})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	systemPool, err := data.SystemPlusCACertPool()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	_, err = leaf.Verify(x509.VerifyOptions{DNSName: "localhost", Roots: systemPool})
+	g.Expect(err).NotTo(HaveOccurred())
+}
+
+func TestData_CACertPool_Invalid(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := (&cert.Data{}).CACertPool()
+	g.Expect(err).To(MatchError(ContainSubstring("CA certificate is empty")))
+
+	_, err = (&cert.Data{CACert: []byte("invalid")}).CACertPool()
+	g.Expect(err).To(MatchError(ContainSubstring("failed to parse CA certificate")))
+
+	_, err = (&cert.Data{CACert: []byte("invalid")}).SystemPlusCACertPool()
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestData_Validity(t *testing.T) {
+	g := NewWithT(t)
+
+	data, err := cert.New(t.TempDir(), time.Hour, []string{"localhost"})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	expiresAt, err := data.ExpiresAt()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(expiresAt).To(BeTemporally("~", time.Now().Add(time.Hour), 2*time.Minute))
+
+	g.Expect(data.IsValid()).To(BeTrue())
+	g.Expect(data.IsValidAt(expiresAt)).To(BeTrue())
+	g.Expect(data.IsValidAt(expiresAt.Add(time.Second))).To(BeFalse())
+	g.Expect(data.IsValidAt(time.Now().Add(-time.Hour))).To(BeFalse())
+}
+
+func TestData_Validity_Invalid(t *testing.T) {
+	g := NewWithT(t)
+
+	data := &cert.Data{ServerCert: []byte("invalid")}
+
+	_, err := data.ExpiresAt()
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(data.IsValid()).To(BeFalse())
+}
+
+func TestLoad(t *testing.T) {
+	g := NewWithT(t)
+
+	src, err := cert.New(t.TempDir(), time.Hour, []string{"localhost"})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	data, err := cert.Load(
+		filepath.Join(src.Path, cert.CertFileName),
+		filepath.Join(src.Path, cert.KeyFileName),
+		filepath.Join(src.Path, cert.CACertFileName),
+	)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(data.CACert).To(Equal(src.CACert))
+	g.Expect(data.ServerCert).To(Equal(src.ServerCert))
+	g.Expect(data.ServerKey).To(Equal(src.ServerKey))
+
+	dst := t.TempDir()
+	g.Expect(data.Write(dst)).To(Succeed())
+	g.Expect(data.Path).To(Equal(dst))
+	g.Expect(filepath.Join(dst, cert.CertFileName)).To(BeAnExistingFile())
+	g.Expect(filepath.Join(dst, cert.KeyFileName)).To(BeAnExistingFile())
+	g.Expect(filepath.Join(dst, cert.CACertFileName)).To(BeAnExistingFile())
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	src, err := cert.New(t.TempDir(), time.Hour, []string{"localhost"})
+	NewWithT(t).Expect(err).NotTo(HaveOccurred())
+
+	other, err := cert.New(t.TempDir(), time.Hour, []string{"localhost"})
+	NewWithT(t).Expect(err).NotTo(HaveOccurred())
+
+	t.Run("missing file", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := cert.Load(
+			filepath.Join(src.Path, "missing.pem"),
+			filepath.Join(src.Path, cert.KeyFileName),
+			filepath.Join(src.Path, cert.CACertFileName),
+		)
+		g.Expect(err).To(MatchError(ContainSubstring("failed to read server cert")))
+	})
+
+	t.Run("mismatched key", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := cert.Load(
+			filepath.Join(src.Path, cert.CertFileName),
+			filepath.Join(other.Path, cert.KeyFileName),
+			filepath.Join(src.Path, cert.CACertFileName),
+		)
+		g.Expect(err).To(MatchError(ContainSubstring("failed to load server key pair")))
+	})
+
+	t.Run("untrusted CA", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := cert.Load(
+			filepath.Join(src.Path, cert.CertFileName),
+			filepath.Join(src.Path, cert.KeyFileName),
+			filepath.Join(other.Path, cert.CACertFileName),
+		)
+		g.Expect(err).To(MatchError(ContainSubstring("not signed by the CA")))
+	})
+}
@@ -0,0 +1,70 @@
+package cert
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+)
+
+// KeyAlgorithm identifies the algorithm used to generate certificate keys.
+type KeyAlgorithm string
+
+const (
+	RSA2048  KeyAlgorithm = "RSA2048"
+	RSA4096  KeyAlgorithm = "RSA4096"
+	ECDSA256 KeyAlgorithm = "ECDSA256"
+	ECDSA384 KeyAlgorithm = "ECDSA384"
+	Ed25519  KeyAlgorithm = "Ed25519"
+
+	// DefaultKeyAlgorithm is the key algorithm used when none is configured.
+	DefaultKeyAlgorithm = RSA2048
+)
+
+// KeyAlgorithms lists all supported key algorithms.
+var KeyAlgorithms = []KeyAlgorithm{RSA2048, RSA4096, ECDSA256, ECDSA384, Ed25519}
+
+// Validate returns an error if the key algorithm is not supported.
+func (a KeyAlgorithm) Validate() error {
+	switch a {
+	case RSA2048, RSA4096, ECDSA256, ECDSA384, Ed25519:
+		return nil
+	default:
+		return fmt.Errorf("unsupported key algorithm %q, must be one of %v", a, KeyAlgorithms)
+	}
+}
+
+func (a KeyAlgorithm) isRSA() bool {
+	return a == RSA2048 || a == RSA4096
+}
+
+func (a KeyAlgorithm) generateKey() (crypto.Signer, error) {
+	var (
+		key crypto.Signer
+		err error
+	)
+
+	switch a {
+	case RSA2048:
+		key, err = rsa.GenerateKey(rand.Reader, 2048)
+	case RSA4096:
+		key, err = rsa.GenerateKey(rand.Reader, 4096)
+	case ECDSA256:
+		key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case ECDSA384:
+		key, err = ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	case Ed25519:
+		_, key, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return nil, a.Validate()
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate %s key: %w", a, err)
+	}
+
+	return key, nil
+}
@@ -1,6 +1,16 @@
 package gvk
 
-import "k8s.io/apimachinery/pkg/runtime/schema"
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
 
 var (
 	CustomResourceDefinition = schema.GroupVersionKind{
@@ -21,9 +31,167 @@ var (
 		Kind:    "ValidatingWebhookConfiguration",
 	}
 
+	ValidatingAdmissionPolicy = schema.GroupVersionKind{
+		Group:   "admissionregistration.k8s.io",
+		Version: "v1",
+		Kind:    "ValidatingAdmissionPolicy",
+	}
+
+	ValidatingAdmissionPolicyBinding = schema.GroupVersionKind{
+		Group:   "admissionregistration.k8s.io",
+		Version: "v1",
+		Kind:    "ValidatingAdmissionPolicyBinding",
+	}
+
 	AdmissionReview = schema.GroupVersionKind{
 		Group:   "admission.k8s.io",
 		Version: "v1",
 		Kind:    "AdmissionReview",
 	}
+
+	Pod = schema.GroupVersionKind{
+		Group:   "",
+		Version: "v1",
+		Kind:    "Pod",
+	}
+
+	Service = schema.GroupVersionKind{
+		Group:   "",
+		Version: "v1",
+		Kind:    "Service",
+	}
+
+	ConfigMap = schema.GroupVersionKind{
+		Group:   "",
+		Version: "v1",
+		Kind:    "ConfigMap",
+	}
+
+	Secret = schema.GroupVersionKind{
+		Group:   "",
+		Version: "v1",
+		Kind:    "Secret",
+	}
+
+	Namespace = schema.GroupVersionKind{
+		Group:   "",
+		Version: "v1",
+		Kind:    "Namespace",
+	}
+
+	ServiceAccount = schema.GroupVersionKind{
+		Group:   "",
+		Version: "v1",
+		Kind:    "ServiceAccount",
+	}
+
+	PersistentVolumeClaim = schema.GroupVersionKind{
+		Group:   "",
+		Version: "v1",
+		Kind:    "PersistentVolumeClaim",
+	}
+
+	Deployment = schema.GroupVersionKind{
+		Group:   "apps",
+		Version: "v1",
+		Kind:    "Deployment",
+	}
+
+	StatefulSet = schema.GroupVersionKind{
+		Group:   "apps",
+		Version: "v1",
+		Kind:    "StatefulSet",
+	}
+
+	DaemonSet = schema.GroupVersionKind{
+		Group:   "apps",
+		Version: "v1",
+		Kind:    "DaemonSet",
+	}
+
+	Job = schema.GroupVersionKind{
+		Group:   "batch",
+		Version: "v1",
+		Kind:    "Job",
+	}
+
+	Role = schema.GroupVersionKind{
+		Group:   "rbac.authorization.k8s.io",
+		Version: "v1",
+		Kind:    "Role",
+	}
+
+	RoleBinding = schema.GroupVersionKind{
+		Group:   "rbac.authorization.k8s.io",
+		Version: "v1",
+		Kind:    "RoleBinding",
+	}
+
+	ClusterRole = schema.GroupVersionKind{
+		Group:   "rbac.authorization.k8s.io",
+		Version: "v1",
+		Kind:    "ClusterRole",
+	}
+
+	ClusterRoleBinding = schema.GroupVersionKind{
+		Group:   "rbac.authorization.k8s.io",
+		Version: "v1",
+		Kind:    "ClusterRoleBinding",
+	}
 )
+
+// FromObject returns the GroupVersionKind of obj. If obj has no apiVersion and
+// kind set, as it is usually the case for typed objects, it is looked up in the
+// scheme.
+func FromObject(scheme *runtime.Scheme, obj runtime.Object) (schema.GroupVersionKind, error) {
+	if obj == nil {
+		return schema.GroupVersionKind{}, errors.New("nil object")
+	}
+
+	if objGVK := obj.GetObjectKind().GroupVersionKind(); objGVK.Version != "" && objGVK.Kind != "" {
+		return objGVK, nil
+	}
+
+	objGVK, err := apiutil.GVKForObject(obj, scheme)
+	if err != nil {
+		return schema.GroupVersionKind{}, fmt.Errorf("failed to get GVK: %w", err)
+	}
+
+	return objGVK, nil
+}
+
+// FromGVR returns the GroupVersionKind of the given GroupVersionResource
+// according to the mapper.
+func FromGVR(mapper meta.RESTMapper, gvr schema.GroupVersionResource) (schema.GroupVersionKind, error) {
+	objGVK, err := mapper.KindFor(gvr)
+	if err != nil {
+		return schema.GroupVersionKind{}, fmt.Errorf("failed to get GVK for resource %s: %w", gvr.String(), err)
+	}
+
+	return objGVK, nil
+}
+
+// FromString parses a GroupVersionKind in the group/version/Kind form, or
+// version/Kind for the core group, e.g. "apps/v1/Deployment" or "v1/Pod".
+func FromString(s string) (schema.GroupVersionKind, error) {
+	parts := strings.Split(s, "/")
+
+	var objGVK schema.GroupVersionKind
+	switch len(parts) {
+	case 2:
+		objGVK = schema.GroupVersionKind{Version: parts[0], Kind: parts[1]}
+	case 3:
+		objGVK = schema.GroupVersionKind{Group: parts[0], Version: parts[1], Kind: parts[2]}
+		if objGVK.Group == "" {
+			return schema.GroupVersionKind{}, fmt.Errorf("invalid GVK %q: empty group", s)
+		}
+	default:
+		return schema.GroupVersionKind{}, fmt.Errorf("invalid GVK %q: expected group/version/Kind or version/Kind", s)
+	}
+
+	if objGVK.Version == "" || objGVK.Kind == "" {
+		return schema.GroupVersionKind{}, fmt.Errorf("invalid GVK %q: empty version or kind", s)
+	}
+
+	return objGVK, nil
+}
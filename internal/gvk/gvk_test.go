@@ -0,0 +1,85 @@
+package gvk_test
+
+import (
+	"testing"
+
+	"github.com/lburgazzoli/k3s-envtest/internal/gvk"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestFromObject(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(corev1.AddToScheme(scheme)).To(Succeed())
+
+	objGVK, err := gvk.FromObject(scheme, &corev1.Pod{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(objGVK).To(Equal(gvk.Pod))
+
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(gvk.Deployment)
+
+	objGVK, err = gvk.FromObject(scheme, u)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(objGVK).To(Equal(gvk.Deployment))
+
+	_, err = gvk.FromObject(scheme, &appsv1.Deployment{})
+	g.Expect(err).To(HaveOccurred())
+
+	_, err = gvk.FromObject(scheme, nil)
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestFromGVR(t *testing.T) {
+	g := NewWithT(t)
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(gvk.Deployment, meta.RESTScopeNamespace)
+
+	objGVK, err := gvk.FromGVR(mapper, schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(objGVK).To(Equal(gvk.Deployment))
+
+	_, err = gvk.FromGVR(mapper, schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "statefulsets"})
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestFromString(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    schema.GroupVersionKind
+		wantErr bool
+	}{
+		{name: "group version kind", input: "apps/v1/Deployment", want: gvk.Deployment},
+		{name: "core group", input: "v1/Pod", want: gvk.Pod},
+		{name: "missing kind", input: "v1", wantErr: true},
+		{name: "empty kind", input: "apps/v1/", wantErr: true},
+		{name: "empty group", input: "/v1/Pod", wantErr: true},
+		{name: "too many parts", input: "a/b/c/d", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			objGVK, err := gvk.FromString(tt.input)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(objGVK).To(Equal(tt.want))
+		})
+	}
+}
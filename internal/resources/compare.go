@@ -0,0 +1,92 @@
+package resources
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// DefaultIgnorePaths lists the server-side metadata fields ignored by
+// CompareObjects and EqualIgnoringMetadata.
+var DefaultIgnorePaths = []string{
+	".metadata.resourceVersion",
+	".metadata.generation",
+	".metadata.managedFields",
+	".metadata.uid",
+	".metadata.creationTimestamp",
+	".metadata.selfLink",
+}
+
+// CompareObjects reports whether a and b are semantically equal once the
+// DefaultIgnorePaths and the given additional paths have been removed from
+// both. Paths use a jq-like syntax: ".spec.replicas", keys containing dots
+// can be quoted as in `.metadata.annotations["example.com/key"]`.
+func CompareObjects(a *unstructured.Unstructured, b *unstructured.Unstructured, ignorePaths ...string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	paths := make([]string, 0, len(DefaultIgnorePaths)+len(ignorePaths))
+	paths = append(paths, DefaultIgnorePaths...)
+	paths = append(paths, ignorePaths...)
+
+	return equality.Semantic.DeepEqual(
+		stripPaths(a, paths),
+		stripPaths(b, paths),
+	)
+}
+
+// EqualIgnoringMetadata reports whether a and b are semantically equal,
+// ignoring the server-side metadata fields listed in DefaultIgnorePaths.
+func EqualIgnoringMetadata(a *unstructured.Unstructured, b *unstructured.Unstructured) bool {
+	return CompareObjects(a, b)
+}
+
+func stripPaths(u *unstructured.Unstructured, paths []string) map[string]any {
+	stripped := u.DeepCopy()
+
+	for _, path := range paths {
+		if fields := parsePath(path); len(fields) > 0 {
+			unstructured.RemoveNestedField(stripped.Object, fields...)
+		}
+	}
+
+	return stripped.Object
+}
+
+// parsePath splits a jq-like path such as `.metadata.annotations["a.b/c"]`
+// into its fields.
+func parsePath(path string) []string {
+	var fields []string
+
+	for path != "" {
+		switch {
+		case strings.HasPrefix(path, "."):
+			path = path[1:]
+		case strings.HasPrefix(path, "["):
+			end := strings.Index(path, "]")
+			if end < 0 {
+				end = len(path)
+			}
+			fields = append(fields, strings.Trim(path[1:end], `"`))
+			path = path[min(end+1, len(path)):]
+		case strings.HasPrefix(path, `"`):
+			end := strings.Index(path[1:], `"`)
+			if end < 0 {
+				return append(fields, path[1:])
+			}
+			fields = append(fields, path[1:end+1])
+			path = path[end+2:]
+		default:
+			end := strings.IndexAny(path, ".[")
+			if end < 0 {
+				end = len(path)
+			}
+			fields = append(fields, path[:end])
+			path = path[end:]
+		}
+	}
+
+	return fields
+}
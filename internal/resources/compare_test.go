@@ -0,0 +1,86 @@
+package resources_test
+
+import (
+	"testing"
+
+	"github.com/lburgazzoli/k3s-envtest/internal/resources"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	. "github.com/onsi/gomega"
+)
+
+func newCompareObject() *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]any{
+			"name":      "test",
+			"namespace": "default",
+			"annotations": map[string]any{
+				"example.com/owner": "team",
+			},
+		},
+		"data": map[string]any{
+			"key": "value",
+		},
+	}}
+}
+
+func newServerObject() *unstructured.Unstructured {
+	u := newCompareObject()
+	u.SetResourceVersion("12345")
+	u.SetGeneration(3)
+	u.SetUID("6f1c7b0e-0000-0000-0000-000000000000")
+	_ = unstructured.SetNestedField(u.Object, "2025-01-01T00:00:00Z", "metadata", "creationTimestamp")
+	_ = unstructured.SetNestedSlice(u.Object, []any{
+		map[string]any{"manager": "k3s-envtest", "operation": "Apply"},
+	}, "metadata", "managedFields")
+
+	return u
+}
+
+func TestEqualIgnoringMetadata(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(resources.EqualIgnoringMetadata(newCompareObject(), newServerObject())).To(BeTrue())
+
+	changed := newServerObject()
+	_ = unstructured.SetNestedField(changed.Object, "other", "data", "key")
+
+	g.Expect(resources.EqualIgnoringMetadata(newCompareObject(), changed)).To(BeFalse())
+}
+
+func TestCompareObjects_IgnorePaths(t *testing.T) {
+	g := NewWithT(t)
+
+	actual := newServerObject()
+	_ = unstructured.SetNestedField(actual.Object, "other", "data", "key")
+	_ = unstructured.SetNestedField(actual.Object, "someone", "metadata", "annotations", "example.com/owner")
+
+	g.Expect(resources.CompareObjects(newCompareObject(), actual)).To(BeFalse())
+	g.Expect(resources.CompareObjects(newCompareObject(), actual, ".data.key")).To(BeFalse())
+	g.Expect(resources.CompareObjects(
+		newCompareObject(),
+		actual,
+		".data.key",
+		`.metadata.annotations["example.com/owner"]`,
+	)).To(BeTrue())
+}
+
+func TestCompareObjects_DoesNotModifyInputs(t *testing.T) {
+	g := NewWithT(t)
+
+	actual := newServerObject()
+
+	g.Expect(resources.CompareObjects(newCompareObject(), actual, ".data")).To(BeTrue())
+	g.Expect(actual.GetResourceVersion()).To(Equal("12345"))
+	g.Expect(actual.Object).To(HaveKey("data"))
+}
+
+func TestCompareObjects_Nil(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(resources.CompareObjects(nil, nil)).To(BeTrue())
+	g.Expect(resources.CompareObjects(newCompareObject(), nil)).To(BeFalse())
+}
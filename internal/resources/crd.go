@@ -9,6 +9,7 @@ import (
 
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
@@ -86,6 +87,35 @@ func WaitForCRDEstablished(
 	return nil
 }
 
+// WaitForCRDDeleted polls until a CRD no longer exists or the timeout is reached.
+func WaitForCRDDeleted(
+	ctx context.Context,
+	cli client.Client,
+	crdName string,
+	pollInterval time.Duration,
+	timeout time.Duration,
+) error {
+	err := wait.PollUntilContextTimeout(ctx, pollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+		crd := apiextensionsv1.CustomResourceDefinition{}
+
+		err := cli.Get(ctx, types.NamespacedName{Name: crdName}, &crd)
+		switch {
+		case k8serr.IsNotFound(err):
+			return true, nil
+		case err != nil:
+			return false, fmt.Errorf("failed to get CRD: %w", err)
+		default:
+			return false, nil
+		}
+	})
+
+	if err != nil {
+		return fmt.Errorf("CRD %s not deleted: %w", crdName, err)
+	}
+
+	return nil
+}
+
 // PatchCRDConversion patches a CustomResourceDefinition to use webhook-based conversion.
 // It modifies the CRD in-place.
 func PatchCRDConversion(
@@ -104,3 +134,103 @@ func PatchCRDConversion(
 		},
 	}
 }
+
+// GetCRDVersions returns the names of the versions served by a CRD, in the
+// order they are declared. The CRD can be either a typed CustomResourceDefinition
+// or an unstructured one, e.g. loaded from YAML.
+func GetCRDVersions(crd client.Object) ([]string, error) {
+	typed, err := toCRD(crd)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]string, 0, len(typed.Spec.Versions))
+	for _, v := range typed.Spec.Versions {
+		if v.Served {
+			versions = append(versions, v.Name)
+		}
+	}
+
+	return versions, nil
+}
+
+// GetStoredVersion returns the name of the storage version of a CRD. The CRD
+// can be either a typed CustomResourceDefinition or an unstructured one.
+func GetStoredVersion(crd client.Object) (string, error) {
+	typed, err := toCRD(crd)
+	if err != nil {
+		return "", err
+	}
+
+	stored := ""
+	for _, v := range typed.Spec.Versions {
+		if !v.Storage {
+			continue
+		}
+		if stored != "" {
+			return "", fmt.Errorf("CRD %s has multiple storage versions: %s, %s", typed.GetName(), stored, v.Name)
+		}
+		stored = v.Name
+	}
+
+	if stored == "" {
+		return "", fmt.Errorf("CRD %s has no storage version", typed.GetName())
+	}
+
+	return stored, nil
+}
+
+// HasConversionWebhook reports whether a CRD uses webhook-based conversion.
+// The CRD can be either a typed CustomResourceDefinition or an unstructured one.
+func HasConversionWebhook(crd client.Object) bool {
+	typed, err := toCRD(crd)
+	if err != nil {
+		return false
+	}
+
+	return typed.Spec.Conversion != nil && typed.Spec.Conversion.Strategy == apiextensionsv1.WebhookConverter
+}
+
+// GetConversionURL returns the URL of the conversion webhook of a CRD, as set
+// by PatchCRDConversion. The CRD can be either a typed CustomResourceDefinition
+// or an unstructured one.
+func GetConversionURL(crd client.Object) (string, error) {
+	typed, err := toCRD(crd)
+	if err != nil {
+		return "", err
+	}
+
+	conversion := typed.Spec.Conversion
+	if conversion == nil || conversion.Webhook == nil || conversion.Webhook.ClientConfig == nil {
+		return "", fmt.Errorf("CRD %s has no conversion webhook client config", typed.GetName())
+	}
+
+	urlStr := ptr.Deref(conversion.Webhook.ClientConfig.URL, "")
+	if urlStr == "" {
+		return "", fmt.Errorf("CRD %s has no conversion webhook URL", typed.GetName())
+	}
+
+	return urlStr, nil
+}
+
+// toCRD returns obj as a typed CustomResourceDefinition, converting it
+// if it is unstructured.
+func toCRD(obj client.Object) (*apiextensionsv1.CustomResourceDefinition, error) {
+	switch crd := obj.(type) {
+	case *apiextensionsv1.CustomResourceDefinition:
+		return crd, nil
+	case *unstructured.Unstructured:
+		if !IsCRD(crd) {
+			return nil, fmt.Errorf("object %s is not a CustomResourceDefinition", FormatObjectReference(crd))
+		}
+
+		typed := apiextensionsv1.CustomResourceDefinition{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(crd.Object, &typed); err != nil {
+			return nil, fmt.Errorf("failed to convert CRD %s: %w", crd.GetName(), err)
+		}
+
+		return &typed, nil
+	default:
+		return nil, fmt.Errorf("unsupported CRD type: %T", obj)
+	}
+}
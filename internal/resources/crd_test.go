@@ -11,8 +11,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
@@ -313,12 +315,40 @@ func TestWaitForCRDEstablished_Timeout(t *testing.T) {
 	g.Expect(err.Error()).To(ContainSubstring("not established"))
 }
 
+func TestWaitForCRDDeleted_AlreadyDeleted(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	cli := &fakeCRDClient{}
+
+	err := resources.WaitForCRDDeleted(ctx, cli, "test.example.com", time.Millisecond, 10*time.Millisecond)
+	g.Expect(err).NotTo(HaveOccurred())
+}
+
+func TestWaitForCRDDeleted_Timeout(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	cli := &fakeCRDClient{crd: &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test.example.com",
+		},
+	}}
+
+	err := resources.WaitForCRDDeleted(ctx, cli, "test.example.com", time.Millisecond, 10*time.Millisecond)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("not deleted"))
+}
+
 type fakeCRDClient struct {
 	crd *apiextensionsv1.CustomResourceDefinition
 }
 
 func (f *fakeCRDClient) Get(ctx context.Context, key types.NamespacedName, obj client.Object, opts ...client.GetOption) error {
 	if crd, ok := obj.(*apiextensionsv1.CustomResourceDefinition); ok {
+		if f.crd == nil {
+			return k8serr.NewNotFound(apiextensionsv1.Resource("customresourcedefinitions"), key.Name)
+		}
 		f.crd.DeepCopyInto(crd)
 		return nil
 	}
@@ -423,3 +453,125 @@ func TestPatchCRDConversion_Success(t *testing.T) {
 	g.Expect(*crd.Spec.Conversion.Webhook.ClientConfig.URL).To(Equal(testBaseURL + "/convert"))
 	g.Expect(crd.Spec.Conversion.Webhook.ClientConfig.CABundle).To(Equal(testCABundleBytes))
 }
+
+const testVersionedCRDYAML = `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: examples.test.example.com
+spec:
+  group: test.example.com
+  names:
+    kind: Example
+    plural: examples
+  scope: Namespaced
+  versions:
+  - name: v1
+    served: true
+    storage: true
+  - name: v1beta1
+    served: true
+    storage: false
+  - name: v1alpha1
+    served: false
+    storage: false
+`
+
+func TestGetCRDVersions(t *testing.T) {
+	g := NewWithT(t)
+
+	u, err := resources.YAMLToUnstructured(testVersionedCRDYAML)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	typed := apiextensionsv1.CustomResourceDefinition{}
+	err = runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, &typed)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	for _, crd := range []client.Object{u, &typed} {
+		versions, err := resources.GetCRDVersions(crd)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(versions).To(Equal([]string{"v1", "v1beta1"}))
+
+		stored, err := resources.GetStoredVersion(crd)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(stored).To(Equal("v1"))
+	}
+}
+
+func TestGetStoredVersion_NoStorageVersion(t *testing.T) {
+	g := NewWithT(t)
+
+	crd := &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "examples.test.example.com",
+		},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{Name: "v1", Served: true},
+			},
+		},
+	}
+
+	_, err := resources.GetStoredVersion(crd)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("no storage version"))
+}
+
+func TestGetCRDVersions_NotCRD(t *testing.T) {
+	g := NewWithT(t)
+
+	u, err := resources.YAMLToUnstructured("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: test\n")
+	g.Expect(err).NotTo(HaveOccurred())
+
+	_, err = resources.GetCRDVersions(u)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("not a CustomResourceDefinition"))
+}
+
+func TestHasConversionWebhook(t *testing.T) {
+	g := NewWithT(t)
+
+	crd := &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "examples.test.example.com",
+		},
+	}
+
+	g.Expect(resources.HasConversionWebhook(crd)).To(BeFalse())
+
+	_, err := resources.GetConversionURL(crd)
+	g.Expect(err).To(HaveOccurred())
+
+	resources.PatchCRDConversion(crd, testBaseURL, testCABundleBytes)
+
+	g.Expect(resources.HasConversionWebhook(crd)).To(BeTrue())
+
+	conversionURL, err := resources.GetConversionURL(crd)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(conversionURL).To(Equal(testBaseURL + "/convert"))
+}
+
+func TestHasConversionWebhook_Unstructured(t *testing.T) {
+	g := NewWithT(t)
+
+	u, err := resources.YAMLToUnstructured(testVersionedCRDYAML)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(resources.HasConversionWebhook(u)).To(BeFalse())
+
+	err = unstructured.SetNestedMap(u.Object, map[string]any{
+		"strategy": "Webhook",
+		"webhook": map[string]any{
+			"conversionReviewVersions": []any{"v1"},
+			"clientConfig": map[string]any{
+				"url": testBaseURL + "/convert",
+			},
+		},
+	}, "spec", "conversion")
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(resources.HasConversionWebhook(u)).To(BeTrue())
+
+	conversionURL, err := resources.GetConversionURL(u)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(conversionURL).To(Equal(testBaseURL + "/convert"))
+}
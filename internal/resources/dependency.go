@@ -0,0 +1,139 @@
+package resources
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/lburgazzoli/k3s-envtest/internal/gvk"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// DependencySort returns a copy of the given manifests sorted so that every
+// manifest comes after the manifests it depends on. A manifest depends on:
+//   - the Namespace it belongs to
+//   - the CRD defining its kind
+//   - the manifests referenced by its owner references
+//   - the Role or ClusterRole referenced by the roleRef of a RoleBinding or
+//     ClusterRoleBinding
+//
+// Dependencies not included in the manifests are ignored. Manifests without a
+// dependency relation keep the order of SortByInstallOrder. An error is returned
+// if the dependencies form a cycle.
+func DependencySort(manifests []unstructured.Unstructured) ([]unstructured.Unstructured, error) {
+	nodes := SortByInstallOrder(manifests)
+
+	// dependents[i] holds the indexes of the nodes depending on node i
+	dependents := make([][]int, len(nodes))
+	inDegree := make([]int, len(nodes))
+
+	for i := range nodes {
+		for j := range nodes {
+			if i == j || !dependsOn(nodes[j], nodes[i]) {
+				continue
+			}
+
+			dependents[i] = append(dependents[i], j)
+			inDegree[j]++
+		}
+	}
+
+	ready := make([]int, 0, len(nodes))
+	for i, d := range inDegree {
+		if d == 0 {
+			ready = append(ready, i)
+		}
+	}
+
+	sorted := make([]unstructured.Unstructured, 0, len(nodes))
+	for len(ready) > 0 {
+		// always pick the first ready node in install order, so that
+		// the result is deterministic
+		slices.Sort(ready)
+
+		i := ready[0]
+		ready = ready[1:]
+
+		sorted = append(sorted, nodes[i])
+
+		for _, j := range dependents[i] {
+			inDegree[j]--
+			if inDegree[j] == 0 {
+				ready = append(ready, j)
+			}
+		}
+	}
+
+	if len(sorted) != len(nodes) {
+		var cycle []string
+		for i, d := range inDegree {
+			if d > 0 {
+				cycle = append(cycle, FormatObjectReference(&nodes[i]))
+			}
+		}
+
+		return nil, fmt.Errorf("dependency cycle detected between: %s", strings.Join(cycle, ", "))
+	}
+
+	return sorted, nil
+}
+
+// dependsOn reports whether obj must be installed after dep.
+func dependsOn(obj unstructured.Unstructured, dep unstructured.Unstructured) bool {
+	depGK := dep.GroupVersionKind().GroupKind()
+	objGK := obj.GroupVersionKind().GroupKind()
+
+	switch depGK {
+	case gvk.Namespace.GroupKind():
+		if obj.GetNamespace() != "" && obj.GetNamespace() == dep.GetName() {
+			return true
+		}
+	case gvk.CustomResourceDefinition.GroupKind():
+		group, _, _ := unstructured.NestedString(dep.Object, "spec", "group")
+		kind, _, _ := unstructured.NestedString(dep.Object, "spec", "names", "kind")
+		if kind != "" && objGK == (schema.GroupKind{Group: group, Kind: kind}) {
+			return true
+		}
+	}
+
+	for _, ref := range obj.GetOwnerReferences() {
+		gv, err := schema.ParseGroupVersion(ref.APIVersion)
+		if err != nil {
+			continue
+		}
+
+		if depGK == gv.WithKind(ref.Kind).GroupKind() &&
+			dep.GetName() == ref.Name &&
+			(dep.GetNamespace() == "" || dep.GetNamespace() == obj.GetNamespace()) {
+			return true
+		}
+	}
+
+	if objGK == gvk.RoleBinding.GroupKind() || objGK == gvk.ClusterRoleBinding.GroupKind() {
+		return referencesRole(obj, dep)
+	}
+
+	return false
+}
+
+// referencesRole reports whether the roleRef of the RoleBinding or
+// ClusterRoleBinding binding references role.
+func referencesRole(binding unstructured.Unstructured, role unstructured.Unstructured) bool {
+	kind, _, _ := unstructured.NestedString(binding.Object, "roleRef", "kind")
+	name, _, _ := unstructured.NestedString(binding.Object, "roleRef", "name")
+
+	if name != role.GetName() {
+		return false
+	}
+
+	switch role.GroupVersionKind().GroupKind() {
+	case gvk.ClusterRole.GroupKind():
+		return kind == gvk.ClusterRole.Kind
+	case gvk.Role.GroupKind():
+		return kind == gvk.Role.Kind && role.GetNamespace() == binding.GetNamespace()
+	default:
+		return false
+	}
+}
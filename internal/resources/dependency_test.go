@@ -0,0 +1,98 @@
+package resources_test
+
+import (
+	"testing"
+
+	"github.com/lburgazzoli/k3s-envtest/internal/gvk"
+	"github.com/lburgazzoli/k3s-envtest/internal/resources"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	. "github.com/onsi/gomega"
+)
+
+var widgetGVK = schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}
+
+func names(manifests []unstructured.Unstructured) []string {
+	result := make([]string, 0, len(manifests))
+	for _, m := range manifests {
+		result = append(result, m.GetName())
+	}
+
+	return result
+}
+
+func TestDependencySort(t *testing.T) {
+	g := NewWithT(t)
+
+	crd := newManifest(gvk.CustomResourceDefinition, "", "widgets.example.com")
+	crd.Object["spec"] = map[string]any{
+		"group": "example.com",
+		"names": map[string]any{"kind": "Widget"},
+	}
+
+	binding := newManifest(gvk.RoleBinding, "test", "a-binding")
+	binding.Object["roleRef"] = map[string]any{
+		"apiGroup": "rbac.authorization.k8s.io",
+		"kind":     "Role",
+		"name":     "z-role",
+	}
+
+	owned := newManifest(gvk.ConfigMap, "test", "a-owned")
+	owned.SetOwnerReferences([]metav1.OwnerReference{{
+		APIVersion: "example.com/v1",
+		Kind:       "Widget",
+		Name:       "z-widget",
+	}})
+
+	sorted, err := resources.DependencySort([]unstructured.Unstructured{
+		owned,
+		newManifest(widgetGVK, "test", "z-widget"),
+		binding,
+		newManifest(gvk.Role, "test", "z-role"),
+		crd,
+		newManifest(gvk.Namespace, "", "test"),
+	})
+
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(names(sorted)).To(Equal([]string{
+		"test",
+		"widgets.example.com",
+		"z-role",
+		"a-binding",
+		"z-widget",
+		"a-owned",
+	}))
+}
+
+func TestDependencySort_NoDependencies(t *testing.T) {
+	g := NewWithT(t)
+
+	manifests := []unstructured.Unstructured{
+		newManifest(gvk.ConfigMap, "default", "cm"),
+		newManifest(gvk.CustomResourceDefinition, "", "crd"),
+	}
+
+	sorted, err := resources.DependencySort(manifests)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(sorted).To(Equal(resources.SortByInstallOrder(manifests)))
+}
+
+func TestDependencySort_Cycle(t *testing.T) {
+	g := NewWithT(t)
+
+	a := newManifest(gvk.ConfigMap, "default", "a")
+	a.SetOwnerReferences([]metav1.OwnerReference{{APIVersion: "v1", Kind: "ConfigMap", Name: "b"}})
+
+	b := newManifest(gvk.ConfigMap, "default", "b")
+	b.SetOwnerReferences([]metav1.OwnerReference{{APIVersion: "v1", Kind: "ConfigMap", Name: "a"}})
+
+	_, err := resources.DependencySort([]unstructured.Unstructured{a, b, newManifest(gvk.ConfigMap, "default", "c")})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("dependency cycle detected"))
+	g.Expect(err.Error()).To(ContainSubstring("default/a"))
+	g.Expect(err.Error()).To(ContainSubstring("default/b"))
+	g.Expect(err.Error()).NotTo(ContainSubstring("default/c"))
+}
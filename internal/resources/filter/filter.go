@@ -1,8 +1,13 @@
 package filter
 
 import (
+	"github.com/lburgazzoli/k3s-envtest/internal/gvk"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/sets"
 )
@@ -90,3 +95,180 @@ func ByType(gvks ...schema.GroupVersionKind) ObjectFilter {
 		return gvkSet.Has(obj.GetObjectKind().GroupVersionKind())
 	}
 }
+
+// ByClusterScoped creates a filter that accepts only cluster-scoped objects.
+//
+// Unstructured objects are checked structurally: an object is cluster-scoped
+// when its namespace is empty. Typed objects are resolved through the optional
+// RESTMapper when one is provided and the object's GVK is known to it, falling
+// back to the structural check otherwise.
+//
+// Usage:
+//
+//	// Structural check only
+//	clusterScoped := ByClusterScoped()
+//
+//	// Stricter check for typed objects
+//	crds := All(ByType(gvk.CustomResourceDefinition), ByClusterScoped(mapper))
+func ByClusterScoped(mapper ...meta.RESTMapper) ObjectFilter {
+	return func(obj client.Object) bool {
+		return isClusterScoped(obj, mapper)
+	}
+}
+
+// ByNamespaceScoped creates a filter that accepts only namespace-scoped objects.
+// It is the inverse of ByClusterScoped and accepts the same optional RESTMapper.
+//
+// Usage:
+//
+//	namespaced := ByNamespaceScoped()
+func ByNamespaceScoped(mapper ...meta.RESTMapper) ObjectFilter {
+	return Negate(ByClusterScoped(mapper...))
+}
+
+// isClusterScoped reports whether the object is cluster-scoped, consulting the
+// first non-nil RESTMapper for typed objects.
+func isClusterScoped(obj client.Object, mappers []meta.RESTMapper) bool {
+	if _, ok := obj.(*unstructured.Unstructured); ok {
+		return obj.GetNamespace() == ""
+	}
+
+	objGVK := obj.GetObjectKind().GroupVersionKind()
+
+	for _, mapper := range mappers {
+		if mapper == nil {
+			continue
+		}
+
+		if !objGVK.Empty() {
+			if mapping, err := mapper.RESTMapping(objGVK.GroupKind(), objGVK.Version); err == nil {
+				return mapping.Scope.Name() == meta.RESTScopeNameRoot
+			}
+		}
+
+		break
+	}
+
+	return obj.GetNamespace() == ""
+}
+
+// ByStoredVersion creates a filter that accepts CRDs declaring the given version
+// as the storage version (spec.versions[].storage == true).
+// Non-CRD objects are always accepted so the filter composes with All().
+//
+// Usage:
+//
+//	filter := All(
+//	    ByType(gvk.CustomResourceDefinition),
+//	    ByStoredVersion("v1beta1"),
+//	)
+func ByStoredVersion(version string) ObjectFilter {
+	return byCRDVersion(func(v apiextensionsv1.CustomResourceDefinitionVersion) bool {
+		return v.Name == version && v.Storage
+	})
+}
+
+// ByServedVersion creates a filter that accepts CRDs serving the given version
+// (spec.versions[].served == true).
+// Non-CRD objects are always accepted so the filter composes with All().
+//
+// Usage:
+//
+//	filter := ByServedVersion("v1alpha1")
+func ByServedVersion(version string) ObjectFilter {
+	return byCRDVersion(func(v apiextensionsv1.CustomResourceDefinitionVersion) bool {
+		return v.Name == version && v.Served
+	})
+}
+
+// byCRDVersion creates a filter that accepts CRDs with at least one version
+// matching the predicate, and every non-CRD object.
+func byCRDVersion(match func(apiextensionsv1.CustomResourceDefinitionVersion) bool) ObjectFilter {
+	return func(obj client.Object) bool {
+		versions, isCRD := crdVersions(obj)
+		if !isCRD {
+			return true
+		}
+
+		for _, v := range versions {
+			if match(v) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// crdVersions returns the spec.versions of a typed or unstructured CRD.
+// The second return value reports whether the object is a CRD at all.
+func crdVersions(obj client.Object) ([]apiextensionsv1.CustomResourceDefinitionVersion, bool) {
+	switch crd := obj.(type) {
+	case *apiextensionsv1.CustomResourceDefinition:
+		return crd.Spec.Versions, true
+	case *unstructured.Unstructured:
+		if crd.GroupVersionKind() != gvk.CustomResourceDefinition {
+			return nil, false
+		}
+
+		typed := apiextensionsv1.CustomResourceDefinition{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(crd.Object, &typed); err != nil {
+			return nil, true
+		}
+
+		return typed.Spec.Versions, true
+	default:
+		return nil, false
+	}
+}
+
+// ByCondition creates a filter that accepts objects having a status condition
+// with the given type and status (e.g. "Ready", "True").
+// Conditions are read from .status.conditions using unstructured helpers, so it
+// works for built-in types, CRDs and custom resources alike. Objects with missing
+// or empty conditions are rejected.
+//
+// Usage:
+//
+//	established := All(
+//	    ByType(gvk.CustomResourceDefinition),
+//	    ByCondition("Established", "True"),
+//	)
+func ByCondition(conditionType string, status string) ObjectFilter {
+	return func(obj client.Object) bool {
+		content, err := unstructuredContent(obj)
+		if err != nil {
+			return false
+		}
+
+		conditions, found, err := unstructured.NestedSlice(content, "status", "conditions")
+		if err != nil || !found {
+			return false
+		}
+
+		for _, c := range conditions {
+			condition, ok := c.(map[string]any)
+			if !ok {
+				continue
+			}
+
+			t, _, _ := unstructured.NestedString(condition, "type")
+			s, _, _ := unstructured.NestedString(condition, "status")
+
+			if t == conditionType && s == status {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// unstructuredContent returns the object content as a map, converting typed objects.
+func unstructuredContent(obj client.Object) (map[string]any, error) {
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		return u.Object, nil
+	}
+
+	return runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+}
@@ -4,8 +4,13 @@ package filter
 import (
 	"testing"
 
+	"github.com/lburgazzoli/k3s-envtest/internal/gvk"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	rbacv1 "k8s.io/api/rbac/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
@@ -158,3 +163,177 @@ func TestComplexCombination(t *testing.T) {
 	g.Expect(complexFilter(includedService)).To(BeTrue())
 	g.Expect(complexFilter(deployment)).To(BeFalse())
 }
+
+func TestByClusterScoped_Unstructured(t *testing.T) {
+	g := NewWithT(t)
+
+	clusterScoped := makeObject(testGVKPod, "cluster-object")
+	namespaced := makeObject(testGVKPod, "namespaced-object")
+	namespaced.SetNamespace("default")
+
+	g.Expect(ByClusterScoped()(clusterScoped)).To(BeTrue())
+	g.Expect(ByClusterScoped()(namespaced)).To(BeFalse())
+	g.Expect(ByNamespaceScoped()(clusterScoped)).To(BeFalse())
+	g.Expect(ByNamespaceScoped()(namespaced)).To(BeTrue())
+}
+
+func TestByClusterScoped_TypedWithRESTMapper(t *testing.T) {
+	g := NewWithT(t)
+
+	roleGVK := rbacv1.SchemeGroupVersion.WithKind("Role")
+
+	mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{rbacv1.SchemeGroupVersion})
+	mapper.Add(roleGVK, meta.RESTScopeNamespace)
+
+	// A namespaced kind without namespace set is still namespace-scoped per the mapper
+	role := &rbacv1.Role{
+		TypeMeta:   metav1.TypeMeta{APIVersion: roleGVK.GroupVersion().String(), Kind: roleGVK.Kind},
+		ObjectMeta: metav1.ObjectMeta{Name: "test-role"},
+	}
+
+	g.Expect(ByClusterScoped()(role)).To(BeTrue())
+	g.Expect(ByClusterScoped(mapper)(role)).To(BeFalse())
+	g.Expect(ByNamespaceScoped(mapper)(role)).To(BeTrue())
+}
+
+func TestByClusterScoped_TypedUnknownToRESTMapper(t *testing.T) {
+	g := NewWithT(t)
+
+	mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{rbacv1.SchemeGroupVersion})
+
+	clusterRole := &rbacv1.ClusterRole{
+		TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "ClusterRole"},
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cluster-role"},
+	}
+
+	// Falls back to the structural check when the mapper cannot resolve the kind
+	g.Expect(ByClusterScoped(mapper)(clusterRole)).To(BeTrue())
+	g.Expect(ByClusterScoped(nil)(clusterRole)).To(BeTrue())
+}
+
+func TestByClusterScoped_ComposesWithByType(t *testing.T) {
+	g := NewWithT(t)
+
+	filter := All(ByType(testGVKService), ByClusterScoped())
+
+	clusterService := makeObject(testGVKService, "cluster-service")
+	namespacedService := makeObject(testGVKService, "namespaced-service")
+	namespacedService.SetNamespace("default")
+	pod := makeObject(testGVKPod, "pod")
+
+	g.Expect(filter(clusterService)).To(BeTrue())
+	g.Expect(filter(namespacedService)).To(BeFalse())
+	g.Expect(filter(pod)).To(BeFalse())
+}
+
+func makeCRD(versions ...apiextensionsv1.CustomResourceDefinitionVersion) *apiextensionsv1.CustomResourceDefinition {
+	return &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "tests.example.com"},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Versions: versions,
+		},
+	}
+}
+
+func TestByStoredVersion_TypedCRD(t *testing.T) {
+	g := NewWithT(t)
+
+	crd := makeCRD(
+		apiextensionsv1.CustomResourceDefinitionVersion{Name: "v1alpha1", Served: true, Storage: false},
+		apiextensionsv1.CustomResourceDefinitionVersion{Name: "v1beta1", Served: true, Storage: true},
+	)
+
+	g.Expect(ByStoredVersion("v1beta1")(crd)).To(BeTrue())
+	g.Expect(ByStoredVersion("v1alpha1")(crd)).To(BeFalse())
+	g.Expect(ByStoredVersion("v1")(crd)).To(BeFalse())
+}
+
+func TestByServedVersion_TypedCRD(t *testing.T) {
+	g := NewWithT(t)
+
+	crd := makeCRD(
+		apiextensionsv1.CustomResourceDefinitionVersion{Name: "v1alpha1", Served: false, Storage: false},
+		apiextensionsv1.CustomResourceDefinitionVersion{Name: "v1beta1", Served: true, Storage: true},
+	)
+
+	g.Expect(ByServedVersion("v1beta1")(crd)).To(BeTrue())
+	g.Expect(ByServedVersion("v1alpha1")(crd)).To(BeFalse())
+}
+
+func TestByStoredVersion_UnstructuredCRD(t *testing.T) {
+	g := NewWithT(t)
+
+	crd := makeObject(gvk.CustomResourceDefinition, "tests.example.com")
+	crd.Object["spec"] = map[string]any{
+		"versions": []any{
+			map[string]any{"name": "v1alpha1", "served": true, "storage": false},
+			map[string]any{"name": "v1beta1", "served": true, "storage": true},
+		},
+	}
+
+	g.Expect(ByStoredVersion("v1beta1")(crd)).To(BeTrue())
+	g.Expect(ByStoredVersion("v1alpha1")(crd)).To(BeFalse())
+	g.Expect(ByServedVersion("v1alpha1")(crd)).To(BeTrue())
+}
+
+func TestByStoredVersion_NonCRDAccepted(t *testing.T) {
+	g := NewWithT(t)
+
+	pod := makeObject(testGVKPod, "test-pod")
+
+	g.Expect(ByStoredVersion("v1")(pod)).To(BeTrue())
+	g.Expect(ByServedVersion("v1")(pod)).To(BeTrue())
+
+	// Composes with All() without excluding unrelated types
+	filter := All(ByType(testGVKPod, gvk.CustomResourceDefinition), ByStoredVersion("v1"))
+	g.Expect(filter(pod)).To(BeTrue())
+}
+
+func TestByCondition_Unstructured(t *testing.T) {
+	g := NewWithT(t)
+
+	obj := makeObject(testGVKDeployment, "test-deployment")
+	obj.Object["status"] = map[string]any{
+		"conditions": []any{
+			map[string]any{"type": "Available", "status": "True"},
+			map[string]any{"type": "Progressing", "status": "False"},
+		},
+	}
+
+	g.Expect(ByCondition("Available", "True")(obj)).To(BeTrue())
+	g.Expect(ByCondition("Progressing", "False")(obj)).To(BeTrue())
+	g.Expect(ByCondition("Progressing", "True")(obj)).To(BeFalse())
+	g.Expect(ByCondition("Ready", "True")(obj)).To(BeFalse())
+}
+
+func TestByCondition_TypedCRD(t *testing.T) {
+	g := NewWithT(t)
+
+	crd := makeCRD()
+	crd.Status.Conditions = []apiextensionsv1.CustomResourceDefinitionCondition{
+		{Type: apiextensionsv1.Established, Status: apiextensionsv1.ConditionTrue},
+	}
+
+	g.Expect(ByCondition("Established", "True")(crd)).To(BeTrue())
+	g.Expect(ByCondition("NamesAccepted", "True")(crd)).To(BeFalse())
+}
+
+func TestByCondition_MissingOrEmptyConditions(t *testing.T) {
+	g := NewWithT(t)
+
+	noStatus := makeObject(testGVKPod, "no-status")
+
+	emptyConditions := makeObject(testGVKPod, "empty-conditions")
+	emptyConditions.Object["status"] = map[string]any{
+		"conditions": []any{},
+	}
+
+	malformed := makeObject(testGVKPod, "malformed")
+	malformed.Object["status"] = map[string]any{
+		"conditions": "not-a-list",
+	}
+
+	g.Expect(ByCondition("Ready", "True")(noStatus)).To(BeFalse())
+	g.Expect(ByCondition("Ready", "True")(emptyConditions)).To(BeFalse())
+	g.Expect(ByCondition("Ready", "True")(malformed)).To(BeFalse())
+}
@@ -2,8 +2,11 @@ package resources
 
 import (
 	"fmt"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/lburgazzoli/k3s-envtest/internal/resources/filter"
@@ -14,6 +17,9 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DefaultFSGlob is the glob pattern used by LoadFromFS when none is provided.
+const DefaultFSGlob = "**/*.yaml"
+
 // loadFromFile loads Kubernetes manifests from a single YAML file and applies the optional filter.
 // Returns all objects if filter is nil.
 func loadFromFile(
@@ -31,8 +37,17 @@ func loadFromFile(
 		return nil, fmt.Errorf("failed to decode YAML from %s: %w", filePath, err)
 	}
 
+	return applyFilter(manifests, objectFilter), nil
+}
+
+// applyFilter returns the manifests accepted by the optional filter.
+// Returns all manifests if filter is nil.
+func applyFilter(
+	manifests []unstructured.Unstructured,
+	objectFilter filter.ObjectFilter,
+) []unstructured.Unstructured {
 	if objectFilter == nil {
-		return manifests, nil
+		return manifests
 	}
 
 	result := make([]unstructured.Unstructured, 0, len(manifests))
@@ -42,7 +57,7 @@ func loadFromFile(
 		}
 	}
 
-	return result, nil
+	return result
 }
 
 // loadFromDirectory loads Kubernetes manifests from all YAML files in a directory (flat, non-recursive).
@@ -157,6 +172,112 @@ func LoadFromPaths(
 	return result, nil
 }
 
+// LoadFromFS loads Kubernetes manifests from the files of fsys matching the
+// given glob pattern, such as an embed.FS holding test fixtures. Patterns follow
+// the fs.Glob syntax, extended with "**" matching any number of directories.
+// If glob is empty, DefaultFSGlob is used.
+// Applies the optional filter. Returns all objects if filter is nil.
+func LoadFromFS(
+	fsys fs.FS,
+	glob string,
+	objectFilter filter.ObjectFilter,
+) ([]unstructured.Unstructured, error) {
+	if glob == "" {
+		glob = DefaultFSGlob
+	}
+
+	matches, err := globFS(fsys, glob)
+	if err != nil {
+		return nil, fmt.Errorf("failed to expand glob pattern %s: %w", glob, err)
+	}
+
+	var result []unstructured.Unstructured
+	for _, match := range matches {
+		data, err := fs.ReadFile(fsys, match)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file %s: %w", match, err)
+		}
+
+		manifests, err := Decode(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode YAML from %s: %w", match, err)
+		}
+
+		result = append(result, applyFilter(manifests, objectFilter)...)
+	}
+
+	return result, nil
+}
+
+// globFS returns the names of the regular files of fsys matching pattern, in
+// lexical order. Patterns without "**" are delegated to fs.Glob.
+func globFS(fsys fs.FS, pattern string) ([]string, error) {
+	if !strings.Contains(pattern, "**") {
+		matches, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			return nil, err
+		}
+
+		return slices.DeleteFunc(matches, func(name string) bool {
+			info, err := fs.Stat(fsys, name)
+			return err != nil || info.IsDir()
+		}), nil
+	}
+
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+
+	patternSegments := strings.Split(pattern, "/")
+
+	var matches []string
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if matchSegments(patternSegments, strings.Split(name, "/")) {
+			matches = append(matches, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return matches, nil
+}
+
+// matchSegments reports whether the segments of a slash-separated name match
+// the segments of a pattern, where a "**" segment matches zero or more segments.
+func matchSegments(pattern []string, name []string) bool {
+	if len(pattern) == 0 {
+		return len(name) == 0
+	}
+
+	if pattern[0] == "**" {
+		for i := 0; i <= len(name); i++ {
+			if matchSegments(pattern[1:], name[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(name) == 0 {
+		return false
+	}
+
+	// the pattern has already been validated, so errors can be ignored
+	if ok, _ := path.Match(pattern[0], name[0]); !ok {
+		return false
+	}
+
+	return matchSegments(pattern[1:], name[1:])
+}
+
 // UnstructuredFromObjects converts client.Objects to unstructured.Unstructured objects.
 // Ensures GVK is set on all objects before filtering (required for ByType filter).
 // Returns all objects if filter is nil.
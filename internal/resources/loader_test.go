@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"testing/fstest"
 
 	"github.com/lburgazzoli/k3s-envtest/internal/gvk"
 	"github.com/lburgazzoli/k3s-envtest/internal/resources/filter"
@@ -165,6 +166,58 @@ func TestLoadFromPath_NotFound(t *testing.T) {
 	g.Expect(err.Error()).To(ContainSubstring("does not exist"))
 }
 
+func TestLoadFromFS(t *testing.T) {
+	g := NewWithT(t)
+
+	fsys := fstest.MapFS{
+		"crd.yaml":          {Data: []byte(testCRDYAML)},
+		"nested/pod.yaml":   {Data: []byte(testPodYAML)},
+		"nested/multi.yaml": {Data: []byte(testMultiDocYAML)},
+		"nested/pod.yml":    {Data: []byte(testPodYAML)},
+		"ignore.txt":        {Data: []byte("ignored")},
+	}
+
+	// Default glob matches .yaml files at any depth
+	manifests, err := LoadFromFS(fsys, "", nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(manifests).To(HaveLen(4))
+
+	manifests, err = LoadFromFS(fsys, "nested/*.yaml", nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(manifests).To(HaveLen(3))
+
+	manifests, err = LoadFromFS(fsys, "*.yaml", nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(manifests).To(HaveLen(1))
+	g.Expect(manifests[0].GetName()).To(Equal("crd1"))
+
+	// Load with filter
+	objectFilter := filter.ByType(gvk.CustomResourceDefinition)
+	manifests, err = LoadFromFS(fsys, "", objectFilter)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(manifests).To(HaveLen(2))
+}
+
+func TestLoadFromFS_InvalidYAML(t *testing.T) {
+	g := NewWithT(t)
+
+	fsys := fstest.MapFS{
+		"invalid.yaml": {Data: []byte(testInvalidYAML)},
+	}
+
+	_, err := LoadFromFS(fsys, "", nil)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("failed to decode YAML from invalid.yaml"))
+}
+
+func TestLoadFromFS_InvalidGlob(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := LoadFromFS(fstest.MapFS{}, "**/[", nil)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("failed to expand glob pattern"))
+}
+
 func TestUnstructuredFromObjects_Success(t *testing.T) {
 	g := NewWithT(t)
 
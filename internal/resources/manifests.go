@@ -0,0 +1,122 @@
+package resources
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/lburgazzoli/k3s-envtest/internal/gvk"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// GroupByGVK groups the given manifests by GroupVersionKind, preserving their
+// relative order within each group.
+func GroupByGVK(manifests []unstructured.Unstructured) map[schema.GroupVersionKind][]unstructured.Unstructured {
+	groups := make(map[schema.GroupVersionKind][]unstructured.Unstructured)
+
+	for _, m := range manifests {
+		objGVK := m.GroupVersionKind()
+		groups[objGVK] = append(groups[objGVK], m)
+	}
+
+	return groups
+}
+
+// CountByGVK returns the number of manifests of each GroupVersionKind.
+func CountByGVK(manifests []unstructured.Unstructured) map[schema.GroupVersionKind]int {
+	counts := make(map[schema.GroupVersionKind]int)
+
+	for _, m := range manifests {
+		counts[m.GroupVersionKind()]++
+	}
+
+	return counts
+}
+
+// SplitByNamespace groups the given manifests by namespace, preserving their
+// relative order within each group. Cluster-scoped manifests, and namespaced
+// ones without an explicit namespace, are grouped under the empty string key.
+func SplitByNamespace(manifests []unstructured.Unstructured) map[string][]unstructured.Unstructured {
+	groups := make(map[string][]unstructured.Unstructured)
+
+	for _, m := range manifests {
+		ns := m.GetNamespace()
+		groups[ns] = append(groups[ns], m)
+	}
+
+	return groups
+}
+
+// GroupsByGVKSorted returns the GroupVersionKinds of the given groups sorted by
+// group, version and kind, so that the groups can be iterated in a stable order.
+func GroupsByGVKSorted(groups map[schema.GroupVersionKind][]unstructured.Unstructured) []schema.GroupVersionKind {
+	keys := make([]schema.GroupVersionKind, 0, len(groups))
+	for objGVK := range groups {
+		keys = append(keys, objGVK)
+	}
+
+	slices.SortFunc(keys, compareGVK)
+
+	return keys
+}
+
+// installOrder lists the tiers in which manifests are installed. Kinds not
+// listed here are installed last.
+var installOrder = [][]schema.GroupKind{
+	{
+		gvk.Namespace.GroupKind(),
+	},
+	{
+		gvk.CustomResourceDefinition.GroupKind(),
+	},
+	{
+		gvk.ClusterRole.GroupKind(),
+		gvk.ClusterRoleBinding.GroupKind(),
+	},
+	{
+		gvk.ValidatingAdmissionPolicy.GroupKind(),
+		gvk.ValidatingAdmissionPolicyBinding.GroupKind(),
+	},
+	{
+		gvk.MutatingWebhookConfiguration.GroupKind(),
+		gvk.ValidatingWebhookConfiguration.GroupKind(),
+	},
+}
+
+// SortByInstallOrder returns a copy of the given manifests sorted so that they
+// can be installed in order: Namespaces, CRDs, ClusterRoles and
+// ClusterRoleBindings, ValidatingAdmissionPolicies, webhook configurations and
+// then everything else. Within each tier, manifests are sorted by namespace/name.
+func SortByInstallOrder(manifests []unstructured.Unstructured) []unstructured.Unstructured {
+	sorted := slices.Clone(manifests)
+
+	slices.SortStableFunc(sorted, func(a unstructured.Unstructured, b unstructured.Unstructured) int {
+		return cmp.Or(
+			cmp.Compare(installTier(a), installTier(b)),
+			cmp.Compare(a.GetNamespace()+"/"+a.GetName(), b.GetNamespace()+"/"+b.GetName()),
+		)
+	})
+
+	return sorted
+}
+
+func installTier(u unstructured.Unstructured) int {
+	gk := u.GroupVersionKind().GroupKind()
+
+	for tier, kinds := range installOrder {
+		if slices.Contains(kinds, gk) {
+			return tier
+		}
+	}
+
+	return len(installOrder)
+}
+
+func compareGVK(a schema.GroupVersionKind, b schema.GroupVersionKind) int {
+	return cmp.Or(
+		cmp.Compare(a.Group, b.Group),
+		cmp.Compare(a.Version, b.Version),
+		cmp.Compare(a.Kind, b.Kind),
+	)
+}
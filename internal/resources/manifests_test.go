@@ -0,0 +1,151 @@
+package resources_test
+
+import (
+	"testing"
+
+	"github.com/lburgazzoli/k3s-envtest/internal/gvk"
+	"github.com/lburgazzoli/k3s-envtest/internal/resources"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	. "github.com/onsi/gomega"
+)
+
+func newManifest(objGVK schema.GroupVersionKind, namespace string, name string) unstructured.Unstructured {
+	u := unstructured.Unstructured{}
+	u.SetGroupVersionKind(objGVK)
+	u.SetNamespace(namespace)
+	u.SetName(name)
+
+	return u
+}
+
+func TestGroupByGVK(t *testing.T) {
+	g := NewWithT(t)
+
+	configMap := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+
+	manifests := []unstructured.Unstructured{
+		newManifest(gvk.CustomResourceDefinition, "", "b.example.com"),
+		newManifest(configMap, "default", "cm"),
+		newManifest(gvk.CustomResourceDefinition, "", "a.example.com"),
+	}
+
+	groups := resources.GroupByGVK(manifests)
+
+	g.Expect(groups).To(HaveLen(2))
+	g.Expect(groups[gvk.CustomResourceDefinition]).To(HaveLen(2))
+	g.Expect(groups[gvk.CustomResourceDefinition][0].GetName()).To(Equal("b.example.com"))
+	g.Expect(groups[gvk.CustomResourceDefinition][1].GetName()).To(Equal("a.example.com"))
+	g.Expect(groups[configMap]).To(HaveLen(1))
+}
+
+func TestCountByGVK(t *testing.T) {
+	g := NewWithT(t)
+
+	configMap := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+
+	counts := resources.CountByGVK([]unstructured.Unstructured{
+		newManifest(gvk.CustomResourceDefinition, "", "b.example.com"),
+		newManifest(configMap, "default", "cm"),
+		newManifest(gvk.CustomResourceDefinition, "", "a.example.com"),
+	})
+
+	g.Expect(counts).To(Equal(map[schema.GroupVersionKind]int{
+		gvk.CustomResourceDefinition: 2,
+		configMap:                    1,
+	}))
+}
+
+func TestSplitByNamespace(t *testing.T) {
+	g := NewWithT(t)
+
+	configMap := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+
+	groups := resources.SplitByNamespace([]unstructured.Unstructured{
+		newManifest(configMap, "ns-a", "cm-1"),
+		newManifest(gvk.CustomResourceDefinition, "", "crd"),
+		newManifest(configMap, "ns-b", "cm-2"),
+		newManifest(configMap, "ns-a", "cm-3"),
+	})
+
+	g.Expect(groups).To(HaveLen(3))
+	g.Expect(groups[""]).To(HaveLen(1))
+	g.Expect(groups[""][0].GetName()).To(Equal("crd"))
+	g.Expect(groups["ns-a"]).To(HaveLen(2))
+	g.Expect(groups["ns-a"][0].GetName()).To(Equal("cm-1"))
+	g.Expect(groups["ns-a"][1].GetName()).To(Equal("cm-3"))
+	g.Expect(groups["ns-b"]).To(HaveLen(1))
+}
+
+func TestGroupsByGVKSorted(t *testing.T) {
+	g := NewWithT(t)
+
+	configMap := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+	namespace := schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}
+
+	groups := resources.GroupByGVK([]unstructured.Unstructured{
+		newManifest(gvk.ValidatingWebhookConfiguration, "", "vwc"),
+		newManifest(namespace, "", "ns"),
+		newManifest(gvk.CustomResourceDefinition, "", "crd"),
+		newManifest(configMap, "default", "cm"),
+		newManifest(gvk.MutatingWebhookConfiguration, "", "mwc"),
+	})
+
+	g.Expect(resources.GroupsByGVKSorted(groups)).To(Equal([]schema.GroupVersionKind{
+		configMap,
+		namespace,
+		gvk.MutatingWebhookConfiguration,
+		gvk.ValidatingWebhookConfiguration,
+		gvk.CustomResourceDefinition,
+	}))
+}
+
+func TestSortByInstallOrder(t *testing.T) {
+	g := NewWithT(t)
+
+	namespace := schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}
+	configMap := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+	clusterRole := schema.GroupVersionKind{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole"}
+	clusterRoleBinding := schema.GroupVersionKind{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRoleBinding"}
+
+	manifests := []unstructured.Unstructured{
+		newManifest(configMap, "ns-b", "cm"),
+		newManifest(gvk.ValidatingWebhookConfiguration, "", "vwc"),
+		newManifest(clusterRoleBinding, "", "binding"),
+		newManifest(gvk.ValidatingAdmissionPolicy, "", "policy"),
+		newManifest(gvk.CustomResourceDefinition, "", "b.example.com"),
+		newManifest(configMap, "ns-a", "cm"),
+		newManifest(namespace, "", "ns-b"),
+		newManifest(gvk.MutatingWebhookConfiguration, "", "mwc"),
+		newManifest(clusterRole, "", "role"),
+		newManifest(gvk.CustomResourceDefinition, "", "a.example.com"),
+		newManifest(namespace, "", "ns-a"),
+	}
+
+	sorted := resources.SortByInstallOrder(manifests)
+
+	refs := make([]string, 0, len(sorted))
+	for _, m := range sorted {
+		refs = append(refs, m.GetKind()+" "+m.GetNamespace()+"/"+m.GetName())
+	}
+
+	g.Expect(refs).To(Equal([]string{
+		"Namespace /ns-a",
+		"Namespace /ns-b",
+		"CustomResourceDefinition /a.example.com",
+		"CustomResourceDefinition /b.example.com",
+		"ClusterRoleBinding /binding",
+		"ClusterRole /role",
+		"ValidatingAdmissionPolicy /policy",
+		"MutatingWebhookConfiguration /mwc",
+		"ValidatingWebhookConfiguration /vwc",
+		"ConfigMap ns-a/cm",
+		"ConfigMap ns-b/cm",
+	}))
+
+	// The input is left untouched
+	g.Expect(manifests[0].GetKind()).To(Equal("ConfigMap"))
+	g.Expect(manifests[0].GetNamespace()).To(Equal("ns-b"))
+}
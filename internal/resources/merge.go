@@ -0,0 +1,104 @@
+package resources
+
+import (
+	"errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+)
+
+// MergeUnstructured deep merges patch on top of base and returns the result as
+// a new object, leaving both inputs untouched. Maps are merged recursively,
+// while slices and scalar values from patch replace the ones in base.
+func MergeUnstructured(base *unstructured.Unstructured, patch *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	if base == nil || patch == nil {
+		return nil, errors.New("base and patch cannot be nil")
+	}
+
+	merged := base.DeepCopy()
+	mergeMaps(merged.Object, patch.Object, false)
+
+	return merged, nil
+}
+
+// StrategicMergeUnstructured merges patch on top of base using the given patch
+// type and returns the result as a new object, leaving both inputs untouched.
+//
+// Supported patch types are:
+//   - types.MergePatchType: JSON merge patch (RFC 7386), like MergeUnstructured
+//     but null values in patch remove the corresponding fields.
+//   - types.StrategicMergePatchType: Kubernetes strategic merge patch, e.g.
+//     merging containers by name. It is only supported for the built-in
+//     Kubernetes types, as the merge strategy is read from their Go structs.
+func StrategicMergeUnstructured(
+	base *unstructured.Unstructured,
+	patch *unstructured.Unstructured,
+	patchType types.PatchType,
+) (*unstructured.Unstructured, error) {
+	if base == nil || patch == nil {
+		return nil, errors.New("base and patch cannot be nil")
+	}
+
+	switch patchType {
+	case types.MergePatchType:
+		merged := base.DeepCopy()
+		mergeMaps(merged.Object, patch.Object, true)
+
+		return merged, nil
+
+	case types.StrategicMergePatchType:
+		gvk := base.GroupVersionKind()
+
+		dataStruct, err := clientgoscheme.Scheme.New(gvk)
+		if err != nil {
+			return nil, fmt.Errorf("strategic merge patch is not supported for %s: %w", gvk, err)
+		}
+
+		data, err := strategicpatch.StrategicMergeMapPatch(
+			base.DeepCopy().Object,
+			patch.DeepCopy().Object,
+			dataStruct,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply strategic merge patch to %s: %w", FormatObjectReference(base), err)
+		}
+
+		return &unstructured.Unstructured{Object: data}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported patch type %q", patchType)
+	}
+}
+
+// mergeMaps recursively merges patch into dst. If deleteNulls is set, null
+// values in patch remove the corresponding keys from dst.
+func mergeMaps(dst map[string]any, patch map[string]any, deleteNulls bool) {
+	for key, patchValue := range patch {
+		if patchValue == nil && deleteNulls {
+			delete(dst, key)
+			continue
+		}
+
+		patchMap, patchIsMap := patchValue.(map[string]any)
+		dstMap, dstIsMap := dst[key].(map[string]any)
+
+		if patchIsMap && dstIsMap {
+			mergeMaps(dstMap, patchMap, deleteNulls)
+			continue
+		}
+
+		if patchIsMap && deleteNulls {
+			// Nested nulls must not be kept when adding a new map
+			added := make(map[string]any, len(patchMap))
+			mergeMaps(added, patchMap, deleteNulls)
+			dst[key] = added
+			continue
+		}
+
+		dst[key] = runtime.DeepCopyJSONValue(patchValue)
+	}
+}
@@ -0,0 +1,169 @@
+package resources_test
+
+import (
+	"testing"
+
+	"github.com/lburgazzoli/k3s-envtest/internal/resources"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	. "github.com/onsi/gomega"
+)
+
+func newMergeBase() *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]any{
+			"name": "test",
+			"labels": map[string]any{
+				"app":  "test",
+				"tier": "backend",
+			},
+		},
+		"spec": map[string]any{
+			"replicas": int64(1),
+			"template": map[string]any{
+				"spec": map[string]any{
+					"containers": []any{
+						map[string]any{"name": "app", "image": "app:v1"},
+						map[string]any{"name": "sidecar", "image": "sidecar:v1"},
+					},
+				},
+			},
+		},
+	}}
+}
+
+func TestMergeUnstructured(t *testing.T) {
+	g := NewWithT(t)
+
+	base := newMergeBase()
+	patch := &unstructured.Unstructured{Object: map[string]any{
+		"metadata": map[string]any{
+			"labels": map[string]any{
+				"tier":    "frontend",
+				"version": "v2",
+			},
+		},
+		"spec": map[string]any{
+			"replicas": int64(3),
+			"template": map[string]any{
+				"spec": map[string]any{
+					"containers": []any{
+						map[string]any{"name": "app", "image": "app:v2"},
+					},
+				},
+			},
+		},
+	}}
+
+	merged, err := resources.MergeUnstructured(base, patch)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(merged.GetName()).To(Equal("test"))
+	g.Expect(merged.GetLabels()).To(Equal(map[string]string{
+		"app":     "test",
+		"tier":    "frontend",
+		"version": "v2",
+	}))
+
+	replicas, _, _ := unstructured.NestedInt64(merged.Object, "spec", "replicas")
+	g.Expect(replicas).To(Equal(int64(3)))
+
+	// Slices are replaced, not merged
+	containers, _, _ := unstructured.NestedSlice(merged.Object, "spec", "template", "spec", "containers")
+	g.Expect(containers).To(HaveLen(1))
+
+	// Inputs are left untouched
+	g.Expect(base.GetLabels()).To(HaveKeyWithValue("tier", "backend"))
+	g.Expect(base.Object).To(Equal(newMergeBase().Object))
+}
+
+func TestMergeUnstructured_Nil(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := resources.MergeUnstructured(nil, newMergeBase())
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestStrategicMergeUnstructured_MergePatch(t *testing.T) {
+	g := NewWithT(t)
+
+	patch := &unstructured.Unstructured{Object: map[string]any{
+		"metadata": map[string]any{
+			"labels": map[string]any{
+				"tier": nil,
+			},
+			"annotations": map[string]any{
+				"owner":   "team",
+				"removed": nil,
+			},
+		},
+	}}
+
+	merged, err := resources.StrategicMergeUnstructured(newMergeBase(), patch, types.MergePatchType)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(merged.GetLabels()).To(Equal(map[string]string{"app": "test"}))
+	g.Expect(merged.GetAnnotations()).To(Equal(map[string]string{"owner": "team"}))
+}
+
+func TestStrategicMergeUnstructured_StrategicMergePatch(t *testing.T) {
+	g := NewWithT(t)
+
+	patch := &unstructured.Unstructured{Object: map[string]any{
+		"spec": map[string]any{
+			"template": map[string]any{
+				"spec": map[string]any{
+					"containers": []any{
+						map[string]any{"name": "app", "image": "app:v2"},
+					},
+				},
+			},
+		},
+	}}
+
+	merged, err := resources.StrategicMergeUnstructured(newMergeBase(), patch, types.StrategicMergePatchType)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// Containers are merged by name
+	containers, _, _ := unstructured.NestedSlice(merged.Object, "spec", "template", "spec", "containers")
+	g.Expect(containers).To(ConsistOf(
+		map[string]any{"name": "app", "image": "app:v2"},
+		map[string]any{"name": "sidecar", "image": "sidecar:v1"},
+	))
+}
+
+func TestStrategicMergeUnstructured_Unsupported(t *testing.T) {
+	tests := []struct {
+		name      string
+		base      *unstructured.Unstructured
+		patchType types.PatchType
+		message   string
+	}{
+		{
+			name:      "unknown type for strategic merge",
+			base:      &unstructured.Unstructured{Object: map[string]any{"apiVersion": "example.com/v1", "kind": "Unknown"}},
+			patchType: types.StrategicMergePatchType,
+			message:   "strategic merge patch is not supported",
+		},
+		{
+			name:      "json patch",
+			base:      newMergeBase(),
+			patchType: types.JSONPatchType,
+			message:   "unsupported patch type",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			_, err := resources.StrategicMergeUnstructured(tt.base, &unstructured.Unstructured{Object: map[string]any{}}, tt.patchType)
+			g.Expect(err).To(HaveOccurred())
+			g.Expect(err.Error()).To(ContainSubstring(tt.message))
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package resources
+
+import (
+	"maps"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// ApplyLabels merges the given labels into the labels of obj, overriding
+// existing labels with the same key.
+func ApplyLabels(obj *unstructured.Unstructured, labels map[string]string) {
+	obj.SetLabels(mergeStringMaps(obj.GetLabels(), labels))
+}
+
+// ApplyAnnotations merges the given annotations into the annotations of obj,
+// overriding existing annotations with the same key.
+func ApplyAnnotations(obj *unstructured.Unstructured, annotations map[string]string) {
+	obj.SetAnnotations(mergeStringMaps(obj.GetAnnotations(), annotations))
+}
+
+// SetLabels replaces the labels of obj with the given ones.
+func SetLabels(obj *unstructured.Unstructured, labels map[string]string) {
+	obj.SetLabels(maps.Clone(labels))
+}
+
+// SetAnnotations replaces the annotations of obj with the given ones.
+func SetAnnotations(obj *unstructured.Unstructured, annotations map[string]string) {
+	obj.SetAnnotations(maps.Clone(annotations))
+}
+
+func mergeStringMaps(dst map[string]string, src map[string]string) map[string]string {
+	if dst == nil {
+		dst = make(map[string]string, len(src))
+	}
+
+	maps.Copy(dst, src)
+
+	return dst
+}
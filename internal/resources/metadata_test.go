@@ -0,0 +1,76 @@
+package resources_test
+
+import (
+	"testing"
+
+	"github.com/lburgazzoli/k3s-envtest/internal/resources"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	. "github.com/onsi/gomega"
+)
+
+func newMetadataObject() *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion("v1")
+	u.SetKind("ConfigMap")
+	u.SetName("test")
+	u.SetLabels(map[string]string{"app": "test", "tier": "backend"})
+	u.SetAnnotations(map[string]string{"owner": "team"})
+
+	return u
+}
+
+func TestApplyLabels(t *testing.T) {
+	g := NewWithT(t)
+
+	obj := newMetadataObject()
+	resources.ApplyLabels(obj, map[string]string{"tier": "frontend", "env": "test"})
+
+	g.Expect(obj.GetLabels()).To(Equal(map[string]string{
+		"app":  "test",
+		"tier": "frontend",
+		"env":  "test",
+	}))
+
+	empty := &unstructured.Unstructured{Object: map[string]any{}}
+	resources.ApplyLabels(empty, map[string]string{"env": "test"})
+
+	g.Expect(empty.GetLabels()).To(Equal(map[string]string{"env": "test"}))
+}
+
+func TestApplyAnnotations(t *testing.T) {
+	g := NewWithT(t)
+
+	obj := newMetadataObject()
+	resources.ApplyAnnotations(obj, map[string]string{"example.com/debug": "true"})
+
+	g.Expect(obj.GetAnnotations()).To(Equal(map[string]string{
+		"owner":             "team",
+		"example.com/debug": "true",
+	}))
+}
+
+func TestSetLabels(t *testing.T) {
+	g := NewWithT(t)
+
+	labels := map[string]string{"env": "test"}
+
+	obj := newMetadataObject()
+	resources.SetLabels(obj, labels)
+
+	g.Expect(obj.GetLabels()).To(Equal(map[string]string{"env": "test"}))
+
+	// The given map is not aliased
+	labels["other"] = "value"
+	g.Expect(obj.GetLabels()).NotTo(HaveKey("other"))
+}
+
+func TestSetAnnotations(t *testing.T) {
+	g := NewWithT(t)
+
+	obj := newMetadataObject()
+	resources.SetAnnotations(obj, map[string]string{"example.com/debug": "true"})
+
+	g.Expect(obj.GetAnnotations()).To(Equal(map[string]string{"example.com/debug": "true"}))
+}
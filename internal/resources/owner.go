@@ -0,0 +1,53 @@
+package resources
+
+import (
+	"errors"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// SetOwnerReference adds owner to the owner references of owned, or updates
+// the existing reference to it. The owner must have already been created in
+// the cluster, i.e. it must have a UID.
+func SetOwnerReference(owner *unstructured.Unstructured, owned *unstructured.Unstructured, scheme *runtime.Scheme) error {
+	if err := validateOwner(owner, owned); err != nil {
+		return err
+	}
+
+	if err := controllerutil.SetOwnerReference(owner, owned, scheme); err != nil {
+		return fmt.Errorf("failed to set owner reference on %s: %w", FormatObjectReference(owned), err)
+	}
+
+	return nil
+}
+
+// SetControllerReference is like SetOwnerReference but marks owner as the
+// controller of owned, setting controller and blockOwnerDeletion to true.
+// It fails if owned is already controlled by another object.
+func SetControllerReference(owner *unstructured.Unstructured, owned *unstructured.Unstructured, scheme *runtime.Scheme) error {
+	if err := validateOwner(owner, owned); err != nil {
+		return err
+	}
+
+	if err := controllerutil.SetControllerReference(owner, owned, scheme); err != nil {
+		return fmt.Errorf("failed to set controller reference on %s: %w", FormatObjectReference(owned), err)
+	}
+
+	return nil
+}
+
+func validateOwner(owner *unstructured.Unstructured, owned *unstructured.Unstructured) error {
+	if owner == nil || owned == nil {
+		return errors.New("owner and owned cannot be nil")
+	}
+
+	if owner.GetUID() == "" {
+		return fmt.Errorf("owner %s has no UID, it must be created in the cluster first", FormatObjectReference(owner))
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package resources_test
+
+import (
+	"testing"
+
+	"github.com/lburgazzoli/k3s-envtest/internal/resources"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	. "github.com/onsi/gomega"
+)
+
+func newOwnedObject(kind string, name string, uid types.UID) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion("v1")
+	u.SetKind(kind)
+	u.SetNamespace("default")
+	u.SetName(name)
+	u.SetUID(uid)
+
+	return u
+}
+
+func TestSetOwnerReference(t *testing.T) {
+	g := NewWithT(t)
+
+	owner := newOwnedObject("ConfigMap", "owner", "owner-uid")
+	owned := newOwnedObject("Secret", "owned", "")
+
+	err := resources.SetOwnerReference(owner, owned, runtime.NewScheme())
+	g.Expect(err).NotTo(HaveOccurred())
+
+	refs := owned.GetOwnerReferences()
+	g.Expect(refs).To(HaveLen(1))
+	g.Expect(refs[0].APIVersion).To(Equal("v1"))
+	g.Expect(refs[0].Kind).To(Equal("ConfigMap"))
+	g.Expect(refs[0].Name).To(Equal("owner"))
+	g.Expect(refs[0].UID).To(Equal(types.UID("owner-uid")))
+	g.Expect(refs[0].Controller).To(BeNil())
+	g.Expect(refs[0].BlockOwnerDeletion).To(BeNil())
+
+	// Setting the same owner again updates the existing reference
+	err = resources.SetOwnerReference(owner, owned, runtime.NewScheme())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(owned.GetOwnerReferences()).To(HaveLen(1))
+}
+
+func TestSetControllerReference(t *testing.T) {
+	g := NewWithT(t)
+
+	owner := newOwnedObject("ConfigMap", "owner", "owner-uid")
+	owned := newOwnedObject("Secret", "owned", "")
+
+	err := resources.SetControllerReference(owner, owned, runtime.NewScheme())
+	g.Expect(err).NotTo(HaveOccurred())
+
+	refs := owned.GetOwnerReferences()
+	g.Expect(refs).To(HaveLen(1))
+	g.Expect(refs[0].Controller).To(Equal(ptr.To(true)))
+	g.Expect(refs[0].BlockOwnerDeletion).To(Equal(ptr.To(true)))
+
+	// A second controller is rejected
+	other := newOwnedObject("ConfigMap", "other", "other-uid")
+
+	err = resources.SetControllerReference(other, owned, runtime.NewScheme())
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestSetOwnerReference_OwnerWithoutUID(t *testing.T) {
+	g := NewWithT(t)
+
+	owner := newOwnedObject("ConfigMap", "owner", "")
+	owned := newOwnedObject("Secret", "owned", "")
+
+	err := resources.SetOwnerReference(owner, owned, runtime.NewScheme())
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("has no UID"))
+
+	err = resources.SetControllerReference(owner, owned, runtime.NewScheme())
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("has no UID"))
+
+	g.Expect(owned.GetOwnerReferences()).To(BeEmpty())
+}
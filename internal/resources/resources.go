@@ -6,9 +6,9 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/lburgazzoli/k3s-envtest/internal/gvk"
 	"gopkg.in/yaml.v3"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -30,47 +30,53 @@ func ToUnstructured(obj any) (*unstructured.Unstructured, error) {
 	return &u, nil
 }
 
-// YAMLToUnstructured converts a YAML string to an unstructured object.
-// This is useful for testing purposes.
+// YAMLToUnstructured converts a YAML string holding a single Kubernetes
+// object to an unstructured object. This is useful for testing purposes.
 func YAMLToUnstructured(yamlStr string) (*unstructured.Unstructured, error) {
-	var data map[string]any
-	if err := yaml.Unmarshal([]byte(yamlStr), &data); err != nil {
+	objs, err := YAMLDocumentsToUnstructured(yamlStr)
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(objs) {
+	case 0:
+		return nil, errors.New("no object found in yaml")
+	case 1:
+		return &objs[0], nil
+	default:
+		return nil, fmt.Errorf("expected a single object in yaml, found %d", len(objs))
+	}
+}
+
+// YAMLDocumentsToUnstructured converts a multi-document YAML string to
+// unstructured objects. Empty documents and documents without a kind are
+// skipped, as with Decode.
+func YAMLDocumentsToUnstructured(yamlStr string) ([]unstructured.Unstructured, error) {
+	objs, err := Decode([]byte(yamlStr))
+	if err != nil {
 		return nil, fmt.Errorf("failed to unmarshal yaml: %w", err)
 	}
 
-	return &unstructured.Unstructured{Object: data}, nil
+	return objs, nil
 }
 
 func GetGroupVersionKindForObject(
 	s *runtime.Scheme,
 	obj runtime.Object,
 ) (schema.GroupVersionKind, error) {
-	if obj == nil {
-		return schema.GroupVersionKind{}, errors.New("nil object")
-	}
-
-	if obj.GetObjectKind().GroupVersionKind().Version != "" && obj.GetObjectKind().GroupVersionKind().Kind != "" {
-		return obj.GetObjectKind().GroupVersionKind(), nil
-	}
-
-	gvk, err := apiutil.GVKForObject(obj, s)
-	if err != nil {
-		return schema.GroupVersionKind{}, fmt.Errorf("failed to get GVK: %w", err)
-	}
-
-	return gvk, nil
+	return gvk.FromObject(s, obj)
 }
 
 func EnsureGroupVersionKind(
 	s *runtime.Scheme,
 	obj client.Object,
 ) error {
-	gvk, err := GetGroupVersionKindForObject(s, obj)
+	objGVK, err := GetGroupVersionKindForObject(s, obj)
 	if err != nil {
 		return err
 	}
 
-	obj.GetObjectKind().SetGroupVersionKind(gvk)
+	obj.GetObjectKind().SetGroupVersionKind(objGVK)
 
 	return nil
 }
@@ -91,13 +97,13 @@ func Convert[T client.Object](
 }
 
 func FormatObjectReference(u client.Object) string {
-	gvk := u.GetObjectKind().GroupVersionKind().String()
+	objGVK := u.GetObjectKind().GroupVersionKind().String()
 	name := u.GetName()
 	ns := u.GetNamespace()
 	if ns != "" {
-		return gvk + " " + ns + "/" + name
+		return objGVK + " " + ns + "/" + name
 	}
-	return gvk + " " + name
+	return objGVK + " " + name
 }
 
 func Decode(content []byte) ([]unstructured.Unstructured, error) {
@@ -142,13 +148,13 @@ func Decode(content []byte) ([]unstructured.Unstructured, error) {
 // that support conversion between versions.
 func AllConvertibleTypes(scheme *runtime.Scheme) (sets.Set[schema.GroupKind], error) {
 	convertibles := sets.New[schema.GroupKind]()
-	for gvk := range scheme.AllKnownTypes() {
-		obj, err := scheme.New(gvk)
+	for objGVK := range scheme.AllKnownTypes() {
+		obj, err := scheme.New(objGVK)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create object for %s: %w", gvk, err)
+			return nil, fmt.Errorf("failed to create object for %s: %w", objGVK, err)
 		}
 		if ok, err := conversion.IsConvertible(scheme, obj); ok && err == nil {
-			convertibles.Insert(gvk.GroupKind())
+			convertibles.Insert(objGVK.GroupKind())
 		}
 	}
 	return convertibles, nil
@@ -0,0 +1,78 @@
+package resources_test
+
+import (
+	"testing"
+
+	"github.com/lburgazzoli/k3s-envtest/internal/resources"
+
+	. "github.com/onsi/gomega"
+)
+
+const testYAMLDocuments = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first
+---
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: second
+`
+
+func TestYAMLToUnstructured(t *testing.T) {
+	g := NewWithT(t)
+
+	obj, err := resources.YAMLToUnstructured(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: test
+  namespace: default
+data:
+  key: value
+`)
+
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(obj.GetKind()).To(Equal("ConfigMap"))
+	g.Expect(obj.GetName()).To(Equal("test"))
+	g.Expect(obj.GetNamespace()).To(Equal("default"))
+	g.Expect(obj.Object).To(HaveKeyWithValue("data", HaveKeyWithValue("key", "value")))
+}
+
+func TestYAMLToUnstructured_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		message string
+	}{
+		{"empty", "", "no object found"},
+		{"multiple documents", testYAMLDocuments, "expected a single object in yaml, found 2"},
+		{"malformed", "kind: [", "failed to unmarshal yaml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			obj, err := resources.YAMLToUnstructured(tt.yaml)
+
+			g.Expect(err).To(HaveOccurred())
+			g.Expect(err.Error()).To(ContainSubstring(tt.message))
+			g.Expect(obj).To(BeNil())
+		})
+	}
+}
+
+func TestYAMLDocumentsToUnstructured(t *testing.T) {
+	g := NewWithT(t)
+
+	objs, err := resources.YAMLDocumentsToUnstructured(testYAMLDocuments)
+
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(objs).To(HaveLen(2))
+	g.Expect(objs[0].GetKind()).To(Equal("ConfigMap"))
+	g.Expect(objs[0].GetName()).To(Equal("first"))
+	g.Expect(objs[1].GetKind()).To(Equal("Secret"))
+	g.Expect(objs[1].GetName()).To(Equal("second"))
+}
@@ -0,0 +1,39 @@
+package resources
+
+import (
+	"github.com/lburgazzoli/k3s-envtest/internal/gvk"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// IsGVK reports whether obj has the given GroupVersionKind. Typed objects
+// must have their TypeMeta set, see EnsureGroupVersionKind.
+func IsGVK(obj client.Object, objGVK schema.GroupVersionKind) bool {
+	if obj == nil {
+		return false
+	}
+
+	return obj.GetObjectKind().GroupVersionKind() == objGVK
+}
+
+// IsCRD reports whether obj is a CustomResourceDefinition.
+func IsCRD(obj client.Object) bool {
+	return IsGVK(obj, gvk.CustomResourceDefinition)
+}
+
+// IsMutatingWebhookConfiguration reports whether obj is a MutatingWebhookConfiguration.
+func IsMutatingWebhookConfiguration(obj client.Object) bool {
+	return IsGVK(obj, gvk.MutatingWebhookConfiguration)
+}
+
+// IsValidatingWebhookConfiguration reports whether obj is a ValidatingWebhookConfiguration.
+func IsValidatingWebhookConfiguration(obj client.Object) bool {
+	return IsGVK(obj, gvk.ValidatingWebhookConfiguration)
+}
+
+// IsWebhookConfiguration reports whether obj is a MutatingWebhookConfiguration
+// or a ValidatingWebhookConfiguration.
+func IsWebhookConfiguration(obj client.Object) bool {
+	return IsMutatingWebhookConfiguration(obj) || IsValidatingWebhookConfiguration(obj)
+}
@@ -0,0 +1,61 @@
+package resources_test
+
+import (
+	"testing"
+
+	"github.com/lburgazzoli/k3s-envtest/internal/gvk"
+	"github.com/lburgazzoli/k3s-envtest/internal/resources"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestTypeChecks(t *testing.T) {
+	newObject := func(objGVK schema.GroupVersionKind) client.Object {
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(objGVK)
+		return u
+	}
+
+	configMap := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+
+	tests := []struct {
+		name       string
+		obj        client.Object
+		crd        bool
+		mutating   bool
+		validating bool
+	}{
+		{"crd", newObject(gvk.CustomResourceDefinition), true, false, false},
+		{"mutating webhook", newObject(gvk.MutatingWebhookConfiguration), false, true, false},
+		{"validating webhook", newObject(gvk.ValidatingWebhookConfiguration), false, false, true},
+		{"other", newObject(configMap), false, false, false},
+		{"nil", nil, false, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(resources.IsCRD(tt.obj)).To(Equal(tt.crd))
+			g.Expect(resources.IsMutatingWebhookConfiguration(tt.obj)).To(Equal(tt.mutating))
+			g.Expect(resources.IsValidatingWebhookConfiguration(tt.obj)).To(Equal(tt.validating))
+			g.Expect(resources.IsWebhookConfiguration(tt.obj)).To(Equal(tt.mutating || tt.validating))
+		})
+	}
+}
+
+func TestIsGVK_TypedObject(t *testing.T) {
+	g := NewWithT(t)
+
+	webhook := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+	g.Expect(resources.IsValidatingWebhookConfiguration(webhook)).To(BeFalse())
+
+	webhook.SetGroupVersionKind(gvk.ValidatingWebhookConfiguration)
+	g.Expect(resources.IsValidatingWebhookConfiguration(webhook)).To(BeTrue())
+	g.Expect(resources.IsGVK(webhook, gvk.MutatingWebhookConfiguration)).To(BeFalse())
+}
@@ -3,6 +3,7 @@ package resources
 import (
 	"fmt"
 	"net/url"
+	"slices"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -58,6 +59,79 @@ func ExtractWebhookURLs(obj client.Object) ([]string, error) {
 	return urls, nil
 }
 
+// GetWebhookPaths returns the sorted, deduplicated paths served by the webhooks
+// of a MutatingWebhookConfiguration or ValidatingWebhookConfiguration. Paths
+// are taken from clientConfig.service.path before installation, defaulting to
+// "/", and from clientConfig.url once the configuration has been patched.
+func GetWebhookPaths(obj client.Object) ([]string, error) {
+	configs, err := webhookClientConfigs(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(configs))
+	for _, config := range configs {
+		switch {
+		case config.Service != nil:
+			paths = append(paths, ptr.Deref(config.Service.Path, "/"))
+		case config.URL != nil:
+			parsedURL, err := url.Parse(*config.URL)
+			if err != nil {
+				return nil, fmt.Errorf("invalid URL in webhook configuration %s: %w", obj.GetName(), err)
+			}
+			paths = append(paths, parsedURL.Path)
+		}
+	}
+
+	slices.Sort(paths)
+
+	return slices.Compact(paths), nil
+}
+
+// GetWebhookHosts returns the sorted, deduplicated hosts, in host[:port] form,
+// of the clientConfig.url of the webhooks of a MutatingWebhookConfiguration or
+// ValidatingWebhookConfiguration. Webhooks referencing a service are ignored.
+func GetWebhookHosts(obj client.Object) ([]string, error) {
+	urls, err := ExtractWebhookURLs(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	hosts := make([]string, 0, len(urls))
+	for _, urlStr := range urls {
+		parsedURL, err := url.Parse(urlStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid URL in webhook configuration %s: %w", obj.GetName(), err)
+		}
+		hosts = append(hosts, parsedURL.Host)
+	}
+
+	slices.Sort(hosts)
+
+	return slices.Compact(hosts), nil
+}
+
+// webhookClientConfigs returns the client configurations of all the webhooks
+// of a MutatingWebhookConfiguration or ValidatingWebhookConfiguration.
+func webhookClientConfigs(obj client.Object) ([]admissionregistrationv1.WebhookClientConfig, error) {
+	var configs []admissionregistrationv1.WebhookClientConfig
+
+	switch webhook := obj.(type) {
+	case *admissionregistrationv1.MutatingWebhookConfiguration:
+		for _, wh := range webhook.Webhooks {
+			configs = append(configs, wh.ClientConfig)
+		}
+	case *admissionregistrationv1.ValidatingWebhookConfiguration:
+		for _, wh := range webhook.Webhooks {
+			configs = append(configs, wh.ClientConfig)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported webhook configuration type: %T", obj)
+	}
+
+	return configs, nil
+}
+
 // patchClientConfig updates a WebhookClientConfig to use a direct URL instead of a service reference.
 func patchClientConfig(
 	config *admissionregistrationv1.WebhookClientConfig,
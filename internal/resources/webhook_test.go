@@ -317,3 +317,81 @@ func TestPatchWebhookConfiguration_RealWorldExample(t *testing.T) {
 	g.Expect(webhook.Webhooks[0].Rules).To(HaveLen(1))
 	g.Expect(webhook.Webhooks[0].AdmissionReviewVersions).To(Equal([]string{"v1"}))
 }
+
+func TestGetWebhookPaths_Service(t *testing.T) {
+	g := NewWithT(t)
+
+	webhook := &admissionregistrationv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-webhook"},
+		Webhooks: []admissionregistrationv1.ValidatingWebhook{
+			{
+				ClientConfig: admissionregistrationv1.WebhookClientConfig{
+					Service: &admissionregistrationv1.ServiceReference{Name: "svc", Namespace: "ns", Path: ptr.To("/validate2")},
+				},
+			},
+			{
+				ClientConfig: admissionregistrationv1.WebhookClientConfig{
+					Service: &admissionregistrationv1.ServiceReference{Name: "svc", Namespace: "ns", Path: ptr.To("/validate1")},
+				},
+			},
+			{
+				ClientConfig: admissionregistrationv1.WebhookClientConfig{
+					Service: &admissionregistrationv1.ServiceReference{Name: "svc", Namespace: "ns", Path: ptr.To("/validate2")},
+				},
+			},
+			{
+				ClientConfig: admissionregistrationv1.WebhookClientConfig{
+					Service: &admissionregistrationv1.ServiceReference{Name: "svc", Namespace: "ns"},
+				},
+			},
+		},
+	}
+
+	paths, err := resources.GetWebhookPaths(webhook)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(paths).To(Equal([]string{"/", "/validate1", "/validate2"}))
+}
+
+func TestGetWebhookPathsAndHosts_URL(t *testing.T) {
+	g := NewWithT(t)
+
+	webhook := &admissionregistrationv1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-webhook"},
+		Webhooks: []admissionregistrationv1.MutatingWebhook{
+			{
+				ClientConfig: admissionregistrationv1.WebhookClientConfig{
+					URL: ptr.To("https://host-b:9443/mutate"),
+				},
+			},
+			{
+				ClientConfig: admissionregistrationv1.WebhookClientConfig{
+					URL: ptr.To("https://host-a:9443/default"),
+				},
+			},
+			{
+				ClientConfig: admissionregistrationv1.WebhookClientConfig{
+					URL: ptr.To("https://host-b:9443/default"),
+				},
+			},
+		},
+	}
+
+	paths, err := resources.GetWebhookPaths(webhook)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(paths).To(Equal([]string{"/default", "/mutate"}))
+
+	hosts, err := resources.GetWebhookHosts(webhook)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(hosts).To(Equal([]string{"host-a:9443", "host-b:9443"}))
+}
+
+func TestGetWebhookPaths_UnsupportedType(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := resources.GetWebhookPaths(&admissionregistrationv1.ValidatingAdmissionPolicy{})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("unsupported webhook configuration type"))
+
+	_, err = resources.GetWebhookHosts(&admissionregistrationv1.ValidatingAdmissionPolicy{})
+	g.Expect(err).To(HaveOccurred())
+}
@@ -13,12 +13,17 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/errgroup"
 
 	admissionv1 "k8s.io/api/admission/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/rest"
 )
 
 // Client is a webhook testing client that simplifies making calls to
@@ -77,6 +82,36 @@ func NewClient(host string, port int, opts ...ClientOption) (*Client, error) {
 	}, nil
 }
 
+// NewClientFromConfig creates a new webhook client using the host of the given
+// rest.Config and the provided port. This is convenient to wire together the
+// rest.Config of a test environment and its webhook server:
+//
+//	client, err := webhook.NewClientFromConfig(env.Config(), 9443)
+//
+// If cfg.CAData is set, it is used as the CA certificate and takes precedence
+// over any WithClientCACert option.
+func NewClientFromConfig(cfg *rest.Config, port int, opts ...ClientOption) (*Client, error) {
+	if cfg == nil {
+		return nil, errors.New("config cannot be nil")
+	}
+
+	host := cfg.Host
+	if !strings.Contains(host, "://") {
+		host = "https://" + host
+	}
+
+	parsedURL, err := url.Parse(host)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config host %s: %w", cfg.Host, err)
+	}
+
+	if len(cfg.CAData) > 0 {
+		opts = append(opts[:len(opts):len(opts)], WithClientCACert(cfg.CAData))
+	}
+
+	return NewClient(parsedURL.Hostname(), port, opts...)
+}
+
 // Address returns the base address (host:port) that the client connects to.
 func (c *Client) Address() string {
 	return net.JoinHostPort(c.host, strconv.Itoa(c.port))
@@ -116,6 +151,93 @@ func (c *Client) Call(
 	review admissionv1.AdmissionReview,
 	opts ...CallOption,
 ) (*admissionv1.AdmissionReview, error) {
+	var reviewResp admissionv1.AdmissionReview
+	if err := c.call(ctx, path, "AdmissionReview", review, &reviewResp, opts...); err != nil {
+		return nil, err
+	}
+
+	return &reviewResp, nil
+}
+
+// EndpointUnreachableError is returned by CheckEndpoint when the webhook
+// endpoint could not be reached, e.g. because the connection was refused or
+// the TLS handshake failed.
+type EndpointUnreachableError struct {
+	Path string
+	Err  error
+}
+
+func (e *EndpointUnreachableError) Error() string {
+	return fmt.Sprintf("webhook endpoint %s unreachable: %v", e.Path, e.Err)
+}
+
+func (e *EndpointUnreachableError) Unwrap() error {
+	return e.Err
+}
+
+// EndpointStatusError is returned by CheckEndpoint when the webhook endpoint
+// responded with a non-2xx status code.
+type EndpointStatusError struct {
+	Path       string
+	StatusCode int
+}
+
+func (e *EndpointStatusError) Error() string {
+	return fmt.Sprintf("webhook endpoint %s returned status %d", e.Path, e.StatusCode)
+}
+
+// CheckEndpoint sends exactly one health check AdmissionReview to the given
+// path and returns nil if the endpoint responded with a 2xx status code.
+//
+// Unlike WaitForEndpoints, no polling is performed, and the RetryOptions of the
+// client are ignored: callers that need to wait for an endpoint are expected
+// to manage the retry loop themselves. The
+// returned error is an *EndpointUnreachableError if the endpoint could not be
+// reached, or an *EndpointStatusError if it responded with a non-2xx status.
+func (c *Client) CheckEndpoint(ctx context.Context, path string) error {
+	ctx, cancel := context.WithTimeout(ctx, DefaultCallTimeout)
+	defer cancel()
+
+	if path == "" {
+		path = "/"
+	}
+
+	body, err := json.Marshal(newHealthCheckReview())
+	if err != nil {
+		return fmt.Errorf("failed to marshal AdmissionReview: %w", err)
+	}
+
+	req, err := newRequest(ctx, c.url(path), body, c.opts.Headers)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return &EndpointUnreachableError{Path: path, Err: err}
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &EndpointStatusError{Path: path, StatusCode: resp.StatusCode}
+	}
+
+	return nil
+}
+
+// call POSTs in, encoded as JSON, to the given path and decodes the
+// response into out. kind is used to describe the payload in error messages.
+func (c *Client) call(
+	ctx context.Context,
+	path string,
+	kind string,
+	in any,
+	out any,
+	opts ...CallOption,
+) error {
 	callOpts := &CallOptions{
 		Timeout: DefaultCallTimeout,
 	}
@@ -134,44 +256,115 @@ func (c *Client) Call(
 		path = "/"
 	}
 
-	hostPort := net.JoinHostPort(c.host, strconv.Itoa(c.port))
-	url := fmt.Sprintf("https://%s%s", hostPort, path)
-
-	body, err := json.Marshal(review)
+	body, err := json.Marshal(in)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal AdmissionReview: %w", err)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+		return fmt.Errorf("failed to marshal %s: %w", kind, err)
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.send(ctx, c.url(path), body, mergeHeaders(c.opts.Headers, callOpts.Headers))
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request to %s: %w", url, err)
+		return err
 	}
 	defer func() {
 		_ = resp.Body.Close()
 	}()
 
 	if resp.StatusCode >= 500 {
-		return nil, fmt.Errorf("webhook returned server error: %d", resp.StatusCode)
+		return fmt.Errorf("webhook returned server error: %d", resp.StatusCode)
 	}
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return fmt.Errorf("failed to read response body: %w", err)
 	}
 
-	var reviewResp admissionv1.AdmissionReview
-	if err := json.Unmarshal(respBody, &reviewResp); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal AdmissionReview response: %w", err)
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("failed to unmarshal %s response: %w", kind, err)
 	}
 
-	return &reviewResp, nil
+	return nil
+}
+
+// url returns the HTTPS URL of the given path on the webhook server.
+func (c *Client) url(path string) string {
+	return fmt.Sprintf("https://%s%s", c.Address(), path)
+}
+
+// send POSTs the body to the given URL with the given additional headers,
+// retrying network-level failures according to the configured RetryOptions.
+// HTTP responses, whatever their status code, are never retried.
+func (c *Client) send(ctx context.Context, url string, body []byte, headers http.Header) (*http.Response, error) {
+	attempts := 1
+	var backoff time.Duration
+
+	if c.opts.Retry != nil && c.opts.Retry.MaxAttempts > 1 {
+		attempts = c.opts.Retry.MaxAttempts
+		backoff = c.opts.Retry.Backoff
+	}
+
+	for attempt := 1; ; attempt++ {
+		req, err := newRequest(ctx, url, body, headers)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err == nil {
+			return resp, nil
+		}
+
+		if attempt >= attempts || ctx.Err() != nil || !isRetryable(err) {
+			if attempt > 1 {
+				return nil, fmt.Errorf("failed to send request to %s after %d attempts: %w", url, attempt, err)
+			}
+			return nil, fmt.Errorf("failed to send request to %s: %w", url, err)
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("failed to send request to %s after %d attempts: %w (last error: %w)", url, attempt, ctx.Err(), err)
+		case <-timer.C:
+		}
+
+		if c.opts.Retry.Exponential {
+			backoff *= 2
+		}
+	}
+}
+
+// newRequest creates a POST request of the body to the given URL, with the
+// given additional headers and the JSON ones of the webhook protocol.
+func newRequest(ctx context.Context, url string, body []byte, headers http.Header) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	for key, values := range headers {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
+	// Always set last, so that custom headers cannot break the webhook protocol
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	return req, nil
+}
+
+// isRetryable reports whether err is a network-level error, such as a refused
+// connection or a timeout, that may succeed when retried.
+func isRetryable(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
 }
 
 // WaitForEndpoints polls the given webhook URLs until they respond successfully
@@ -187,11 +380,61 @@ func (c *Client) Call(
 //	)
 //
 // The method will wait for each endpoint sequentially in the order provided.
+// Use WaitForMultipleEndpoints to wait for them in parallel.
 func (c *Client) WaitForEndpoints(
 	ctx context.Context,
 	webhookURLs []string,
 	opts ...WaitOption,
 ) error {
+	waitOpts := newWaitOptions(opts)
+
+	for _, webhookURL := range webhookURLs {
+		if err := c.waitForEndpoint(ctx, webhookURL, waitOpts); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WaitForMultipleEndpoints polls the given webhook URLs in parallel until they
+// all respond successfully. The first failing endpoint cancels the others.
+//
+// Either a single client is provided and used for all the endpoints, or one
+// client per endpoint, matched by index. The number of endpoints polled at the
+// same time can be limited with WithParallelism.
+func WaitForMultipleEndpoints(
+	ctx context.Context,
+	clients []*Client,
+	endpoints []string,
+	opts ...WaitOption,
+) error {
+	if len(clients) != 1 && len(clients) != len(endpoints) {
+		return fmt.Errorf("expected 1 or %d clients, got %d", len(endpoints), len(clients))
+	}
+
+	waitOpts := newWaitOptions(opts)
+
+	g, gctx := errgroup.WithContext(ctx)
+	if waitOpts.Parallelism > 0 {
+		g.SetLimit(waitOpts.Parallelism)
+	}
+
+	for i, endpoint := range endpoints {
+		c := clients[0]
+		if len(clients) > 1 {
+			c = clients[i]
+		}
+
+		g.Go(func() error {
+			return c.waitForEndpoint(gctx, endpoint, waitOpts)
+		})
+	}
+
+	return g.Wait()
+}
+
+func newWaitOptions(opts []WaitOption) *WaitOptions {
 	waitOpts := &WaitOptions{
 		PollInterval: DefaultPollInterval,
 		ReadyTimeout: DefaultReadyTimeout,
@@ -199,33 +442,39 @@ func (c *Client) WaitForEndpoints(
 	}
 	waitOpts.ApplyOptions(opts)
 
-	healthCheckReview := newHealthCheckReview()
+	return waitOpts
+}
 
-	for _, webhookURL := range webhookURLs {
-		parsedURL, err := url.Parse(webhookURL)
-		if err != nil {
-			return fmt.Errorf("invalid webhook URL %s: %w", webhookURL, err)
-		}
+func (c *Client) waitForEndpoint(
+	ctx context.Context,
+	webhookURL string,
+	waitOpts *WaitOptions,
+) error {
+	parsedURL, err := url.Parse(webhookURL)
+	if err != nil {
+		return fmt.Errorf("invalid webhook URL %s: %w", webhookURL, err)
+	}
 
-		path := parsedURL.Path
-		if path == "" {
-			path = "/"
-		}
+	path := parsedURL.Path
+	if path == "" {
+		path = "/"
+	}
 
-		err = wait.PollUntilContextTimeout(
-			ctx,
-			waitOpts.PollInterval,
-			waitOpts.ReadyTimeout,
-			true,
-			func(ctx context.Context) (bool, error) {
-				_, err := c.Call(ctx, path, healthCheckReview, WithCallTimeout(waitOpts.CallTimeout))
-				return err == nil, nil
-			},
-		)
+	healthCheckReview := newHealthCheckReview()
 
-		if err != nil {
-			return fmt.Errorf("webhook endpoint %s not ready: %w", path, err)
-		}
+	err = wait.PollUntilContextTimeout(
+		ctx,
+		waitOpts.PollInterval,
+		waitOpts.ReadyTimeout,
+		true,
+		func(ctx context.Context) (bool, error) {
+			_, err := c.Call(ctx, path, healthCheckReview, WithCallTimeout(waitOpts.CallTimeout))
+			return err == nil, nil
+		},
+	)
+
+	if err != nil {
+		return fmt.Errorf("webhook endpoint %s not ready: %w", path, err)
 	}
 
 	return nil
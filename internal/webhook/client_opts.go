@@ -1,6 +1,12 @@
 package webhook
 
-import "time"
+import (
+	"net/http"
+	"slices"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
 
 // Default values for webhook operations.
 const (
@@ -26,6 +32,29 @@ type ClientOptions struct {
 	// CACert is the CA certificate for verifying the webhook server's TLS certificate.
 	// If empty, TLS verification will be skipped (insecure).
 	CACert []byte
+
+	// Retry configures retries of requests failing with network-level errors
+	// (connection refused, timeout). If nil, requests are not retried.
+	Retry *RetryOptions
+
+	// Scheme is used by ConversionClient to encode and decode typed objects.
+	Scheme *runtime.Scheme
+
+	// Headers are additional HTTP headers sent with every request.
+	// Content-Type and Accept are ignored.
+	Headers http.Header
+}
+
+// RetryOptions contains the retry configuration for the webhook client.
+type RetryOptions struct {
+	// MaxAttempts is the maximum number of attempts, including the first one.
+	MaxAttempts int
+
+	// Backoff is the delay before the first retry.
+	Backoff time.Duration
+
+	// Exponential doubles the backoff after each retry.
+	Exponential bool
 }
 
 // ApplyOptions applies a list of ClientOptions to the ClientOptions.
@@ -42,6 +71,15 @@ func (o *ClientOptions) ApplyToClientOptions(target *ClientOptions) {
 	if len(o.CACert) > 0 {
 		target.CACert = o.CACert
 	}
+	if o.Retry != nil {
+		target.Retry = o.Retry
+	}
+	if o.Scheme != nil {
+		target.Scheme = o.Scheme
+	}
+	if len(o.Headers) > 0 {
+		target.Headers = mergeHeaders(target.Headers, o.Headers)
+	}
 }
 
 // WithClientCACert configures the CA certificate for TLS verification.
@@ -52,6 +90,49 @@ func WithClientCACert(cert []byte) ClientOption {
 	})
 }
 
+// WithClientScheme configures the scheme used to encode and decode typed objects.
+func WithClientScheme(scheme *runtime.Scheme) ClientOption {
+	return clientOptionFunc(func(o *ClientOptions) {
+		o.Scheme = scheme
+	})
+}
+
+// WithRequestHeaders configures additional HTTP headers, such as custom
+// authentication or trace IDs, sent with every request of the client.
+// Headers set with WithCallHeaders take precedence over these ones.
+//
+// Content-Type and Accept headers are silently ignored, as they are required
+// by the webhook protocol.
+func WithRequestHeaders(headers http.Header) ClientOption {
+	return clientOptionFunc(func(o *ClientOptions) {
+		o.Headers = mergeHeaders(o.Headers, headers)
+	})
+}
+
+// WithRetry configures the client to retry requests failing with network-level
+// errors up to maxAttempts times, waiting backoff between attempts.
+// Webhook responses, including rejections with 4xx status codes, are never retried.
+func WithRetry(maxAttempts int, backoff time.Duration) ClientOption {
+	return clientOptionFunc(func(o *ClientOptions) {
+		o.Retry = &RetryOptions{
+			MaxAttempts: maxAttempts,
+			Backoff:     backoff,
+		}
+	})
+}
+
+// WithExponentialRetry is like WithRetry but doubles the backoff after each attempt,
+// starting from initialBackoff.
+func WithExponentialRetry(maxAttempts int, initialBackoff time.Duration) ClientOption {
+	return clientOptionFunc(func(o *ClientOptions) {
+		o.Retry = &RetryOptions{
+			MaxAttempts: maxAttempts,
+			Backoff:     initialBackoff,
+			Exponential: true,
+		}
+	})
+}
+
 // CallOption configures individual Call method invocations.
 type CallOption interface {
 	ApplyToCallOptions(opts *CallOptions)
@@ -68,6 +149,10 @@ type CallOptions struct {
 	// Timeout for the HTTP request.
 	// Default: 10s
 	Timeout time.Duration
+
+	// Headers are additional HTTP headers sent with the request. They take
+	// precedence over the client headers. Content-Type and Accept are ignored.
+	Headers http.Header
 }
 
 // WithCallTimeout sets a custom timeout for a single Call invocation.
@@ -77,6 +162,33 @@ func WithCallTimeout(timeout time.Duration) CallOption {
 	})
 }
 
+// WithCallHeaders sets additional HTTP headers for a single Call invocation.
+// They are merged with the client headers configured with WithRequestHeaders,
+// taking precedence for headers set at both levels.
+//
+// Content-Type and Accept headers are silently ignored, as they are required
+// by the webhook protocol.
+func WithCallHeaders(headers http.Header) CallOption {
+	return callOptionFunc(func(opts *CallOptions) {
+		opts.Headers = mergeHeaders(opts.Headers, headers)
+	})
+}
+
+// mergeHeaders returns a copy of base with the values of overrides replacing
+// the ones of the same header.
+func mergeHeaders(base http.Header, overrides http.Header) http.Header {
+	merged := base.Clone()
+	if merged == nil {
+		merged = make(http.Header, len(overrides))
+	}
+
+	for key, values := range overrides {
+		merged[http.CanonicalHeaderKey(key)] = slices.Clone(values)
+	}
+
+	return merged
+}
+
 // WaitOption configures the WaitForEndpoints method.
 type WaitOption interface {
 	ApplyToWaitOptions(opts *WaitOptions)
@@ -101,6 +213,10 @@ type WaitOptions struct {
 	// CallTimeout is the timeout for each individual health check call.
 	// Default: 10s
 	CallTimeout time.Duration
+
+	// Parallelism limits the number of endpoints polled at the same time by
+	// WaitForMultipleEndpoints. Zero or negative means no limit.
+	Parallelism int
 }
 
 // WithPollInterval sets the interval between readiness check retries.
@@ -124,6 +240,14 @@ func WithWaitCallTimeout(timeout time.Duration) WaitOption {
 	})
 }
 
+// WithParallelism limits the number of endpoints polled at the same time by
+// WaitForMultipleEndpoints.
+func WithParallelism(n int) WaitOption {
+	return waitOptionFunc(func(opts *WaitOptions) {
+		opts.Parallelism = n
+	})
+}
+
 func (opts *WaitOptions) ApplyOptions(options []WaitOption) {
 	for _, opt := range options {
 		opt.ApplyToWaitOptions(opts)
@@ -3,9 +3,13 @@ package webhook_test
 import (
 	"context"
 	"encoding/json"
+	"encoding/pem"
+	"errors"
 	"net"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -14,6 +18,7 @@ import (
 	admissionv1 "k8s.io/api/admission/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
 
 	. "github.com/onsi/gomega"
 )
@@ -36,6 +41,58 @@ func TestNewClient_EmptyHost(t *testing.T) {
 	g.Expect(client).To(BeNil())
 }
 
+func TestNewClientFromConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		host    string
+		address string
+	}{
+		{"url with scheme", "https://127.0.0.1:6443", "127.0.0.1:9443"},
+		{"url with path", "https://localhost:6443/prefix", "localhost:9443"},
+		{"host without scheme", "localhost:6443", "localhost:9443"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			client, err := webhook.NewClientFromConfig(&rest.Config{Host: tt.host}, 9443)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(client.Address()).To(Equal(tt.address))
+		})
+	}
+}
+
+func TestNewClientFromConfig_CADataTakesPrecedence(t *testing.T) {
+	g := NewWithT(t)
+
+	var calls atomic.Int32
+	server := newHealthyServer(t, 0, &calls)
+	addr := server.Listener.Addr().(*net.TCPAddr)
+
+	caData := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	// The invalid CA certificate would make NewClient fail if it was used.
+	client, err := webhook.NewClientFromConfig(
+		&rest.Config{Host: server.URL, TLSClientConfig: rest.TLSClientConfig{CAData: caData}},
+		addr.Port,
+		webhook.WithClientCACert([]byte("invalid")),
+	)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	err = client.CheckEndpoint(context.Background(), "/validate")
+	g.Expect(err).NotTo(HaveOccurred())
+}
+
+func TestNewClientFromConfig_NilConfig(t *testing.T) {
+	g := NewWithT(t)
+
+	client, err := webhook.NewClientFromConfig(nil, 9443)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("config cannot be nil"))
+	g.Expect(client).To(BeNil())
+}
+
 func TestNewClient_InvalidPort(t *testing.T) {
 	tests := []struct {
 		name string
@@ -273,3 +330,330 @@ func TestCall_InvalidResponse(t *testing.T) {
 	g.Expect(err.Error()).To(ContainSubstring("failed to unmarshal"))
 	g.Expect(resp).To(BeNil())
 }
+
+// reservePort returns a local port with no listener bound to it.
+func reservePort(t *testing.T) int {
+	t.Helper()
+	g := NewWithT(t)
+
+	lc := net.ListenConfig{}
+	listener, err := lc.Listen(context.Background(), "tcp", "127.0.0.1:0")
+	g.Expect(err).NotTo(HaveOccurred())
+
+	port := listener.Addr().(*net.TCPAddr).Port
+	g.Expect(listener.Close()).To(Succeed())
+
+	return port
+}
+
+func TestCall_Retry_ConnectionRefused(t *testing.T) {
+	g := NewWithT(t)
+
+	client, err := webhook.NewClient("127.0.0.1", reservePort(t),
+		webhook.WithRetry(3, 20*time.Millisecond))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	start := time.Now()
+	resp, err := client.Call(context.Background(), "/validate", admissionv1.AdmissionReview{})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("after 3 attempts"))
+	g.Expect(resp).To(BeNil())
+	g.Expect(time.Since(start)).To(BeNumerically(">=", 40*time.Millisecond))
+}
+
+func TestCall_Retry_ServerStartsLate(t *testing.T) {
+	g := NewWithT(t)
+
+	port := reservePort(t)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(admissionv1.AdmissionReview{
+			Response: &admissionv1.AdmissionResponse{Allowed: true},
+		})
+	}))
+
+	started := make(chan struct{})
+	defer func() {
+		<-started
+		server.Close()
+	}()
+
+	go func() {
+		defer close(started)
+
+		time.Sleep(100 * time.Millisecond)
+
+		lc := net.ListenConfig{}
+		listener, err := lc.Listen(context.Background(), "tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)))
+		if err != nil {
+			return
+		}
+
+		_ = server.Listener.Close()
+		server.Listener = listener
+		server.StartTLS()
+	}()
+
+	client, err := webhook.NewClient("127.0.0.1", port,
+		webhook.WithExponentialRetry(10, 20*time.Millisecond))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	resp, err := client.Call(context.Background(), "/validate", admissionv1.AdmissionReview{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(resp.Response.Allowed).To(BeTrue())
+}
+
+func TestCall_Retry_NotOnClientError(t *testing.T) {
+	g := NewWithT(t)
+
+	var calls atomic.Int32
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusForbidden)
+		_ = json.NewEncoder(w).Encode(admissionv1.AdmissionReview{
+			Response: &admissionv1.AdmissionResponse{Allowed: false},
+		})
+	}))
+	defer server.Close()
+
+	client, err := webhook.NewClient(server.Listener.Addr().(*net.TCPAddr).IP.String(),
+		server.Listener.Addr().(*net.TCPAddr).Port,
+		webhook.WithRetry(5, 10*time.Millisecond))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	resp, err := client.Call(context.Background(), "/validate", admissionv1.AdmissionReview{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(resp.Response.Allowed).To(BeFalse())
+	g.Expect(calls.Load()).To(Equal(int32(1)))
+}
+
+func TestCall_Retry_ContextCancelled(t *testing.T) {
+	g := NewWithT(t)
+
+	client, err := webhook.NewClient("127.0.0.1", reservePort(t),
+		webhook.WithRetry(100, time.Second))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err = client.Call(ctx, "/validate", admissionv1.AdmissionReview{})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err).To(MatchError(context.DeadlineExceeded))
+	g.Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+}
+
+func newHealthyServer(t *testing.T, delay time.Duration, calls *atomic.Int32) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		time.Sleep(delay)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(admissionv1.AdmissionReview{
+			Response: &admissionv1.AdmissionResponse{Allowed: true},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestWaitForMultipleEndpoints_Parallel(t *testing.T) {
+	g := NewWithT(t)
+
+	var calls atomic.Int32
+	server := newHealthyServer(t, 200*time.Millisecond, &calls)
+
+	client, err := webhook.NewClient(server.Listener.Addr().(*net.TCPAddr).IP.String(),
+		server.Listener.Addr().(*net.TCPAddr).Port)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	endpoints := []string{
+		server.URL + "/validate1",
+		server.URL + "/validate2",
+		server.URL + "/validate3",
+		server.URL + "/validate4",
+	}
+
+	start := time.Now()
+	err = webhook.WaitForMultipleEndpoints(context.Background(), []*webhook.Client{client}, endpoints)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(calls.Load()).To(Equal(int32(len(endpoints))))
+
+	// Sequential polling would take at least 4 * 200ms
+	g.Expect(time.Since(start)).To(BeNumerically("<", 600*time.Millisecond))
+}
+
+func TestWaitForMultipleEndpoints_Parallelism(t *testing.T) {
+	g := NewWithT(t)
+
+	var calls atomic.Int32
+	server := newHealthyServer(t, 100*time.Millisecond, &calls)
+
+	client, err := webhook.NewClient(server.Listener.Addr().(*net.TCPAddr).IP.String(),
+		server.Listener.Addr().(*net.TCPAddr).Port)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	endpoints := []string{server.URL + "/a", server.URL + "/b", server.URL + "/c"}
+
+	start := time.Now()
+	err = webhook.WaitForMultipleEndpoints(context.Background(), []*webhook.Client{client}, endpoints,
+		webhook.WithParallelism(1))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(time.Since(start)).To(BeNumerically(">=", 300*time.Millisecond))
+}
+
+func TestWaitForMultipleEndpoints_FirstErrorCancelsOthers(t *testing.T) {
+	g := NewWithT(t)
+
+	unreachable, err := webhook.NewClient("127.0.0.1", reservePort(t))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	start := time.Now()
+	err = webhook.WaitForMultipleEndpoints(
+		context.Background(),
+		[]*webhook.Client{unreachable, unreachable},
+		[]string{"https://127.0.0.1/fast", "https://127.0.0.1/slow"},
+		webhook.WithPollInterval(20*time.Millisecond),
+		webhook.WithReadyTimeout(200*time.Millisecond),
+	)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("not ready"))
+	g.Expect(time.Since(start)).To(BeNumerically("<", 2*time.Second))
+}
+
+func TestWaitForMultipleEndpoints_ClientCountMismatch(t *testing.T) {
+	g := NewWithT(t)
+
+	client, err := webhook.NewClient("localhost", 9443)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	err = webhook.WaitForMultipleEndpoints(context.Background(),
+		[]*webhook.Client{client, client},
+		[]string{"https://localhost/a", "https://localhost/b", "https://localhost/c"},
+	)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("expected 1 or 3 clients, got 2"))
+}
+
+func TestCheckEndpoint(t *testing.T) {
+	g := NewWithT(t)
+
+	var calls atomic.Int32
+	server := newHealthyServer(t, 0, &calls)
+	addr := server.Listener.Addr().(*net.TCPAddr)
+
+	client, err := webhook.NewClient(addr.IP.String(), addr.Port)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	err = client.CheckEndpoint(context.Background(), "/validate")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(calls.Load()).To(Equal(int32(1)))
+}
+
+func TestCheckEndpoint_Unreachable(t *testing.T) {
+	g := NewWithT(t)
+
+	client, err := webhook.NewClient("127.0.0.1", reservePort(t))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	err = client.CheckEndpoint(context.Background(), "/validate")
+	g.Expect(err).To(HaveOccurred())
+
+	var unreachableErr *webhook.EndpointUnreachableError
+	g.Expect(errors.As(err, &unreachableErr)).To(BeTrue())
+	g.Expect(unreachableErr.Path).To(Equal("/validate"))
+}
+
+func TestCheckEndpoint_IgnoresRetry(t *testing.T) {
+	g := NewWithT(t)
+
+	client, err := webhook.NewClient("127.0.0.1", reservePort(t),
+		webhook.WithRetry(100, time.Second))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	start := time.Now()
+	err = client.CheckEndpoint(context.Background(), "/validate")
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+
+	var unreachableErr *webhook.EndpointUnreachableError
+	g.Expect(errors.As(err, &unreachableErr)).To(BeTrue())
+}
+
+func TestCheckEndpoint_NonSuccessStatus(t *testing.T) {
+	g := NewWithT(t)
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	addr := server.Listener.Addr().(*net.TCPAddr)
+
+	client, err := webhook.NewClient(addr.IP.String(), addr.Port)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	err = client.CheckEndpoint(context.Background(), "/missing")
+	g.Expect(err).To(HaveOccurred())
+
+	var statusErr *webhook.EndpointStatusError
+	g.Expect(errors.As(err, &statusErr)).To(BeTrue())
+	g.Expect(statusErr.Path).To(Equal("/missing"))
+	g.Expect(statusErr.StatusCode).To(Equal(http.StatusNotFound))
+}
+
+func TestClient_Call_Headers(t *testing.T) {
+	g := NewWithT(t)
+
+	var received http.Header
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(admissionv1.AdmissionReview{
+			Response: &admissionv1.AdmissionResponse{Allowed: true},
+		})
+	}))
+	defer server.Close()
+
+	addr := server.Listener.Addr().(*net.TCPAddr)
+
+	client, err := webhook.NewClient(addr.IP.String(), addr.Port,
+		webhook.WithRequestHeaders(http.Header{
+			"X-Trace-Id":    []string{"client-trace"},
+			"Authorization": []string{"Bearer client"},
+			"Content-Type":  []string{"text/plain"},
+		}),
+	)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	review := admissionv1.AdmissionReview{
+		Request: &admissionv1.AdmissionRequest{UID: types.UID("test-uid")},
+	}
+
+	_, err = client.Call(context.Background(), "/validate", review,
+		webhook.WithCallHeaders(http.Header{
+			"authorization": []string{"Bearer call"},
+			"X-Debug":       []string{"true"},
+			"Accept":        []string{"text/html"},
+		}),
+	)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(received.Get("X-Trace-Id")).To(Equal("client-trace"))
+	g.Expect(received.Get("X-Debug")).To(Equal("true"))
+	g.Expect(received.Values("Authorization")).To(Equal([]string{"Bearer call"}))
+	g.Expect(received.Values("Content-Type")).To(Equal([]string{"application/json"}))
+	g.Expect(received.Values("Accept")).To(Equal([]string{"application/json"}))
+
+	// Call level headers do not leak into subsequent calls
+	_, err = client.Call(context.Background(), "/validate", review)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(received.Values("Authorization")).To(Equal([]string{"Bearer client"}))
+	g.Expect(received.Get("X-Debug")).To(BeEmpty())
+}
@@ -0,0 +1,92 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/uuid"
+)
+
+// DefaultConversionPath is the path CRD conversion webhooks are served on,
+// matching the controller-runtime webhook server default.
+const DefaultConversionPath = "/convert"
+
+// NewConversionRequest creates a ConversionReview asking to convert the given
+// objects to toVersion (e.g. "example.k3senv.io/v1beta1"). Raw objects that do
+// not set an apiVersion are assumed to be in fromVersion.
+func NewConversionRequest(
+	fromVersion string,
+	toVersion string,
+	objects []runtime.RawExtension,
+) apiextensionsv1.ConversionReview {
+	requestObjects := make([]runtime.RawExtension, len(objects))
+	for i := range objects {
+		requestObjects[i] = withDefaultAPIVersion(objects[i], fromVersion)
+	}
+
+	return apiextensionsv1.ConversionReview{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: apiextensionsv1.SchemeGroupVersion.String(),
+			Kind:       "ConversionReview",
+		},
+		Request: &apiextensionsv1.ConversionRequest{
+			UID:               uuid.NewUUID(),
+			DesiredAPIVersion: toVersion,
+			Objects:           requestObjects,
+		},
+	}
+}
+
+// SendConversionReview sends a ConversionReview to the conversion webhook path
+// and returns the ConversionReview response.
+//
+// As for Call, 5xx status codes are reported as errors. Conversion failures
+// reported by the webhook are available in the response Result.
+func (c *Client) SendConversionReview(
+	ctx context.Context,
+	review apiextensionsv1.ConversionReview,
+	opts ...CallOption,
+) (*apiextensionsv1.ConversionReview, error) {
+	if review.APIVersion == "" {
+		review.APIVersion = apiextensionsv1.SchemeGroupVersion.String()
+	}
+	if review.Kind == "" {
+		review.Kind = "ConversionReview"
+	}
+
+	var reviewResp apiextensionsv1.ConversionReview
+	if err := c.call(ctx, DefaultConversionPath, "ConversionReview", review, &reviewResp, opts...); err != nil {
+		return nil, err
+	}
+
+	return &reviewResp, nil
+}
+
+// withDefaultAPIVersion sets apiVersion on a raw JSON object that does not have one.
+// Objects that cannot be decoded are returned unchanged and left to the webhook to reject.
+func withDefaultAPIVersion(obj runtime.RawExtension, apiVersion string) runtime.RawExtension {
+	if apiVersion == "" || len(obj.Raw) == 0 {
+		return obj
+	}
+
+	content := map[string]any{}
+	if err := json.Unmarshal(obj.Raw, &content); err != nil {
+		return obj
+	}
+
+	if v, ok := content["apiVersion"].(string); ok && v != "" {
+		return obj
+	}
+
+	content["apiVersion"] = apiVersion
+
+	raw, err := json.Marshal(content)
+	if err != nil {
+		return obj
+	}
+
+	return runtime.RawExtension{Raw: raw}
+}
@@ -0,0 +1,166 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ConversionFailedError is returned when a conversion webhook reports a
+// failed conversion in the ConversionReview response Result.
+type ConversionFailedError struct {
+	Status metav1.Status
+}
+
+func (e *ConversionFailedError) Error() string {
+	return fmt.Sprintf("conversion failed: %s (reason: %s, code: %d)", e.Status.Message, e.Status.Reason, e.Status.Code)
+}
+
+// ConversionClient is a webhook testing client dedicated to CRD conversion
+// webhooks. It converts typed or unstructured objects and hides the
+// ConversionReview wire format.
+type ConversionClient struct {
+	client *Client
+	scheme *runtime.Scheme
+}
+
+// NewConversionClient creates a new conversion webhook client. Options are the
+// same as NewClient. If a scheme is configured with WithClientScheme, converted
+// objects are returned as typed objects, otherwise as *unstructured.Unstructured.
+func NewConversionClient(host string, port int, opts ...ClientOption) (*ConversionClient, error) {
+	c, err := NewClient(host, port, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ConversionClient{
+		client: c,
+		scheme: c.opts.Scheme,
+	}, nil
+}
+
+// Address returns the base address (host:port) that the client connects to.
+func (c *ConversionClient) Address() string {
+	return c.client.Address()
+}
+
+// Convert sends from to the conversion webhook and returns it converted to
+// toAPIVersion (e.g. "example.k3senv.io/v1beta1").
+//
+// A *ConversionFailedError is returned if the webhook reports the conversion
+// as failed.
+func (c *ConversionClient) Convert(
+	ctx context.Context,
+	from runtime.Object,
+	toAPIVersion string,
+	opts ...CallOption,
+) (runtime.Object, error) {
+	fromGVK, err := c.objectKind(from)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := c.encode(from, fromGVK)
+	if err != nil {
+		return nil, err
+	}
+
+	review := NewConversionRequest(fromGVK.GroupVersion().String(), toAPIVersion, []runtime.RawExtension{{Raw: raw}})
+
+	resp, err := c.client.SendConversionReview(ctx, review, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Response == nil {
+		return nil, errors.New("conversion webhook returned an empty response")
+	}
+	if resp.Response.UID != review.Request.UID {
+		return nil, fmt.Errorf("conversion response UID %q does not match request UID %q", resp.Response.UID, review.Request.UID)
+	}
+	if resp.Response.Result.Status != metav1.StatusSuccess {
+		return nil, &ConversionFailedError{Status: resp.Response.Result}
+	}
+	if len(resp.Response.ConvertedObjects) != 1 {
+		return nil, fmt.Errorf("expected 1 converted object, got %d", len(resp.Response.ConvertedObjects))
+	}
+
+	return c.decode(resp.Response.ConvertedObjects[0].Raw, fromGVK.Kind, toAPIVersion)
+}
+
+func (c *ConversionClient) objectKind(obj runtime.Object) (schema.GroupVersionKind, error) {
+	gvk := obj.GetObjectKind().GroupVersionKind()
+	if !gvk.Empty() {
+		return gvk, nil
+	}
+
+	if c.scheme == nil {
+		return schema.GroupVersionKind{}, fmt.Errorf("object %T has no apiVersion/kind and no scheme is configured", obj)
+	}
+
+	gvks, _, err := c.scheme.ObjectKinds(obj)
+	if err != nil {
+		return schema.GroupVersionKind{}, fmt.Errorf("failed to determine kind of %T: %w", obj, err)
+	}
+
+	return gvks[0], nil
+}
+
+func (c *ConversionClient) encode(obj runtime.Object, gvk schema.GroupVersionKind) ([]byte, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert %T to unstructured: %w", obj, err)
+	}
+
+	u := unstructured.Unstructured{Object: content}
+	u.SetGroupVersionKind(gvk)
+
+	raw, err := json.Marshal(u.Object)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s: %w", gvk.Kind, err)
+	}
+
+	return raw, nil
+}
+
+func (c *ConversionClient) decode(raw []byte, kind string, apiVersion string) (runtime.Object, error) {
+	u := &unstructured.Unstructured{}
+	if err := json.Unmarshal(raw, &u.Object); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal converted object: %w", err)
+	}
+
+	if u.GetAPIVersion() != apiVersion {
+		return nil, fmt.Errorf("converted object has apiVersion %q, expected %q", u.GetAPIVersion(), apiVersion)
+	}
+
+	if c.scheme == nil {
+		return u, nil
+	}
+
+	gvk := u.GroupVersionKind()
+	if gvk.Kind == "" {
+		gvk.Kind = kind
+	}
+
+	obj, err := c.scheme.New(gvk)
+	if err != nil {
+		if runtime.IsNotRegisteredError(err) {
+			return u, nil
+		}
+		return nil, fmt.Errorf("failed to create %s: %w", gvk, err)
+	}
+
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, obj); err != nil {
+		return nil, fmt.Errorf("failed to convert object to %T: %w", obj, err)
+	}
+
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+
+	return obj, nil
+}
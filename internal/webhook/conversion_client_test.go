@@ -0,0 +1,117 @@
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lburgazzoli/k3s-envtest/internal/testdata/v1alpha1"
+	"github.com/lburgazzoli/k3s-envtest/internal/testdata/v1beta1"
+	"github.com/lburgazzoli/k3s-envtest/internal/webhook"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestConversionClient_Convert_Typed(t *testing.T) {
+	g := NewWithT(t)
+
+	addr := newConversionServer(t)
+
+	client, err := webhook.NewConversionClient(addr.IP.String(), addr.Port,
+		webhook.WithClientScheme(newConversionScheme(t)))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// TypeMeta is left empty on purpose, the kind is resolved using the scheme
+	original := &v1alpha1.SampleResource{
+		ObjectMeta: metav1.ObjectMeta{Name: "sample", Namespace: "default"},
+		Spec:       v1alpha1.SampleResourceSpec{FieldAlpha: "value"},
+	}
+
+	converted, err := client.Convert(context.Background(), original, v1beta1.GroupVersion.String())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(converted).To(BeAssignableToTypeOf(&v1beta1.SampleResource{}))
+
+	sample := converted.(*v1beta1.SampleResource)
+	g.Expect(sample.Name).To(Equal("sample"))
+	g.Expect(sample.Spec.FieldBeta).To(Equal("value"))
+}
+
+func TestConversionClient_Convert_Unstructured(t *testing.T) {
+	g := NewWithT(t)
+
+	addr := newConversionServer(t)
+
+	client, err := webhook.NewConversionClient(addr.IP.String(), addr.Port)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	original := &unstructured.Unstructured{}
+	original.SetAPIVersion(v1beta1.GroupVersion.String())
+	original.SetKind("SampleResource")
+	original.SetName("sample")
+	g.Expect(unstructured.SetNestedField(original.Object, "value", "spec", "fieldBeta")).To(Succeed())
+
+	converted, err := client.Convert(context.Background(), original, v1alpha1.GroupVersion.String())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(converted).To(BeAssignableToTypeOf(&unstructured.Unstructured{}))
+
+	u := converted.(*unstructured.Unstructured)
+	g.Expect(u.GetAPIVersion()).To(Equal(v1alpha1.GroupVersion.String()))
+	g.Expect(u.Object).To(HaveKeyWithValue("spec", HaveKeyWithValue("fieldAlpha", "value")))
+}
+
+func TestConversionClient_Convert_NoKind(t *testing.T) {
+	g := NewWithT(t)
+
+	client, err := webhook.NewConversionClient("localhost", 9443)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	_, err = client.Convert(context.Background(), &v1alpha1.SampleResource{}, v1beta1.GroupVersion.String())
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("no scheme is configured"))
+}
+
+func TestConversionClient_Convert_Failure(t *testing.T) {
+	g := NewWithT(t)
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var review apiextensionsv1.ConversionReview
+		_ = json.NewDecoder(r.Body).Decode(&review)
+
+		review.Response = &apiextensionsv1.ConversionResponse{
+			UID: review.Request.UID,
+			Result: metav1.Status{
+				Status:  metav1.StatusFailure,
+				Message: "unsupported version",
+				Reason:  metav1.StatusReasonBadRequest,
+				Code:    http.StatusBadRequest,
+			},
+		}
+		review.Request = nil
+		_ = json.NewEncoder(w).Encode(review)
+	}))
+	defer server.Close()
+
+	client, err := webhook.NewConversionClient(server.Listener.Addr().(*net.TCPAddr).IP.String(),
+		server.Listener.Addr().(*net.TCPAddr).Port)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	original := &unstructured.Unstructured{}
+	original.SetAPIVersion(v1alpha1.GroupVersion.String())
+	original.SetKind("SampleResource")
+
+	_, err = client.Convert(context.Background(), original, v1beta1.GroupVersion.String())
+	g.Expect(err).To(HaveOccurred())
+
+	var failed *webhook.ConversionFailedError
+	g.Expect(errors.As(err, &failed)).To(BeTrue())
+	g.Expect(failed.Status.Message).To(Equal("unsupported version"))
+	g.Expect(failed.Status.Code).To(Equal(int32(http.StatusBadRequest)))
+}
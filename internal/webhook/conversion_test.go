@@ -0,0 +1,153 @@
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lburgazzoli/k3s-envtest/internal/testdata/v1alpha1"
+	"github.com/lburgazzoli/k3s-envtest/internal/testdata/v1beta1"
+	"github.com/lburgazzoli/k3s-envtest/internal/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	. "github.com/onsi/gomega"
+)
+
+func newConversionScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+	g.Expect(v1beta1.AddToScheme(scheme)).To(Succeed())
+
+	return scheme
+}
+
+func newConversionServer(t *testing.T) *net.TCPAddr {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.Handle(webhook.DefaultConversionPath, conversion.NewWebhookHandler(newConversionScheme(t), conversion.NewRegistry()))
+
+	server := httptest.NewTLSServer(mux)
+	t.Cleanup(server.Close)
+
+	return server.Listener.Addr().(*net.TCPAddr)
+}
+
+func toRaw(t *testing.T, obj runtime.Object) runtime.RawExtension {
+	t.Helper()
+
+	raw, err := json.Marshal(obj)
+	NewWithT(t).Expect(err).NotTo(HaveOccurred())
+
+	return runtime.RawExtension{Raw: raw}
+}
+
+func TestNewConversionRequest(t *testing.T) {
+	g := NewWithT(t)
+
+	review := webhook.NewConversionRequest(
+		v1alpha1.GroupVersion.String(),
+		v1beta1.GroupVersion.String(),
+		[]runtime.RawExtension{{Raw: []byte(`{"kind":"SampleResource"}`)}},
+	)
+
+	g.Expect(review.APIVersion).To(Equal("apiextensions.k8s.io/v1"))
+	g.Expect(review.Kind).To(Equal("ConversionReview"))
+	g.Expect(review.Request).NotTo(BeNil())
+	g.Expect(review.Request.UID).NotTo(BeEmpty())
+	g.Expect(review.Request.DesiredAPIVersion).To(Equal(v1beta1.GroupVersion.String()))
+	g.Expect(review.Request.Objects).To(HaveLen(1))
+	g.Expect(string(review.Request.Objects[0].Raw)).To(ContainSubstring(`"apiVersion":"example.k3senv.io/v1alpha1"`))
+}
+
+func TestSendConversionReview_RoundTrip(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	addr := newConversionServer(t)
+
+	client, err := webhook.NewClient(addr.IP.String(), addr.Port)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	original := &v1alpha1.SampleResource{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.GroupVersion.String(),
+			Kind:       "SampleResource",
+		},
+		ObjectMeta: metav1.ObjectMeta{Name: "sample", Namespace: "default"},
+		Spec:       v1alpha1.SampleResourceSpec{FieldAlpha: "value"},
+	}
+
+	// v1alpha1 -> v1beta1
+	resp, err := client.SendConversionReview(ctx, webhook.NewConversionRequest(
+		v1alpha1.GroupVersion.String(),
+		v1beta1.GroupVersion.String(),
+		[]runtime.RawExtension{toRaw(t, original)},
+	))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(resp.Response).NotTo(BeNil())
+	g.Expect(resp.Response.Result.Status).To(Equal(metav1.StatusSuccess))
+	g.Expect(resp.Response.ConvertedObjects).To(HaveLen(1))
+
+	converted := &v1beta1.SampleResource{}
+	g.Expect(json.Unmarshal(resp.Response.ConvertedObjects[0].Raw, converted)).To(Succeed())
+	g.Expect(converted.APIVersion).To(Equal(v1beta1.GroupVersion.String()))
+	g.Expect(converted.Spec.FieldBeta).To(Equal("value"))
+
+	// v1beta1 -> v1alpha1
+	resp, err = client.SendConversionReview(ctx, webhook.NewConversionRequest(
+		v1beta1.GroupVersion.String(),
+		v1alpha1.GroupVersion.String(),
+		[]runtime.RawExtension{resp.Response.ConvertedObjects[0]},
+	))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(resp.Response.Result.Status).To(Equal(metav1.StatusSuccess))
+	g.Expect(resp.Response.ConvertedObjects).To(HaveLen(1))
+
+	roundTripped := &v1alpha1.SampleResource{}
+	g.Expect(json.Unmarshal(resp.Response.ConvertedObjects[0].Raw, roundTripped)).To(Succeed())
+	g.Expect(roundTripped.Name).To(Equal(original.Name))
+	g.Expect(roundTripped.Spec).To(Equal(original.Spec))
+}
+
+func TestSendConversionReview_DefaultsTypeMeta(t *testing.T) {
+	g := NewWithT(t)
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		g.Expect(r.URL.Path).To(Equal(webhook.DefaultConversionPath))
+		g.Expect(r.Header.Get("Content-Type")).To(Equal("application/json"))
+
+		var review apiextensionsv1.ConversionReview
+		g.Expect(json.NewDecoder(r.Body).Decode(&review)).To(Succeed())
+		g.Expect(review.APIVersion).To(Equal("apiextensions.k8s.io/v1"))
+		g.Expect(review.Kind).To(Equal("ConversionReview"))
+
+		review.Response = &apiextensionsv1.ConversionResponse{
+			UID:    review.Request.UID,
+			Result: metav1.Status{Status: metav1.StatusFailure, Message: "boom"},
+		}
+		_ = json.NewEncoder(w).Encode(review)
+	}))
+	defer server.Close()
+
+	client, err := webhook.NewClient(server.Listener.Addr().(*net.TCPAddr).IP.String(),
+		server.Listener.Addr().(*net.TCPAddr).Port)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	resp, err := client.SendConversionReview(context.Background(), apiextensionsv1.ConversionReview{
+		Request: &apiextensionsv1.ConversionRequest{UID: "test-uid"},
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(resp.Response.Result.Status).To(Equal(metav1.StatusFailure))
+	g.Expect(resp.Response.Result.Message).To(Equal("boom"))
+}
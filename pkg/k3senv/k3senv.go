@@ -5,26 +5,41 @@ import (
 	"crypto/tls"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
 
+	cerrdefs "github.com/containerd/errdefs"
 	dockercontainer "github.com/docker/docker/api/types/container"
 	"github.com/lburgazzoli/k3s-envtest/internal/cert"
 	"github.com/lburgazzoli/k3s-envtest/internal/gvk"
 	"github.com/lburgazzoli/k3s-envtest/internal/resources"
 	"github.com/lburgazzoli/k3s-envtest/internal/resources/filter"
+	"github.com/lburgazzoli/k3s-envtest/internal/webhook"
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/modules/k3s"
 	"github.com/testcontainers/testcontainers-go/network"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 	ctrlwebhook "sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/utils/ptr"
@@ -46,13 +61,31 @@ const (
 
 	// WebhookConvertPath is the default path for CRD conversion webhook endpoints.
 	WebhookConvertPath = "/convert"
+
+	// DefaultFieldOwner is the field manager used to apply the resources
+	// installed by the environment.
+	DefaultFieldOwner = "k3s-envtest"
+
+	// k3sStartupRetryBackoff is the delay before the first container startup
+	// retry, doubled after each attempt.
+	k3sStartupRetryBackoff = time.Second
+
+	// k3sRegistriesConfigPath is the path of the registries configuration
+	// file in the k3s container.
+	k3sRegistriesConfigPath = "/etc/rancher/k3s/registries.yaml"
 )
 
 var (
-	// CertificateSANs contains the Subject Alternative Names (SANs) used when
+	// ErrEnvironmentPaused is returned by the operations of the client returned
+	// by Client while the environment is paused, see Pause.
+	ErrEnvironmentPaused = errors.New("environment paused - call Resume() first")
+
+	// CertificateSANs contains the default Subject Alternative Names (SANs) used when
 	// generating TLS certificates for webhook testing. This list includes common
 	// Docker networking hostnames and IP addresses to ensure webhooks can connect
 	// across different container networking configurations.
+	//
+	// Use WithCertSANs or WithAdditionalCertSANs to customize the list per environment.
 	CertificateSANs = []string{
 		"host.containers.internal", // Primary: works on both Docker and Podman
 		"host.docker.internal",
@@ -70,6 +103,17 @@ var (
 
 type TeardownTask func(context.Context) error
 
+// state is the lifecycle state of a K3sEnv.
+type state int32
+
+const (
+	stateNew state = iota
+	stateStarting
+	stateStarted
+	stateStopped
+	stateFailed
+)
+
 // CertificatePaths contains the file paths for all TLS certificates used by k3s-envtest.
 type CertificatePaths struct {
 	Dir     string // Base certificate directory
@@ -80,21 +124,70 @@ type CertificatePaths struct {
 
 // Manifests contains typed Kubernetes resources loaded from manifest files.
 type Manifests struct {
-	CustomResourceDefinitions       []apiextensionsv1.CustomResourceDefinition
-	MutatingWebhookConfigurations   []admissionregistrationv1.MutatingWebhookConfiguration
-	ValidatingWebhookConfigurations []admissionregistrationv1.ValidatingWebhookConfiguration
+	CustomResourceDefinitions         []apiextensionsv1.CustomResourceDefinition
+	MutatingWebhookConfigurations     []admissionregistrationv1.MutatingWebhookConfiguration
+	ValidatingWebhookConfigurations   []admissionregistrationv1.ValidatingWebhookConfiguration
+	ValidatingAdmissionPolicies       []admissionregistrationv1.ValidatingAdmissionPolicy
+	ValidatingAdmissionPolicyBindings []admissionregistrationv1.ValidatingAdmissionPolicyBinding
 }
 
 type K3sEnv struct {
-	container *k3s.K3sContainer
-	cfg       *rest.Config
-	cli       client.Client
+	container  *k3s.K3sContainer
+	kubeconfig []byte
+	cfg        *rest.Config
+	cli        client.WithWatch
+	mapper     meta.RESTMapper
+
+	// kubeconfigPath is the temporary file written by GetKubeconfigPath.
+	kubeconfigPath string
+
+	// clientsMu guards the typed clientsets, created on first use by
+	// GetClientset and GetExtensionsClientset.
+	clientsMu           sync.Mutex
+	clientset           *kubernetes.Clientset
+	extensionsClientset *apiextensionsclientset.Clientset
+
+	// versionMu guards the versions cached by GetKubernetesVersion and
+	// GetK3sVersion.
+	versionMu         sync.Mutex
+	kubernetesVersion *version.Info
+	k3sVersion        string
+
+	// cacheMu guards the cache started by StartCache.
+	cacheMu       sync.Mutex
+	informerCache cache.Cache
 
 	options Options
 
 	certData      *cert.Data
 	manifests     Manifests
 	teardownTasks []TeardownTask
+	webhookHost   string
+
+	// state is the lifecycle state of the environment, see IsStarted.
+	state atomic.Int32
+
+	// paused reports whether the k3s container is paused, see Pause.
+	paused atomic.Bool
+
+	// logger is the Logger of the options, which can be changed by SetLogger.
+	logger atomic.Pointer[Logger]
+
+	// cleanupRegistered reports whether Stop has been registered as a test
+	// cleanup, see WithTestingT and MustStart.
+	cleanupRegistered bool
+
+	// certPathGenerated reports whether the certificate path has been
+	// generated by the environment rather than provided by the user.
+	certPathGenerated bool
+
+	// installedWebhooks tracks the webhook configurations applied by
+	// InstallWebhooks, so that pre-existing ones are never uninstalled.
+	installedWebhooks []client.Object
+
+	// conversionCRDs tracks the names of the CRDs whose conversion webhook
+	// has been configured by InstallWebhooks.
+	conversionCRDs []string
 }
 
 func New(opts ...Option) (*K3sEnv, error) {
@@ -113,23 +206,42 @@ func New(opts ...Option) (*K3sEnv, error) {
 
 	if options.Scheme == nil {
 		options.Scheme = runtime.NewScheme()
+
+		// core types are always needed, e.g. by GetEvents
+		if err := corev1.AddToScheme(options.Scheme); err != nil {
+			return nil, fmt.Errorf("failed to register core types: %w", err)
+		}
+	}
+
+	if options.Webhook.HostResolver == nil {
+		options.Webhook.HostResolver = DefaultWebhookHostResolver
 	}
 
+	// Resolve certificate files once, so that a defaulted certificate path does
+	// not change how they are located later on.
+	options.Certificate.CertFile = options.Certificate.resolveFile(options.Certificate.CertFile)
+	options.Certificate.KeyFile = options.Certificate.resolveFile(options.Certificate.KeyFile)
+	options.Certificate.CAFile = options.Certificate.resolveFile(options.Certificate.CAFile)
+
 	env := &K3sEnv{
 		options:       *options,
 		teardownTasks: []TeardownTask{},
 	}
 
+	env.SetLogger(options.Logger)
+
 	return env, nil
 }
 
 // Start initializes and starts the k3s environment. It performs the following operations:
+// - Runs the PreStart hooks
 // - Starts k3s container using testcontainers-go
 // - Configures kubeconfig for cluster access
 // - Creates Kubernetes clients
 // - Generates TLS certificates for webhook testing
 // - Loads and installs CRDs (waits for them to be established)
 // - Optionally installs webhooks if AutoInstall is enabled
+// - Runs the PostStart hooks
 //
 // IMPORTANT: Always register cleanup immediately after New() to ensure proper resource cleanup:
 //
@@ -162,19 +274,59 @@ func New(opts ...Option) (*K3sEnv, error) {
 //	}
 //
 // The Stop() method is safe to call even if Start() fails partway through,
-// as it handles nil/uninitialized fields gracefully.
+// as it handles nil/uninitialized fields gracefully. A failed Start leaves the
+// environment failed, see IsFailed, and Stop must be called before starting
+// it again, so that the partially started resources are released.
 func (e *K3sEnv) Start(ctx context.Context) error {
+	if !e.transition(stateStarting, stateNew, stateStopped) {
+		if e.IsFailed() {
+			return errors.New("environment failed - call Stop() first")
+		}
+		return errors.New("environment already started")
+	}
+
+	if e.options.TestingT != nil {
+		e.registerCleanup(ctx, e.options.TestingT)
+	}
+
+	if err := e.start(ctx); err != nil {
+		e.setState(stateFailed)
+		return err
+	}
+
+	e.setState(stateStarted)
+
+	for i, hook := range e.options.Hooks.PostStart {
+		if err := hook(ctx, e); err != nil {
+			return fmt.Errorf("post-start hook %d failed: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+func (e *K3sEnv) start(ctx context.Context) error {
+	for i, hook := range e.options.Hooks.PreStart {
+		if err := hook(ctx); err != nil {
+			return fmt.Errorf("pre-start hook %d failed: %w", i, err)
+		}
+	}
+
 	// Configure testcontainers global logger based on user preferences.
 	// WARNING: This modifies global state and affects all testcontainers in this process.
 	e.configureTestcontainersLogger()
 
-	e.debugf("Starting k3s environment with image: %s", e.options.K3s.Image)
-	if len(e.options.K3s.Args) > 0 {
-		e.debugf("Using custom k3s arguments: %v", e.options.K3s.Args)
-	}
+	if e.usesPreExistingCluster() {
+		e.debugf("Starting k3s environment with pre-existing cluster: %s", e.options.Cluster.Kubeconfig)
+	} else {
+		e.debugf("Starting k3s environment with image: %s", e.options.K3s.Image)
+		if len(e.options.K3s.Args) > 0 {
+			e.debugf("Using custom k3s arguments: %v", e.options.K3s.Args)
+		}
 
-	if err := e.startK3sContainer(ctx); err != nil {
-		return err
+		if err := e.startK3sContainer(ctx); err != nil {
+			return err
+		}
 	}
 
 	if err := e.setupKubeConfig(ctx); err != nil {
@@ -194,7 +346,11 @@ func (e *K3sEnv) Start(ctx context.Context) error {
 	if err := e.prepareManifests(); err != nil {
 		return err
 	}
-	totalManifests := len(e.manifests.CustomResourceDefinitions) + len(e.manifests.MutatingWebhookConfigurations) + len(e.manifests.ValidatingWebhookConfigurations)
+	totalManifests := len(e.manifests.CustomResourceDefinitions) +
+		len(e.manifests.MutatingWebhookConfigurations) +
+		len(e.manifests.ValidatingWebhookConfigurations) +
+		len(e.manifests.ValidatingAdmissionPolicies) +
+		len(e.manifests.ValidatingAdmissionPolicyBindings)
 	e.debugf("Loaded %d manifests", totalManifests)
 
 	if err := e.installCRDs(ctx); err != nil {
@@ -212,16 +368,95 @@ func (e *K3sEnv) Start(ctx context.Context) error {
 	return nil
 }
 
+// MustStart starts the environment like Start, failing the test on error, and
+// registers Stop as a test cleanup, reducing the common setup to:
+//
+//	env, err := k3senv.New(...)
+//	g.Expect(err).NotTo(HaveOccurred())
+//	env.MustStart(ctx, t)
+//
+// The cleanup is registered before starting, so that resources created by a
+// failed Start are released as well. If t is nil, the one configured with
+// WithTestingT is used, if any; otherwise MustStart panics on error and the
+// caller is responsible for calling Stop.
+func (e *K3sEnv) MustStart(ctx context.Context, t TestingT) {
+	if t == nil {
+		t = e.options.TestingT
+	}
+
+	if t == nil {
+		if err := e.Start(ctx); err != nil {
+			panic(fmt.Sprintf("k3senv: failed to start environment: %v", err))
+		}
+		return
+	}
+
+	t.Helper()
+
+	e.registerCleanup(ctx, t)
+
+	if err := e.Start(ctx); err != nil {
+		t.Fatalf("k3senv: failed to start environment: %v", err)
+	}
+}
+
+// registerCleanup registers Stop as a cleanup of the test, once.
+func (e *K3sEnv) registerCleanup(ctx context.Context, t TestingT) {
+	if e.cleanupRegistered {
+		return
+	}
+
+	// the environment is stopped even if a PreStop hook fails, as nothing
+	// could stop it after the test
+	t.Cleanup(func() {
+		if e.IsStopped() {
+			return
+		}
+
+		if err := e.stop(context.WithoutCancel(ctx), true); err != nil {
+			t.Logf("k3senv: failed to stop environment: %v", err)
+		}
+	})
+
+	e.cleanupRegistered = true
+}
+
 func (e *K3sEnv) Stop(ctx context.Context) error {
+	return e.stop(ctx, false)
+}
+
+// stop stops the environment. A failing PreStop hook aborts it, unless force
+// is set, in which case the remaining hooks are skipped and the error is
+// returned along with the teardown ones.
+func (e *K3sEnv) stop(ctx context.Context, force bool) error {
 	e.debugf("Stopping k3s environment")
+
 	var errs []error
 
-	for i := len(e.teardownTasks) - 1; i >= 0; i-- {
-		if err := e.teardownTasks[i](ctx); err != nil {
-			errs = append(errs, fmt.Errorf("teardown task %d failed: %w", i, err))
+	for i, hook := range e.options.Hooks.PreStop {
+		if err := hook(ctx); err != nil {
+			err = fmt.Errorf("pre-stop hook %d failed: %w", i, err)
+			if !force {
+				return err
+			}
+
+			errs = append(errs, err)
+
+			break
 		}
 	}
 
+	defer e.setState(stateStopped)
+
+	// a paused API server would never answer to the teardown tasks
+	if e.IsPaused() {
+		if err := e.Resume(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	errs = append(errs, e.runTeardownTasks(ctx)...)
+
 	if e.container != nil {
 		if err := testcontainers.TerminateContainer(e.container); err != nil {
 			errs = append(errs, fmt.Errorf("failed to terminate container: %w", err))
@@ -235,6 +470,182 @@ func (e *K3sEnv) Stop(ctx context.Context) error {
 	return nil
 }
 
+// Restart replays the environment lifecycle on the running container: the
+// teardown tasks are run and discarded, as with Stop but without terminating
+// the container, then the kubeconfig is fetched again, the clients and the
+// certificates are re-created, the manifests are reloaded and the CRDs are
+// installed again, along with the webhooks if AutoInstall is enabled. The API
+// server must first become healthy, see WaitForAPIServerHealthy.
+//
+// Teardown tasks registered with AddTeardown are run by Restart and must be
+// registered again if needed. Unlike Reset, installed resources are not deleted.
+//
+// A failed Restart leaves the environment failed, as a failed Start does.
+func (e *K3sEnv) Restart(ctx context.Context) error {
+	if !e.transition(stateStarting, stateStarted) {
+		return errors.New("cluster not started - call Start() first")
+	}
+
+	if err := e.restart(ctx); err != nil {
+		e.setState(stateFailed)
+		return err
+	}
+
+	e.setState(stateStarted)
+
+	return nil
+}
+
+func (e *K3sEnv) restart(ctx context.Context) error {
+	e.debugf("Restarting k3s environment")
+
+	if err := e.WaitForAPIServerHealthy(ctx, e.options.K3s.StartTimeout); err != nil {
+		return fmt.Errorf("failed to restart environment: %w", err)
+	}
+
+	if errs := e.runTeardownTasks(ctx); len(errs) > 0 {
+		return fmt.Errorf("failed to restart environment: %w", errors.Join(errs...))
+	}
+
+	if err := e.setupKubeConfig(ctx); err != nil {
+		return err
+	}
+
+	if err := e.createKubernetesClients(); err != nil {
+		return err
+	}
+
+	if err := e.setupCertificates(); err != nil {
+		return err
+	}
+
+	if err := e.prepareManifests(); err != nil {
+		return err
+	}
+
+	if err := e.installCRDs(ctx); err != nil {
+		return err
+	}
+
+	if ptr.Deref(e.options.Webhook.AutoInstall, false) {
+		if err := e.InstallWebhooks(ctx); err != nil {
+			return fmt.Errorf("failed to auto-install webhooks: %w", err)
+		}
+	}
+
+	e.debugf("k3s environment restarted successfully")
+
+	return nil
+}
+
+// Reset restores the environment to the state it had right after Start, without
+// restarting the container: the webhook configurations installed by InstallWebhooks
+// and the CRDs loaded from the manifests are deleted, along with all their custom
+// resources, then the CRDs are installed again, and the webhooks too if
+// AutoInstall is enabled. The API server must first become healthy, see
+// WaitForAPIServerHealthy.
+//
+// Resources created by tests outside of the manifests, such as namespaces or
+// cluster-scoped objects, are left untouched.
+func (e *K3sEnv) Reset(ctx context.Context) error {
+	if !e.IsStarted() {
+		return errors.New("cluster not started - call Start() first")
+	}
+
+	e.debugf("Resetting k3s environment")
+
+	if err := e.WaitForAPIServerHealthy(ctx, e.options.K3s.StartTimeout); err != nil {
+		return fmt.Errorf("failed to reset environment: %w", err)
+	}
+
+	if err := e.UninstallWebhooks(ctx); err != nil {
+		return fmt.Errorf("failed to reset environment: %w", err)
+	}
+
+	if err := e.uninstallCRDs(ctx); err != nil {
+		return fmt.Errorf("failed to reset environment: %w", err)
+	}
+
+	if err := e.installCRDs(ctx); err != nil {
+		return fmt.Errorf("failed to reset environment: %w", err)
+	}
+
+	if ptr.Deref(e.options.Webhook.AutoInstall, false) {
+		if err := e.InstallWebhooks(ctx); err != nil {
+			return fmt.Errorf("failed to reset environment: %w", err)
+		}
+	}
+
+	e.debugf("k3s environment reset successfully")
+
+	return nil
+}
+
+// runTeardownTasks runs the teardown tasks in reverse registration order and
+// discards them, returning the errors of the failed ones.
+func (e *K3sEnv) runTeardownTasks(ctx context.Context) []error {
+	var errs []error
+
+	for i := len(e.teardownTasks) - 1; i >= 0; i-- {
+		if err := e.teardownTasks[i](ctx); err != nil {
+			errs = append(errs, fmt.Errorf("teardown task %d failed: %w", i, err))
+		}
+	}
+
+	e.teardownTasks = []TeardownTask{}
+
+	// The generated certificate directory has been removed by its teardown
+	// task, let setupCertificates generate it again on the next start.
+	if e.certPathGenerated {
+		e.options.Certificate.Path = ""
+		e.certPathGenerated = false
+	}
+
+	return errs
+}
+
+// IsStarted reports whether Start completed successfully and the environment
+// has not been stopped since.
+func (e *K3sEnv) IsStarted() bool {
+	return e.getState() == stateStarted
+}
+
+// IsStarting reports whether Start or Restart is in progress.
+func (e *K3sEnv) IsStarting() bool {
+	return e.getState() == stateStarting
+}
+
+// IsStopped reports whether Stop has been called.
+func (e *K3sEnv) IsStopped() bool {
+	return e.getState() == stateStopped
+}
+
+// IsFailed reports whether Start or Restart failed, leaving resources that
+// must be released with Stop before starting the environment again.
+func (e *K3sEnv) IsFailed() bool {
+	return e.getState() == stateFailed
+}
+
+func (e *K3sEnv) getState() state {
+	return state(e.state.Load())
+}
+
+func (e *K3sEnv) setState(s state) {
+	e.state.Store(int32(s))
+}
+
+// transition atomically moves the environment to the target state if it is in
+// one of the given states, reporting whether it did.
+func (e *K3sEnv) transition(target state, from ...state) bool {
+	for _, s := range from {
+		if e.state.CompareAndSwap(int32(s), int32(target)) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (e *K3sEnv) AddTeardown(task TeardownTask) {
 	e.teardownTasks = append(e.teardownTasks, task)
 }
@@ -251,6 +662,45 @@ func (e *K3sEnv) Scheme() *runtime.Scheme {
 	return e.options.Scheme
 }
 
+// Options returns a deep copy of the options resolved by New, including the
+// values loaded from environment variables. Modifying the returned options has
+// no effect on the environment.
+func (e *K3sEnv) Options() Options {
+	out := e.options.DeepCopy()
+	out.Logger = e.GetLogger()
+
+	return *out
+}
+
+// GetLogger returns the Logger of the environment, set by WithLogger or
+// SetLogger, or nil if none is set.
+func (e *K3sEnv) GetLogger() Logger {
+	if l := e.logger.Load(); l != nil {
+		return *l
+	}
+
+	return nil
+}
+
+// SetLogger replaces the Logger of the environment, e.g. with a testing.T
+// only available after New:
+//
+//	env.SetLogger(t)
+//
+// A nil logger disables logging, unless a SlogHandler is set. The logger is
+// updated atomically, but changing it while Start, Restart or Stop are running
+// has undefined behavior: it should only be changed before or after them. The
+// logs of the k3s container and of testcontainers keep going to the logger set
+// when the container was started.
+func (e *K3sEnv) SetLogger(l Logger) {
+	e.logger.Store(&l)
+}
+
+// WebhookPort returns the port of the webhook server.
+func (e *K3sEnv) WebhookPort() int {
+	return e.options.Webhook.Port
+}
+
 func (e *K3sEnv) CertPath() string {
 	return e.options.Certificate.Path
 }
@@ -263,6 +713,8 @@ func (e *K3sEnv) CABundle() []byte {
 	return e.certData.CABundle()
 }
 
+// ContainerID returns the ID of the k3s container, or an empty string before
+// Start or with a pre-existing cluster. See also ContainerIP and ContainerName.
 func (e *K3sEnv) ContainerID() string {
 	if e.container == nil {
 		return ""
@@ -280,6 +732,10 @@ func (e *K3sEnv) CertificatePaths() CertificatePaths {
 }
 
 func (e *K3sEnv) GetKubeconfig(ctx context.Context) ([]byte, error) {
+	if e.usesPreExistingCluster() && e.kubeconfig != nil {
+		return slices.Clone(e.kubeconfig), nil
+	}
+
 	if e.container == nil {
 		return nil, errors.New("cluster not started - call Start() first")
 	}
@@ -331,12 +787,56 @@ func (e *K3sEnv) ValidatingWebhookConfigurations() []admissionregistrationv1.Val
 	return result
 }
 
-func (e *K3sEnv) WebhookHost() string {
-	return net.JoinHostPort(DefaultWebhookContainerHost, strconv.Itoa(e.options.Webhook.Port))
+// ValidatingAdmissionPolicies returns a deep copy of all ValidatingAdmissionPolicies loaded from the provided manifests.
+//
+// Note: This method creates deep copies to prevent external modification of internal state.
+// If calling this method multiple times (e.g., in a loop), consider caching the result
+// to avoid repeated copying overhead.
+func (e *K3sEnv) ValidatingAdmissionPolicies() []admissionregistrationv1.ValidatingAdmissionPolicy {
+	result := make([]admissionregistrationv1.ValidatingAdmissionPolicy, len(e.manifests.ValidatingAdmissionPolicies))
+	for i := range e.manifests.ValidatingAdmissionPolicies {
+		result[i] = *e.manifests.ValidatingAdmissionPolicies[i].DeepCopy()
+	}
+	return result
+}
+
+// ValidatingAdmissionPolicyBindings returns a deep copy of all ValidatingAdmissionPolicyBindings loaded from the provided manifests.
+//
+// Note: This method creates deep copies to prevent external modification of internal state.
+// If calling this method multiple times (e.g., in a loop), consider caching the result
+// to avoid repeated copying overhead.
+func (e *K3sEnv) ValidatingAdmissionPolicyBindings() []admissionregistrationv1.ValidatingAdmissionPolicyBinding {
+	result := make([]admissionregistrationv1.ValidatingAdmissionPolicyBinding, len(e.manifests.ValidatingAdmissionPolicyBindings))
+	for i := range e.manifests.ValidatingAdmissionPolicyBindings {
+		result[i] = *e.manifests.ValidatingAdmissionPolicyBindings[i].DeepCopy()
+	}
+	return result
+}
+
+// WebhookHost returns the host:port the k3s container uses to reach the webhook
+// server. The host is obtained from the configured WebhookHostResolver and
+// cached after the first successful resolution.
+func (e *K3sEnv) WebhookHost(ctx context.Context) (string, error) {
+	if e.webhookHost == "" {
+		host, err := e.options.Webhook.HostResolver(ctx)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve webhook host: %w", err)
+		}
+		if host == "" {
+			return "", errors.New("failed to resolve webhook host: resolver returned an empty host")
+		}
+
+		e.webhookHost = host
+	}
+
+	return net.JoinHostPort(e.webhookHost, strconv.Itoa(e.options.Webhook.Port)), nil
 }
 
+// WebhookServer returns a webhook server serving the certificates of the
+// environment on the webhook port, with the TLS settings of the options and
+// customized by WithWebhookServerOptions, if set.
 func (e *K3sEnv) WebhookServer() ctrlwebhook.Server {
-	return ctrlwebhook.NewServer(ctrlwebhook.Options{
+	opts := ctrlwebhook.Options{
 		Port:     e.options.Webhook.Port,
 		Host:     DefaultWebhookServerHost,
 		CertDir:  e.options.Certificate.Path,
@@ -344,14 +844,77 @@ func (e *K3sEnv) WebhookServer() ctrlwebhook.Server {
 		KeyName:  cert.KeyFileName,
 		TLSOpts: []func(*tls.Config){
 			func(config *tls.Config) {
-				config.MinVersion = tls.VersionTLS12
+				config.MinVersion = e.options.Webhook.TLS.MinVersion
+				if len(e.options.Webhook.TLS.CipherSuites) > 0 {
+					config.CipherSuites = slices.Clone(e.options.Webhook.TLS.CipherSuites)
+				}
 			},
 		},
-	})
+	}
+
+	if e.options.Webhook.OptionsMutator != nil {
+		e.options.Webhook.OptionsMutator(&opts)
+
+		// the installed webhook configurations depend on them
+		opts.Port = e.options.Webhook.Port
+		opts.CertDir = e.options.Certificate.Path
+		opts.CertName = cert.CertFileName
+		opts.KeyName = cert.KeyFileName
+	}
+
+	return ctrlwebhook.NewServer(opts)
+}
+
+// GetConversionClient returns a conversion webhook client targeting the local
+// webhook server, trusting the environment CA and using the environment scheme.
+func (e *K3sEnv) GetConversionClient() (*webhook.ConversionClient, error) {
+	if e.certData == nil {
+		return nil, errors.New("certificates not generated - call Start() first")
+	}
+
+	c, err := webhook.NewConversionClient(
+		"127.0.0.1",
+		e.options.Webhook.Port,
+		webhook.WithClientCACert(e.certData.CACert),
+		webhook.WithClientScheme(e.options.Scheme),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create conversion client: %w", err)
+	}
+
+	return c, nil
+}
+
+// IsWebhookReady performs a single health check against every endpoint of the
+// registered webhook configurations and reports whether all of them responded
+// successfully. No retry is performed.
+func (e *K3sEnv) IsWebhookReady(ctx context.Context) bool {
+	if e.certData == nil {
+		return false
+	}
+
+	webhookClient, err := webhook.NewClient(
+		"127.0.0.1",
+		e.options.Webhook.Port,
+		webhook.WithClientCACert(e.certData.CACert),
+	)
+	if err != nil {
+		return false
+	}
+
+	if err := e.checkWebhookEndpoints(ctx, webhookClient); err != nil {
+		e.debugf("Webhook not ready: %v", err)
+		return false
+	}
+
+	return true
 }
 
 func (e *K3sEnv) InstallWebhooks(ctx context.Context) error {
-	webhookHostPort := e.WebhookHost()
+	webhookHostPort, err := e.WebhookHost(ctx)
+	if err != nil {
+		return err
+	}
 
 	e.debugf("Installing webhooks with host: %s", webhookHostPort)
 
@@ -373,46 +936,241 @@ func (e *K3sEnv) InstallWebhooks(ctx context.Context) error {
 	return nil
 }
 
-func (e *K3sEnv) InstallCRD(
-	ctx context.Context,
-	crd *apiextensionsv1.CustomResourceDefinition,
-) error {
-	e.debugf("Installing CRD %s", crd.GetName())
+// InstallValidatingAdmissionPolicies applies the ValidatingAdmissionPolicy and
+// ValidatingAdmissionPolicyBinding objects loaded from the manifests. Policies
+// are applied before their bindings.
+func (e *K3sEnv) InstallValidatingAdmissionPolicies(ctx context.Context) error {
+	if e.cli == nil {
+		return errors.New("cluster not started - call Start() first")
+	}
 
-	if err := resources.EnsureGroupVersionKind(e.options.Scheme, crd); err != nil {
-		return fmt.Errorf("failed to set GVK for CRD %s: %w", crd.GetName(), err)
+	policies := e.ValidatingAdmissionPolicies()
+	for i := range policies {
+		if err := e.ForceSSAObject(ctx, &policies[i], DefaultFieldOwner); err != nil {
+			return fmt.Errorf("failed to install validating admission policies: %w", err)
+		}
 	}
 
-	// Convert to unstructured for apply configuration
-	unstructuredCRD, err := resources.ToUnstructured(crd)
-	if err != nil {
-		return fmt.Errorf("failed to convert CRD %s to unstructured: %w", crd.GetName(), err)
+	bindings := e.ValidatingAdmissionPolicyBindings()
+	for i := range bindings {
+		if err := e.ForceSSAObject(ctx, &bindings[i], DefaultFieldOwner); err != nil {
+			return fmt.Errorf("failed to install validating admission policy bindings: %w", err)
+		}
 	}
 
-	applyConfig := client.ApplyConfigurationFromUnstructured(unstructuredCRD)
-	err = e.cli.Apply(ctx, applyConfig, client.ForceOwnership, client.FieldOwner("k3s-envtest"))
+	return nil
+}
+
+// GetWebhookURL returns the client configuration URL of the webhook named
+// webhookName in the MutatingWebhookConfiguration or ValidatingWebhookConfiguration
+// named webhookConfigName, as found in the cluster.
+func (e *K3sEnv) GetWebhookURL(ctx context.Context, webhookConfigName string, webhookName string) (string, error) {
+	if e.cli == nil {
+		return "", errors.New("cluster not started - call Start() first")
+	}
+
+	configs, err := e.getWebhookClientConfigs(ctx, webhookConfigName)
 	if err != nil {
-		return fmt.Errorf("failed to apply CRD %s: %w", crd.GetName(), err)
+		return "", err
 	}
 
-	e.debugf("Waiting for CRD %s to be established...", crd.GetName())
+	cfg, ok := configs[webhookName]
+	if !ok {
+		return "", fmt.Errorf("webhook %q not found in webhook configuration %q", webhookName, webhookConfigName)
+	}
 
-	err = resources.WaitForCRDEstablished(
-		ctx,
-		e.cli,
-		crd.GetName(),
-		e.options.CRD.PollInterval,
-		e.options.CRD.ReadyTimeout,
-	)
+	if cfg.URL == nil {
+		return "", fmt.Errorf("webhook %q in webhook configuration %q has no URL", webhookName, webhookConfigName)
+	}
+
+	return *cfg.URL, nil
+}
+
+// UpdateWebhookCA sets the CA bundle of every webhook of the
+// MutatingWebhookConfiguration and/or ValidatingWebhookConfiguration named
+// webhookConfigName, e.g. after the webhook server restarted with a new
+// certificate. Only the caBundle fields are patched, all other settings
+// are left untouched.
+func (e *K3sEnv) UpdateWebhookCA(ctx context.Context, webhookConfigName string, caBundle []byte) error {
+	if e.cli == nil {
+		return errors.New("cluster not started - call Start() first")
+	}
+
+	found := false
+
+	for _, webhookConfig := range []client.Object{
+		&admissionregistrationv1.MutatingWebhookConfiguration{},
+		&admissionregistrationv1.ValidatingWebhookConfiguration{},
+	} {
+		err := e.cli.Get(ctx, client.ObjectKey{Name: webhookConfigName}, webhookConfig)
+		if k8serr.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get webhook configuration %s: %w", webhookConfigName, err)
+		}
+
+		found = true
+
+		if err := e.patchWebhookCABundle(ctx, webhookConfig, caBundle); err != nil {
+			return err
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("webhook configuration %q not found", webhookConfigName)
+	}
+
+	return nil
+}
+
+// ReinstallWebhooks re-applies the webhook configurations installed by
+// InstallWebhooks with the current CA bundle and webhook host, e.g. after the
+// certificates have been rotated. Only the client configuration URL and CA
+// bundle are updated; rules, selectors and failure policies are preserved.
+//
+// Endpoint readiness is not checked, since the webhook server may not serve
+// the new certificates yet.
+func (e *K3sEnv) ReinstallWebhooks(ctx context.Context) error {
+	if e.certData == nil {
+		return errors.New("certificates not generated - call Start() first")
+	}
+
+	webhookHostPort, err := e.WebhookHost(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to wait for CRD to be established: %w", err)
+		return err
+	}
+
+	e.debugf("Reinstalling %d webhooks with host: %s", len(e.installedWebhooks), webhookHostPort)
+
+	baseURL := fmt.Sprintf("%s://%s", WebhookURLScheme, webhookHostPort)
+	caBundle := string(e.certData.CABundle())
+
+	var errs []error
+
+	for _, wh := range slices.Clone(e.installedWebhooks) {
+		if err := e.applyWebhook(ctx, wh, baseURL, caBundle); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("failed to reinstall webhook configurations: %w", err)
+	}
+
+	return nil
+}
+
+// RefreshCABundle updates the CA bundle of all the webhook configurations and
+// CRD conversion webhooks installed by InstallWebhooks to the current one, e.g.
+// after RotateCertificates. All of them are updated even if some fail, and the
+// errors are returned joined.
+func (e *K3sEnv) RefreshCABundle(ctx context.Context) error {
+	if e.certData == nil {
+		return errors.New("certificates not generated - call Start() first")
+	}
+
+	var errs []error
+
+	if err := e.ReinstallWebhooks(ctx); err != nil {
+		errs = append(errs, err)
+	}
+
+	for _, name := range e.conversionCRDs {
+		if err := e.patchCRDConversionCABundle(ctx, name, e.certData.CACert); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("failed to refresh CA bundle: %w", err)
+	}
+
+	return nil
+}
+
+// UninstallWebhooks deletes all the webhook configurations installed by
+// InstallWebhooks. Webhook configurations not installed by the environment are
+// left untouched. It is safe to call it multiple times.
+func (e *K3sEnv) UninstallWebhooks(ctx context.Context) error {
+	if e.cli == nil {
+		return errors.New("cluster not started - call Start() first")
+	}
+
+	for len(e.installedWebhooks) > 0 {
+		if err := e.uninstallWebhook(ctx, e.installedWebhooks[0]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UninstallWebhook deletes the webhook configurations with the given name
+// installed by InstallWebhooks. It does nothing if no such webhook configuration
+// has been installed, or if it has already been deleted.
+func (e *K3sEnv) UninstallWebhook(ctx context.Context, name string) error {
+	if e.cli == nil {
+		return errors.New("cluster not started - call Start() first")
 	}
 
-	e.debugf("CRD %s is now active", crd.GetName())
+	webhooks := slices.Clone(e.installedWebhooks)
+	for _, wh := range webhooks {
+		if wh.GetName() != name {
+			continue
+		}
+
+		if err := e.uninstallWebhook(ctx, wh); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
 
+// RotateCertificates generates a new set of certificates in the same certificate
+// path and refreshes the CA bundle of the installed webhook configurations and
+// CRD conversions (see RefreshCABundle).
+//
+// The webhook server is not restarted: callers serving webhooks from the previous
+// certificates must restart their server to pick up the new ones.
+//
+// Certificates loaded from the files configured with WithCertFiles cannot be
+// rotated, as the environment does not own their CA: an error is returned
+// instead, and the files must be replaced and reloaded with Restart.
+func (e *K3sEnv) RotateCertificates(ctx context.Context) error {
+	if e.options.Certificate.hasFiles() {
+		return errors.New("cannot rotate certificates loaded from files configured with WithCertFiles")
+	}
+
+	if e.certData == nil {
+		return errors.New("certificates not generated - call Start() first")
+	}
+
+	e.debugf("Rotating certificates in: %s", e.options.Certificate.Path)
+
+	if err := e.setupCertificates(); err != nil {
+		return fmt.Errorf("failed to rotate certificates: %w", err)
+	}
+
+	return e.RefreshCABundle(ctx)
+}
+
+func (e *K3sEnv) InstallCRD(
+	ctx context.Context,
+	crd *apiextensionsv1.CustomResourceDefinition,
+) error {
+	e.debugf("Installing CRD %s", crd.GetName())
+
+	if err := e.ForceSSAObject(ctx, crd, DefaultFieldOwner); err != nil {
+		return err
+	}
+
+	e.debugf("Waiting for CRD %s to be established...", crd.GetName())
+
+	return e.WaitForAllCRDs(ctx, []string{crd.GetName()})
+}
+
 func (e *K3sEnv) startK3sContainer(ctx context.Context) error {
 	opts := []testcontainers.ContainerCustomizer{
 		withHostAccess(),
@@ -437,23 +1195,63 @@ func (e *K3sEnv) startK3sContainer(ctx context.Context) error {
 		}
 	}
 
+	switch e.options.K3s.PullPolicy {
+	case K3sPullAlways:
+		opts = append(opts, testcontainers.WithAlwaysPull())
+	case K3sPullNever:
+		// testcontainers has no such policy and pulls missing images
+		if err := checkImagePresent(ctx, e.options.K3s.Image); err != nil {
+			return err
+		}
+	case K3sPullIfNotPresent:
+		// default testcontainers behavior
+	}
+
+	// Mount the registry mirrors configuration, read by k3s on startup
+	if len(e.options.K3s.RegistryMirrors) > 0 || len(e.options.K3s.RegistryCreds) > 0 {
+		registries, err := registriesConfig(e.options.K3s)
+		if err != nil {
+			return err
+		}
+
+		e.debugf("Using registry mirrors: %v", e.options.K3s.RegistryMirrors)
+		opts = append(opts, withRegistriesConfig(registries))
+	}
+
+	if len(e.options.K3s.ContainerLabels) > 0 {
+		opts = append(opts, testcontainers.WithLabels(e.options.K3s.ContainerLabels))
+	}
+
+	if len(e.options.K3s.ContainerEnv) > 0 {
+		opts = append(opts, testcontainers.WithEnv(e.options.K3s.ContainerEnv))
+	}
+
+	args := slices.Clone(e.options.K3s.Args)
+
+	// Mount the audit policy and enable the audit log of the API server
+	if e.options.K3s.AuditPolicy != "" {
+		e.debugf("Enabling API server audit log: %s", k3sAuditLogPath)
+		opts = append(opts, withAuditPolicy([]byte(e.options.K3s.AuditPolicy)))
+		args = append(args, auditArgs()...)
+	}
+
 	// If custom k3s arguments are provided, modify the container command
-	if len(e.options.K3s.Args) > 0 {
-		cmd := make([]string, 0, 1+len(e.options.K3s.Args))
+	if len(args) > 0 {
+		cmd := make([]string, 0, 1+len(args))
 		cmd = append(cmd, "server")
-		cmd = append(cmd, e.options.K3s.Args...)
+		cmd = append(cmd, args...)
 
 		opts = append(opts, testcontainers.WithCmd(cmd...))
 	}
 
 	// Add log consumer to forward container logs to k3senv Logger
-	if ptr.Deref(e.options.K3s.LogRedirection, false) && e.options.Logger != nil {
+	if logger := e.sourceLogger("k3s"); ptr.Deref(e.options.K3s.LogRedirection, false) && logger != nil {
 		opts = append(opts, testcontainers.WithLogConsumers(&loggerConsumer{
-			logger: e.options.Logger,
+			logger: logger,
 		}))
 	}
 
-	container, err := k3s.Run(ctx, e.options.K3s.Image, opts...)
+	container, err := e.runK3sContainer(ctx, opts)
 	if err != nil {
 		return fmt.Errorf("failed to start k3s container with image %s: %w", e.options.K3s.Image, err)
 	}
@@ -461,6 +1259,46 @@ func (e *K3sEnv) startK3sContainer(ctx context.Context) error {
 	return nil
 }
 
+// runK3sContainer runs the k3s container, retrying up to StartupRetries times
+// with exponential backoff. Each attempt is bounded by StartTimeout.
+func (e *K3sEnv) runK3sContainer(
+	ctx context.Context,
+	opts []testcontainers.ContainerCustomizer,
+) (*k3s.K3sContainer, error) {
+	backoff := k3sStartupRetryBackoff
+
+	for attempt := 0; ; attempt++ {
+		runCtx, cancel := context.WithTimeout(ctx, e.options.K3s.StartTimeout)
+		container, err := k3s.Run(runCtx, e.options.K3s.Image, opts...)
+		cancel()
+
+		if err == nil {
+			return container, nil
+		}
+
+		// A container may be returned along with the error, e.g. when it was
+		// created but k3s did not become ready in time.
+		if container != nil {
+			_ = testcontainers.TerminateContainer(container)
+		}
+
+		if attempt >= e.options.K3s.StartupRetries || ctx.Err() != nil {
+			return nil, err
+		}
+
+		e.debugf("Failed to start k3s container (attempt %d/%d), retrying in %v: %v",
+			attempt+1, e.options.K3s.StartupRetries+1, backoff, err)
+
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+	}
+}
+
 // withHostAccess enables container -> host communication by adding
 // host.containers.internal to the container's /etc/hosts, mapped to host-gateway.
 // This works on both Docker and Podman (4.1+).
@@ -484,43 +1322,128 @@ func withNetworkMode(mode string) testcontainers.ContainerCustomizer {
 	})
 }
 
-func (e *K3sEnv) setupKubeConfig(ctx context.Context) error {
-	kubeconfig, err := e.container.GetKubeConfig(ctx)
+// checkImagePresent returns an error if the given image is not present in the
+// local image store.
+func checkImagePresent(ctx context.Context, image string) error {
+	dockerClient, err := testcontainers.NewDockerClientWithOpts(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to get kubeconfig from container %s: %w", e.container.GetContainerID(), err)
+		return fmt.Errorf("failed to create docker client: %w", err)
+	}
+	defer func() {
+		_ = dockerClient.Close()
+	}()
+
+	if _, err := dockerClient.ImageInspect(ctx, image); err != nil {
+		if cerrdefs.IsNotFound(err) {
+			return fmt.Errorf("k3s image %s is not present locally and the pull policy is %s", image, K3sPullNever)
+		}
+		return fmt.Errorf("failed to inspect k3s image %s: %w", image, err)
+	}
+
+	return nil
+}
+
+func (e *K3sEnv) setupKubeConfig(ctx context.Context) error {
+	var kubeconfig []byte
+	var err error
+
+	if e.usesPreExistingCluster() {
+		kubeconfig, err = os.ReadFile(e.options.Cluster.Kubeconfig)
+		if err != nil {
+			return fmt.Errorf("failed to read kubeconfig %s: %w", e.options.Cluster.Kubeconfig, err)
+		}
+	} else {
+		kubeconfig, err = e.container.GetKubeConfig(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get kubeconfig from container %s: %w", e.container.GetContainerID(), err)
+		}
 	}
 
 	cfg, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
 	if err != nil {
 		return fmt.Errorf("failed to create REST config from kubeconfig: %w", err)
 	}
+	e.kubeconfig = kubeconfig
 	e.cfg = cfg
 	return nil
 }
 
+// usesPreExistingCluster reports whether the environment uses the cluster of
+// a kubeconfig file instead of a k3s container, see WithPreExistingCluster.
+func (e *K3sEnv) usesPreExistingCluster() bool {
+	return e.options.Cluster.Kubeconfig != ""
+}
+
 func (e *K3sEnv) createKubernetesClients() error {
-	cli, err := client.New(e.cfg, client.Options{Scheme: e.options.Scheme})
+	cli, err := client.NewWithWatch(e.cfg, client.Options{Scheme: e.options.Scheme})
 	if err != nil {
 		return fmt.Errorf("failed to create Kubernetes client with scheme: %w", err)
 	}
 
-	e.cli = cli
+	e.cli = interceptor.NewClient(cli, e.pausedInterceptor())
+	e.mapper = cli.RESTMapper()
+
+	// the cached clientsets may refer to a previous configuration, see Restart
+	e.clientsMu.Lock()
+	e.clientset = nil
+	e.extensionsClientset = nil
+	e.clientsMu.Unlock()
+
+	return nil
+}
+
+// ensureCertificatePath defaults the certificate path to a directory named
+// after the container, or a random one with a pre-existing cluster, removed on
+// teardown.
+func (e *K3sEnv) ensureCertificatePath() error {
+	if e.options.Certificate.Path != "" {
+		return nil
+	}
+
+	var cd string
+
+	if e.container != nil {
+		cd = fmt.Sprintf("%s%s", DefaultCertDirPrefix, e.container.GetContainerID())
+	} else {
+		dir, err := os.MkdirTemp(filepath.Dir(DefaultCertDirPrefix), filepath.Base(DefaultCertDirPrefix)+"*")
+		if err != nil {
+			return fmt.Errorf("failed to create certificate directory: %w", err)
+		}
+		cd = dir
+	}
+
+	e.AddTeardown(func(ctx context.Context) error {
+		return os.RemoveAll(cd)
+	})
+
+	e.options.Certificate.Path = cd
+	e.certPathGenerated = true
 
 	return nil
 }
 
 func (e *K3sEnv) setupCertificates() error {
-	if e.options.Certificate.Path == "" {
-		cd := fmt.Sprintf("%s%s", DefaultCertDirPrefix, e.container.GetContainerID())
+	if e.options.Certificate.hasFiles() {
+		return e.loadCertificates()
+	}
 
-		e.AddTeardown(func(ctx context.Context) error {
-			return os.RemoveAll(cd)
-		})
+	if err := e.ensureCertificatePath(); err != nil {
+		return err
+	}
 
-		e.options.Certificate.Path = cd
+	certOpts := []cert.Option{
+		cert.WithKeyAlgorithm(e.options.Certificate.KeyAlgorithm),
+	}
+	if len(e.options.Certificate.CACert) > 0 {
+		certOpts = append(certOpts, cert.WithCA(e.options.Certificate.CACert, e.options.Certificate.CAKey))
 	}
 
-	certData, err := cert.New(e.options.Certificate.Path, e.options.Certificate.Validity, CertificateSANs)
+	certData, err := cert.New(
+		e.options.Certificate.Path,
+		e.options.Certificate.Validity,
+		e.options.Certificate.SANs,
+		certOpts...,
+	)
 	if err != nil {
 		return fmt.Errorf("failed to generate certificates in path %s: %w", e.options.Certificate.Path, err)
 	}
@@ -530,17 +1453,44 @@ func (e *K3sEnv) setupCertificates() error {
 	return nil
 }
 
+func (e *K3sEnv) loadCertificates() error {
+	certData, err := cert.Load(
+		e.options.Certificate.CertFile,
+		e.options.Certificate.KeyFile,
+		e.options.Certificate.CAFile,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to load certificate files: %w", err)
+	}
+
+	if err := e.ensureCertificatePath(); err != nil {
+		return err
+	}
+
+	// The webhook server and CertificatePaths expect the standard file names
+	// in the certificate path, so copy the provided files there.
+	if err := certData.Write(e.options.Certificate.Path); err != nil {
+		return fmt.Errorf("failed to copy certificates to path %s: %w", e.options.Certificate.Path, err)
+	}
+
+	e.certData = certData
+
+	return nil
+}
+
 func (e *K3sEnv) prepareManifests() error {
 	e.manifests = Manifests{}
 
-	// Define the filter for CRDs and webhook configurations
+	// Define the filter for CRDs, webhook configurations and admission policies
 	manifestFilter := filter.ByType(
 		gvk.CustomResourceDefinition,
 		gvk.MutatingWebhookConfiguration,
 		gvk.ValidatingWebhookConfiguration,
+		gvk.ValidatingAdmissionPolicy,
+		gvk.ValidatingAdmissionPolicyBinding,
 	)
 
-	var unstructuredObjs []runtime.Object
+	var unstructuredObjs []unstructured.Unstructured
 
 	if len(e.options.Manifest.Paths) > 0 {
 		manifests, err := resources.LoadFromPaths(
@@ -550,9 +1500,7 @@ func (e *K3sEnv) prepareManifests() error {
 		if err != nil {
 			return fmt.Errorf("failed to load manifests from paths %v: %w", e.options.Manifest.Paths, err)
 		}
-		for _, m := range manifests {
-			unstructuredObjs = append(unstructuredObjs, &m)
-		}
+		unstructuredObjs = append(unstructuredObjs, manifests...)
 	}
 
 	if len(e.options.Manifest.Objects) > 0 {
@@ -564,18 +1512,13 @@ func (e *K3sEnv) prepareManifests() error {
 		if err != nil {
 			return fmt.Errorf("failed to load %d runtime objects: %w", len(e.options.Manifest.Objects), err)
 		}
-		for _, m := range manifests {
-			unstructuredObjs = append(unstructuredObjs, &m)
-		}
+		unstructuredObjs = append(unstructuredObjs, manifests...)
 	}
 
-	// Convert unstructured objects to typed objects
-	for _, obj := range unstructuredObjs {
-		uns, ok := obj.(*unstructured.Unstructured)
-		if !ok {
-			continue
-		}
-
+	// Convert unstructured objects to typed objects, in install order
+	sorted := resources.SortByInstallOrder(unstructuredObjs)
+	for i := range sorted {
+		uns := &sorted[i]
 		objGVK := uns.GroupVersionKind()
 
 		switch objGVK {
@@ -599,15 +1542,44 @@ func (e *K3sEnv) prepareManifests() error {
 				return fmt.Errorf("failed to convert ValidatingWebhookConfiguration %s: %w", uns.GetName(), err)
 			}
 			e.manifests.ValidatingWebhookConfigurations = append(e.manifests.ValidatingWebhookConfigurations, webhook)
+
+		case gvk.ValidatingAdmissionPolicy:
+			var policy admissionregistrationv1.ValidatingAdmissionPolicy
+			if err := resources.Convert(e.options.Scheme, uns, &policy); err != nil {
+				return fmt.Errorf("failed to convert ValidatingAdmissionPolicy %s: %w", uns.GetName(), err)
+			}
+			e.manifests.ValidatingAdmissionPolicies = append(e.manifests.ValidatingAdmissionPolicies, policy)
+
+		case gvk.ValidatingAdmissionPolicyBinding:
+			var binding admissionregistrationv1.ValidatingAdmissionPolicyBinding
+			if err := resources.Convert(e.options.Scheme, uns, &binding); err != nil {
+				return fmt.Errorf("failed to convert ValidatingAdmissionPolicyBinding %s: %w", uns.GetName(), err)
+			}
+			e.manifests.ValidatingAdmissionPolicyBindings = append(e.manifests.ValidatingAdmissionPolicyBindings, binding)
 		}
 	}
 
 	return nil
 }
 
-// debugf logs a debug message if a logger is configured.
+// debugf logs a debug message if a slog handler or a logger is configured.
 func (e *K3sEnv) debugf(format string, args ...any) {
-	if e.options.Logger != nil {
-		e.options.Logger.Logf("[k3senv] "+format, args...)
+	if h := e.options.SlogHandler; h != nil {
+		ctx := context.Background()
+		if !h.Enabled(ctx, slog.LevelDebug) {
+			return
+		}
+
+		slog.New(h).LogAttrs(
+			ctx,
+			slog.LevelDebug,
+			fmt.Sprintf(format, args...),
+			slog.String("component", "k3senv"),
+		)
+		return
+	}
+
+	if logger := e.GetLogger(); logger != nil {
+		logger.Logf("[k3senv] "+format, args...)
 	}
 }
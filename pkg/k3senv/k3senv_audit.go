@@ -0,0 +1,143 @@
+package k3senv
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lburgazzoli/k3s-envtest/internal/cert"
+	"github.com/testcontainers/testcontainers-go"
+)
+
+const (
+	// k3sAuditPolicyPath is the path of the audit policy set by
+	// WithK3sAuditPolicy in the container.
+	k3sAuditPolicyPath = "/var/lib/rancher/k3s/server/audit-policy.yaml"
+
+	// k3sAuditLogPath is the path of the audit log in the container.
+	k3sAuditLogPath = "/var/log/k3s/audit.log"
+
+	// auditLogPollInterval is the interval at which WatchAuditLog reads the
+	// new entries of the audit log.
+	auditLogPollInterval = 500 * time.Millisecond
+)
+
+// GetAuditLog returns the content of the API server audit log, one JSON
+// encoded audit event per line. The audit log must be enabled with
+// WithK3sAuditPolicy.
+func (e *K3sEnv) GetAuditLog(ctx context.Context) ([]byte, error) {
+	if err := e.checkAuditLog(); err != nil {
+		return nil, err
+	}
+
+	stdout, stderr, code, err := e.ExecInContainer(ctx, []string{"cat", k3sAuditLogPath})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	if code != 0 {
+		return nil, fmt.Errorf("failed to read audit log: cat exited with code %d: %s", code, strings.TrimSpace(stderr))
+	}
+
+	return []byte(stdout), nil
+}
+
+// WatchAuditLog streams the lines written to the API server audit log after
+// the call, each a JSON encoded audit event. The log is polled, and the channel
+// is closed when ctx is cancelled. The audit log must be enabled with
+// WithK3sAuditPolicy.
+func (e *K3sEnv) WatchAuditLog(ctx context.Context) (<-chan string, error) {
+	content, err := e.GetAuditLog(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := make(chan string)
+
+	go func() {
+		defer close(lines)
+
+		ticker := time.NewTicker(auditLogPollInterval)
+		defer ticker.Stop()
+
+		offset := len(content)
+
+		var pending []byte
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			// tail offsets are 1-based
+			stdout, _, code, err := e.ExecInContainer(ctx, []string{"tail", "-c", "+" + strconv.Itoa(offset+1), k3sAuditLogPath})
+			if err != nil || code != 0 {
+				e.debugf("Failed to read audit log: exit code %d, %v", code, err)
+				continue
+			}
+
+			offset += len(stdout)
+			pending = append(pending, stdout...)
+
+			for {
+				i := bytes.IndexByte(pending, '\n')
+				if i < 0 {
+					break
+				}
+
+				line := string(pending[:i])
+				pending = pending[i+1:]
+
+				select {
+				case lines <- line:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return lines, nil
+}
+
+// checkAuditLog returns an error if the audit log cannot be read.
+func (e *K3sEnv) checkAuditLog() error {
+	if err := e.checkContainer("reading the audit log"); err != nil {
+		return err
+	}
+
+	if e.options.K3s.AuditPolicy == "" {
+		return errors.New("audit log not enabled - use WithK3sAuditPolicy()")
+	}
+
+	return nil
+}
+
+// auditArgs returns the k3s arguments enabling the audit log of the API
+// server with the policy copied by withAuditPolicy.
+func auditArgs() []string {
+	return []string{
+		"--kube-apiserver-arg=audit-policy-file=" + k3sAuditPolicyPath,
+		"--kube-apiserver-arg=audit-log-path=" + k3sAuditLogPath,
+	}
+}
+
+// withAuditPolicy creates a customizer that copies the audit policy into the
+// container before it starts. A new reader is created each time the
+// customizer is applied, as the container start may be retried.
+func withAuditPolicy(data []byte) testcontainers.ContainerCustomizer {
+	return testcontainers.CustomizeRequestOption(func(req *testcontainers.GenericContainerRequest) error {
+		req.Files = append(req.Files, testcontainers.ContainerFile{
+			Reader:            bytes.NewReader(data),
+			ContainerFilePath: k3sAuditPolicyPath,
+			FileMode:          cert.DefaultFilePermission,
+		})
+		return nil
+	})
+}
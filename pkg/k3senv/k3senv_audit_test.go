@@ -0,0 +1,90 @@
+package k3senv_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lburgazzoli/k3s-envtest/pkg/k3senv"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	. "github.com/onsi/gomega"
+)
+
+const testAuditPolicy = `
+apiVersion: audit.k8s.io/v1
+kind: Policy
+rules:
+  - level: Metadata
+    resources:
+      - group: ""
+        resources: ["configmaps"]
+  - level: None
+`
+
+func TestAuditLog(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	env, err := k3senv.New(k3senv.WithK3sAuditPolicy(testAuditPolicy))
+	g.Expect(err).NotTo(HaveOccurred())
+	t.Cleanup(func() {
+		_ = env.Stop(ctx)
+	})
+
+	_, err = env.GetAuditLog(ctx)
+	g.Expect(err).To(MatchError(ContainSubstring("call Start() first")))
+
+	_, err = env.WatchAuditLog(ctx)
+	g.Expect(err).To(MatchError(ContainSubstring("call Start() first")))
+
+	err = env.Start(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	ns, err := env.CreateTestNamespace(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	watchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	lines, err := env.WatchAuditLog(watchCtx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	cm := &unstructured.Unstructured{}
+	cm.SetAPIVersion("v1")
+	cm.SetKind("ConfigMap")
+	cm.SetNamespace(ns)
+	cm.SetName("audited")
+	g.Expect(env.Client().Create(ctx, cm)).To(Succeed())
+
+	g.Eventually(lines).WithTimeout(30 * time.Second).Should(Receive(And(
+		ContainSubstring(`"verb":"create"`),
+		ContainSubstring(`"name":"audited"`),
+	)))
+
+	content, err := env.GetAuditLog(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(content)).To(ContainSubstring(`"name":"audited"`))
+
+	cancel()
+
+	g.Eventually(lines).Should(BeClosed())
+}
+
+func TestAuditLogNotEnabled(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	env, err := k3senv.New()
+	g.Expect(err).NotTo(HaveOccurred())
+	t.Cleanup(func() {
+		_ = env.Stop(ctx)
+	})
+
+	err = env.Start(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	_, err = env.GetAuditLog(ctx)
+	g.Expect(err).To(MatchError(ContainSubstring("audit log not enabled")))
+}
@@ -0,0 +1,147 @@
+package k3senv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// StartCache starts a controller-runtime cache for the cluster, built with the
+// scheme of the environment, e.g. to test code relying on informers or field
+// indexes without a manager. The cache is stopped on Stop:
+//
+//	if err := env.StartCache(ctx); err != nil {
+//	    return err
+//	}
+//	if err := env.AddIndex(&corev1.Pod{}, "spec.nodeName", indexByNodeName); err != nil {
+//	    return err
+//	}
+//	if err := env.WaitForCacheSync(ctx); err != nil {
+//	    return err
+//	}
+//
+// Informers are created on first use, by InformerFor and AddIndex.
+func (e *K3sEnv) StartCache(ctx context.Context) error {
+	if !e.IsStarted() {
+		return errors.New("cluster not started - call Start() first")
+	}
+
+	e.cacheMu.Lock()
+	defer e.cacheMu.Unlock()
+
+	if e.informerCache != nil {
+		return errors.New("cache already started")
+	}
+
+	c, err := cache.New(e.cfg, cache.Options{
+		Scheme: e.options.Scheme,
+		Mapper: e.mapper,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create cache: %w", err)
+	}
+
+	cacheCtx, cancel := context.WithCancel(ctx)
+	stopped := make(chan struct{})
+
+	var startErr error
+
+	go func() {
+		defer close(stopped)
+		startErr = c.Start(cacheCtx)
+	}()
+
+	e.informerCache = c
+
+	e.debugf("Cache started")
+
+	e.AddTeardown(func(context.Context) error {
+		cancel()
+		<-stopped
+
+		e.cacheMu.Lock()
+		e.informerCache = nil
+		e.cacheMu.Unlock()
+
+		if startErr != nil {
+			return fmt.Errorf("failed to stop cache: %w", startErr)
+		}
+		return nil
+	})
+
+	return nil
+}
+
+// InformerFor returns the informer of the cache started by StartCache for the
+// type of obj, creating it if needed, without waiting for it to sync, see
+// WaitForCacheSync.
+func (e *K3sEnv) InformerFor(obj client.Object) (cache.Informer, error) {
+	c, err := e.GetCache()
+	if err != nil {
+		return nil, err
+	}
+
+	informer, err := c.GetInformer(context.Background(), obj, cache.BlockUntilSynced(false))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get informer for %T: %w", obj, err)
+	}
+
+	return informer, nil
+}
+
+// AddIndex adds a field index to the cache started by StartCache for the type
+// of obj, so that the objects can be listed from the cache with a field
+// selector on it, as with the FieldIndexer of a manager. The informer of the
+// type is created if needed, and waited for to sync up to the CRD ready
+// timeout.
+func (e *K3sEnv) AddIndex(obj client.Object, field string, fn client.IndexerFunc) error {
+	c, err := e.GetCache()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), e.options.CRD.ReadyTimeout)
+	defer cancel()
+
+	if err := c.IndexField(ctx, obj, field, fn); err != nil {
+		return fmt.Errorf("failed to add index %s for %T: %w", field, obj, err)
+	}
+
+	return nil
+}
+
+// WaitForCacheSync blocks until the informers of the cache started by
+// StartCache have synced, or ctx is done.
+func (e *K3sEnv) WaitForCacheSync(ctx context.Context) error {
+	c, err := e.GetCache()
+	if err != nil {
+		return err
+	}
+
+	if !c.WaitForCacheSync(ctx) {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("failed to wait for cache sync: %w", err)
+		}
+		return errors.New("failed to wait for cache sync: cache stopped")
+	}
+
+	return nil
+}
+
+// GetCache returns the cache started by StartCache, e.g. to list objects by
+// the field indexes added with AddIndex:
+//
+//	err := c.List(ctx, &pods, client.MatchingFields{"spec.nodeName": node})
+func (e *K3sEnv) GetCache() (cache.Cache, error) {
+	e.cacheMu.Lock()
+	defer e.cacheMu.Unlock()
+
+	if e.informerCache == nil {
+		return nil, errors.New("cache not started - call StartCache() first")
+	}
+
+	return e.informerCache, nil
+}
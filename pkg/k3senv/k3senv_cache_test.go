@@ -0,0 +1,81 @@
+package k3senv_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lburgazzoli/k3s-envtest/pkg/k3senv"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestCache(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	env, err := k3senv.New()
+	g.Expect(err).NotTo(HaveOccurred())
+	t.Cleanup(func() {
+		_ = env.Stop(ctx)
+	})
+
+	err = env.StartCache(ctx)
+	g.Expect(err).To(MatchError(ContainSubstring("call Start() first")))
+
+	err = env.Start(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	_, err = env.InformerFor(&corev1.ConfigMap{})
+	g.Expect(err).To(MatchError(ContainSubstring("call StartCache() first")))
+
+	err = env.WaitForCacheSync(ctx)
+	g.Expect(err).To(MatchError(ContainSubstring("call StartCache() first")))
+
+	g.Expect(env.StartCache(ctx)).To(Succeed())
+	g.Expect(env.StartCache(ctx)).To(MatchError(ContainSubstring("cache already started")))
+
+	ns, err := env.CreateTestNamespace(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	informer, err := env.InformerFor(&corev1.ConfigMap{})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	err = env.AddIndex(&corev1.ConfigMap{}, "data.owner", func(obj client.Object) []string {
+		cm, ok := obj.(*corev1.ConfigMap)
+		if !ok || cm.Data["owner"] == "" {
+			return nil
+		}
+		return []string{cm.Data["owner"]}
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(env.WaitForCacheSync(ctx)).To(Succeed())
+	g.Expect(informer.HasSynced()).To(BeTrue())
+
+	for _, name := range []string{"mine", "theirs"} {
+		g.Expect(env.Client().Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name},
+			Data:       map[string]string{"owner": name},
+		})).To(Succeed())
+	}
+
+	c, err := env.GetCache()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Eventually(func(g Gomega) {
+		list := corev1.ConfigMapList{}
+		g.Expect(c.List(ctx, &list, client.InNamespace(ns), client.MatchingFields{"data.owner": "mine"})).To(Succeed())
+		g.Expect(list.Items).To(HaveLen(1))
+		g.Expect(list.Items[0].Name).To(Equal("mine"))
+	}).WithTimeout(30 * time.Second).Should(Succeed())
+
+	g.Expect(env.Stop(ctx)).To(Succeed())
+
+	_, err = env.GetCache()
+	g.Expect(err).To(MatchError(ContainSubstring("call StartCache() first")))
+}
@@ -0,0 +1,164 @@
+package k3senv
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lburgazzoli/k3s-envtest/internal/resources"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/discovery"
+)
+
+// CleanupLabel is the label set by TagResourcesForCleanup, whose value
+// identifies the resources deleted together by CleanupTestResources.
+const CleanupLabel = "k3senv.io/test-cleanup"
+
+// TagResourcesForCleanup labels the given objects, which must exist in the
+// cluster, with CleanupLabel set to label, so that they are deleted by
+// CleanupTestResources. This allows tests sharing an environment to clean up
+// their own resources, whatever their type.
+//
+// The label must be a valid label value, which test names such as the ones of
+// subtests are not, so they are best hashed:
+//
+//	label := fmt.Sprintf("%x", sha256.Sum256([]byte(t.Name())))[:16]
+//
+//	err := env.TagResourcesForCleanup(ctx, label, deployment, configMap)
+//	t.Cleanup(func() {
+//	    _ = env.CleanupTestResources(ctx, label)
+//	})
+func (e *K3sEnv) TagResourcesForCleanup(ctx context.Context, label string, objs ...client.Object) error {
+	if err := validateCleanupLabel(label); err != nil {
+		return err
+	}
+
+	if e.cli == nil {
+		return errors.New("cluster not started - call Start() first")
+	}
+
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{
+			"labels": map[string]string{
+				CleanupLabel: label,
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal cleanup label patch: %w", err)
+	}
+
+	for _, obj := range objs {
+		if err := e.cli.Patch(ctx, obj, client.RawPatch(types.MergePatchType, patch)); err != nil {
+			return fmt.Errorf("failed to tag %s for cleanup: %w", resources.FormatObjectReference(obj), err)
+		}
+	}
+
+	return nil
+}
+
+// CleanupTestResources deletes the resources of any type, in all namespaces,
+// tagged with the given label by TagResourcesForCleanup. The resource types
+// are discovered from the API server, so that custom resources are included.
+func (e *K3sEnv) CleanupTestResources(ctx context.Context, label string) error {
+	if err := validateCleanupLabel(label); err != nil {
+		return err
+	}
+
+	if e.cli == nil {
+		return errors.New("cluster not started - call Start() first")
+	}
+
+	dc, err := e.GetDiscoveryClient()
+	if err != nil {
+		return err
+	}
+
+	lists, err := dc.ServerPreferredResources()
+	if err != nil {
+		// groups failing discovery, e.g. served by an unavailable aggregated
+		// API server, are skipped as their resources could not be deleted anyway
+		if !discovery.IsGroupDiscoveryFailedError(err) {
+			return fmt.Errorf("failed to discover server resources: %w", err)
+		}
+
+		e.debugf("Partial resource discovery: %v", err)
+	}
+
+	lists = discovery.FilteredBy(discovery.SupportsAllVerbs{Verbs: []string{"list", "delete"}}, lists)
+
+	var errs []error
+	deleted := 0
+
+	for _, list := range lists {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to parse group version %q: %w", list.GroupVersion, err))
+			continue
+		}
+
+		for _, resource := range list.APIResources {
+			if strings.Contains(resource.Name, "/") {
+				continue
+			}
+
+			n, err := e.deleteTagged(ctx, gv.WithKind(resource.Kind), label)
+			if err != nil {
+				errs = append(errs, err)
+			}
+
+			deleted += n
+		}
+	}
+
+	e.debugf("Deleted %d resources tagged with %s=%s", deleted, CleanupLabel, label)
+
+	return errors.Join(errs...)
+}
+
+// validateCleanupLabel checks that label can be used as the value of
+// CleanupLabel, so that an invalid one is reported before any object is
+// patched.
+func validateCleanupLabel(label string) error {
+	if errs := validation.IsValidLabelValue(label); len(errs) > 0 {
+		return fmt.Errorf("invalid cleanup label %q: %s", label, strings.Join(errs, ", "))
+	}
+
+	return nil
+}
+
+// deleteTagged deletes the resources of the given type tagged with the given
+// label, returning how many were deleted.
+func (e *K3sEnv) deleteTagged(ctx context.Context, objGVK schema.GroupVersionKind, label string) (int, error) {
+	list := unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(objGVK.GroupVersion().WithKind(objGVK.Kind + "List"))
+
+	if err := e.cli.List(ctx, &list, client.MatchingLabels{CleanupLabel: label}); err != nil {
+		return 0, fmt.Errorf("failed to list %s: %w", objGVK.Kind, err)
+	}
+
+	var errs []error
+	deleted := 0
+
+	for i := range list.Items {
+		item := &list.Items[i]
+
+		err := e.cli.Delete(ctx, item, client.PropagationPolicy(metav1.DeletePropagationBackground))
+		if client.IgnoreNotFound(err) != nil {
+			errs = append(errs, fmt.Errorf("failed to delete %s: %w", resources.FormatObjectReference(item), err))
+			continue
+		}
+
+		deleted++
+	}
+
+	return deleted, errors.Join(errs...)
+}
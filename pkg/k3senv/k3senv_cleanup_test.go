@@ -0,0 +1,82 @@
+package k3senv_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lburgazzoli/k3s-envtest/pkg/k3senv"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestCleanupTestResources(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	newObject := func(apiVersion string, kind string, namespace string, name string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(apiVersion)
+		obj.SetKind(kind)
+		obj.SetNamespace(namespace)
+		obj.SetName(name)
+
+		return obj
+	}
+
+	env, err := k3senv.New()
+	g.Expect(err).NotTo(HaveOccurred())
+	t.Cleanup(func() {
+		_ = env.Stop(ctx)
+	})
+
+	err = env.TagResourcesForCleanup(ctx, t.Name()+"/invalid")
+	g.Expect(err).To(MatchError(ContainSubstring("invalid cleanup label")))
+
+	err = env.CleanupTestResources(ctx, t.Name()+"/invalid")
+	g.Expect(err).To(MatchError(ContainSubstring("invalid cleanup label")))
+
+	err = env.TagResourcesForCleanup(ctx, "test")
+	g.Expect(err).To(MatchError(ContainSubstring("call Start() first")))
+
+	err = env.CleanupTestResources(ctx, "test")
+	g.Expect(err).To(MatchError(ContainSubstring("call Start() first")))
+
+	err = env.Start(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	ns, err := env.CreateTestNamespace(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	tagged := []*unstructured.Unstructured{
+		newObject("v1", "ConfigMap", ns, "tagged"),
+		newObject("v1", "ServiceAccount", ns, "tagged"),
+		newObject("rbac.authorization.k8s.io/v1", "ClusterRole", "", "k3senv-cleanup-tagged"),
+	}
+	other := newObject("v1", "ConfigMap", ns, "other")
+	untagged := newObject("v1", "ConfigMap", ns, "untagged")
+
+	for _, obj := range append(tagged, other, untagged) {
+		g.Expect(env.Client().Create(ctx, obj)).To(Succeed())
+	}
+
+	g.Expect(env.TagResourcesForCleanup(ctx, "mine", tagged[0], tagged[1], tagged[2])).To(Succeed())
+	g.Expect(env.TagResourcesForCleanup(ctx, "theirs", other)).To(Succeed())
+	g.Expect(tagged[0].GetLabels()).To(HaveKeyWithValue(k3senv.CleanupLabel, "mine"))
+
+	g.Expect(env.CleanupTestResources(ctx, "mine")).To(Succeed())
+
+	for _, obj := range tagged {
+		g.Eventually(func() bool {
+			err := env.Client().Get(ctx, client.ObjectKeyFromObject(obj), obj.DeepCopy())
+			return k8serr.IsNotFound(err)
+		}).WithTimeout(30*time.Second).Should(BeTrue(), "%s %s not deleted", obj.GetKind(), obj.GetName())
+	}
+
+	g.Expect(env.Client().Get(ctx, client.ObjectKeyFromObject(other), other.DeepCopy())).To(Succeed())
+	g.Expect(env.Client().Get(ctx, client.ObjectKeyFromObject(untagged), untagged.DeepCopy())).To(Succeed())
+}
@@ -0,0 +1,145 @@
+package k3senv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/lburgazzoli/k3s-envtest/internal/gvk"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+// GetClientForNamespace returns a client defaulting the namespace of the
+// namespaced objects to the given namespace, so that it does not need to be
+// set on every object and ObjectKey. It panics if the environment has not been
+// started, as there is no client to wrap yet.
+func (e *K3sEnv) GetClientForNamespace(namespace string) client.Client {
+	if e.cli == nil {
+		panic("cluster not started - call Start() first")
+	}
+
+	return client.NewNamespacedClient(e.cli, namespace)
+}
+
+// CreateTestNamespace creates a namespace with a unique test-<uuid> name and
+// returns it. The namespace is deleted on teardown.
+func (e *K3sEnv) CreateTestNamespace(ctx context.Context) (string, error) {
+	if !e.IsStarted() {
+		return "", errors.New("cluster not started - call Start() first")
+	}
+
+	ns := unstructured.Unstructured{}
+	ns.SetGroupVersionKind(gvk.Namespace)
+	ns.SetName("test-" + string(uuid.NewUUID()))
+
+	if err := e.cli.Create(ctx, &ns); err != nil {
+		return "", fmt.Errorf("failed to create namespace %s: %w", ns.GetName(), err)
+	}
+
+	e.debugf("Namespace %s created", ns.GetName())
+
+	e.AddTeardown(func(ctx context.Context) error {
+		if err := e.cli.Delete(ctx, &ns); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete namespace %s: %w", ns.GetName(), err)
+		}
+
+		return nil
+	})
+
+	return ns.GetName(), nil
+}
+
+// GetDiscoveryClient returns a new discovery client for the cluster, e.g. to
+// list the API resources it serves.
+func (e *K3sEnv) GetDiscoveryClient() (discovery.DiscoveryInterface, error) {
+	if e.cfg == nil {
+		return nil, errors.New("cluster not started - call Start() first")
+	}
+
+	dc, err := discovery.NewDiscoveryClientForConfig(e.cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create discovery client: %w", err)
+	}
+
+	return dc, nil
+}
+
+// GetDynamicClient returns a new dynamic client for the cluster, e.g. to access
+// resources whose types are not registered in the scheme.
+func (e *K3sEnv) GetDynamicClient() (dynamic.Interface, error) {
+	if e.cfg == nil {
+		return nil, errors.New("cluster not started - call Start() first")
+	}
+
+	dc, err := dynamic.NewForConfig(e.cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+
+	return dc, nil
+}
+
+// GetClientset returns a typed clientset for the cluster, e.g. to watch core
+// resources. The clientset is created on first use and then cached.
+func (e *K3sEnv) GetClientset() (*kubernetes.Clientset, error) {
+	if e.cfg == nil {
+		return nil, errors.New("cluster not started - call Start() first")
+	}
+
+	e.clientsMu.Lock()
+	defer e.clientsMu.Unlock()
+
+	if e.clientset == nil {
+		cs, err := kubernetes.NewForConfig(e.cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create clientset: %w", err)
+		}
+
+		e.clientset = cs
+	}
+
+	return e.clientset, nil
+}
+
+// GetExtensionsClientset returns a typed clientset for the apiextensions API,
+// e.g. to access CRDs. The clientset is created on first use and then cached.
+func (e *K3sEnv) GetExtensionsClientset() (*apiextensionsclientset.Clientset, error) {
+	if e.cfg == nil {
+		return nil, errors.New("cluster not started - call Start() first")
+	}
+
+	e.clientsMu.Lock()
+	defer e.clientsMu.Unlock()
+
+	if e.extensionsClientset == nil {
+		cs, err := apiextensionsclientset.NewForConfig(e.cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create apiextensions clientset: %w", err)
+		}
+
+		e.extensionsClientset = cs
+	}
+
+	return e.extensionsClientset, nil
+}
+
+// GetRestMapper returns the REST mapper used by the client returned by Client,
+// e.g. to map resources to kinds with KindFor. The mapper is refreshed
+// lazily, so CRDs installed after Start are mapped as well.
+//
+// GetRestMapper returns nil if the environment has not been started.
+func (e *K3sEnv) GetRestMapper() meta.RESTMapper {
+	if e.mapper == nil {
+		e.debugf("REST mapper not available - call Start() first")
+	}
+
+	return e.mapper
+}
@@ -0,0 +1,200 @@
+package k3senv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lburgazzoli/k3s-envtest/internal/resources"
+	"github.com/lburgazzoli/k3s-envtest/pkg/k3senv"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestGetRestMapper(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	g.Expect(apiextensionsv1.AddToScheme(scheme)).To(Succeed())
+
+	crd := newTestCRDNonConvertible()
+
+	env, err := k3senv.New(
+		k3senv.WithScheme(scheme),
+		k3senv.WithObjects(crd),
+	)
+	g.Expect(err).NotTo(HaveOccurred())
+	t.Cleanup(func() {
+		_ = env.Stop(ctx)
+	})
+
+	g.Expect(env.GetRestMapper()).To(BeNil())
+
+	err = env.Start(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	mapper := env.GetRestMapper()
+	g.Expect(mapper).NotTo(BeNil())
+	g.Expect(mapper).To(BeIdenticalTo(env.Client().RESTMapper()))
+
+	kind, err := mapper.KindFor(schema.GroupVersionResource{
+		Group:    crd.Spec.Group,
+		Version:  "v1",
+		Resource: crd.Spec.Names.Plural,
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(kind.Kind).To(Equal(crd.Spec.Names.Kind))
+}
+
+func TestGetDiscoveryAndDynamicClients(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	g.Expect(apiextensionsv1.AddToScheme(scheme)).To(Succeed())
+
+	crd := newTestCRDNonConvertible()
+
+	env, err := k3senv.New(
+		k3senv.WithScheme(scheme),
+		k3senv.WithObjects(crd),
+	)
+	g.Expect(err).NotTo(HaveOccurred())
+	t.Cleanup(func() {
+		_ = env.Stop(ctx)
+	})
+
+	_, err = env.GetDiscoveryClient()
+	g.Expect(err).To(MatchError(ContainSubstring("call Start() first")))
+
+	_, err = env.GetDynamicClient()
+	g.Expect(err).To(MatchError(ContainSubstring("call Start() first")))
+
+	err = env.Start(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	dc, err := env.GetDiscoveryClient()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	apiResources, err := dc.ServerResourcesForGroupVersion(crd.Spec.Group + "/v1")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(apiResources.APIResources).To(ContainElement(HaveField("Kind", crd.Spec.Names.Kind)))
+
+	dyn, err := env.GetDynamicClient()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	installed, err := dyn.Resource(apiextensionsv1.SchemeGroupVersion.WithResource("customresourcedefinitions")).
+		Get(ctx, crd.Name, metav1.GetOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(installed.GetName()).To(Equal(crd.Name))
+}
+
+func TestGetClientsets(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	g.Expect(apiextensionsv1.AddToScheme(scheme)).To(Succeed())
+
+	crd := newTestCRDNonConvertible()
+
+	env, err := k3senv.New(
+		k3senv.WithScheme(scheme),
+		k3senv.WithObjects(crd),
+	)
+	g.Expect(err).NotTo(HaveOccurred())
+	t.Cleanup(func() {
+		_ = env.Stop(ctx)
+	})
+
+	_, err = env.GetClientset()
+	g.Expect(err).To(MatchError(ContainSubstring("call Start() first")))
+
+	_, err = env.GetExtensionsClientset()
+	g.Expect(err).To(MatchError(ContainSubstring("call Start() first")))
+
+	err = env.Start(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	cs, err := env.GetClientset()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	cached, err := env.GetClientset()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cached).To(BeIdenticalTo(cs))
+
+	_, err = cs.CoreV1().Namespaces().Get(ctx, metav1.NamespaceDefault, metav1.GetOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	ext, err := env.GetExtensionsClientset()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	installed, err := ext.ApiextensionsV1().CustomResourceDefinitions().Get(ctx, crd.Name, metav1.GetOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(resources.IsCRDEstablished(installed)).To(BeTrue())
+}
+
+func TestCreateTestNamespace(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	env, err := k3senv.New()
+	g.Expect(err).NotTo(HaveOccurred())
+	t.Cleanup(func() {
+		_ = env.Stop(ctx)
+	})
+
+	_, err = env.CreateTestNamespace(ctx)
+	g.Expect(err).To(MatchError(ContainSubstring("call Start() first")))
+
+	err = env.Start(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	ns, err := env.CreateTestNamespace(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(ns).To(HavePrefix("test-"))
+
+	other, err := env.CreateTestNamespace(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(other).NotTo(Equal(ns))
+
+	cli := env.GetClientForNamespace(ns)
+
+	cm := &unstructured.Unstructured{}
+	cm.SetAPIVersion("v1")
+	cm.SetKind("ConfigMap")
+	cm.SetName("test-config")
+
+	err = cli.Create(ctx, cm)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cm.GetNamespace()).To(Equal(ns))
+
+	found := &unstructured.Unstructured{}
+	found.SetAPIVersion("v1")
+	found.SetKind("ConfigMap")
+
+	err = cli.Get(ctx, client.ObjectKey{Name: "test-config"}, found)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(found.GetNamespace()).To(Equal(ns))
+}
+
+func TestCreateTestNamespace_BeforeStart(t *testing.T) {
+	g := NewWithT(t)
+
+	env, err := k3senv.New()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	_, err = env.CreateTestNamespace(context.Background())
+	g.Expect(err).To(MatchError(ContainSubstring("call Start() first")))
+
+	g.Expect(func() {
+		env.GetClientForNamespace("default")
+	}).To(PanicWith(ContainSubstring("call Start() first")))
+}
@@ -0,0 +1,122 @@
+package k3senv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/docker/go-connections/nat"
+)
+
+// ContainerIP returns the IP address of the k3s container in its Docker
+// network, e.g. to reach it from other containers of a custom network.
+func (e *K3sEnv) ContainerIP(ctx context.Context) (string, error) {
+	if err := e.checkContainer("getting the container IP"); err != nil {
+		return "", err
+	}
+
+	ip, err := e.container.ContainerIP(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get k3s container IP: %w", err)
+	}
+
+	return ip, nil
+}
+
+// ContainerName returns the name of the k3s container, without the leading
+// slash reported by Docker.
+func (e *K3sEnv) ContainerName(ctx context.Context) (string, error) {
+	if err := e.checkContainer("getting the container name"); err != nil {
+		return "", err
+	}
+
+	info, err := e.container.Inspect(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to inspect k3s container: %w", err)
+	}
+
+	return strings.TrimPrefix(info.Name, "/"), nil
+}
+
+// GetHostPort returns the port of the host the given TCP port of the k3s
+// container is mapped to, e.g. 6443 for the API server.
+func (e *K3sEnv) GetHostPort(ctx context.Context, containerPort int) (int, error) {
+	if err := e.checkContainer("getting host ports"); err != nil {
+		return 0, err
+	}
+
+	port, err := nat.NewPort("tcp", strconv.Itoa(containerPort))
+	if err != nil {
+		return 0, fmt.Errorf("invalid container port %d: %w", containerPort, err)
+	}
+
+	hostPort, err := e.container.MappedPort(ctx, port)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get host port of container port %d: %w", containerPort, err)
+	}
+
+	return hostPort.Int(), nil
+}
+
+// GetContainerLogs returns the standard output and error of the k3s container
+// since its start, e.g. to capture the logs of the k3s server after a test
+// failure. Unlike WithK3sLogRedirection, which streams them as they are
+// written, the logs can be retrieved at any time. The caller must close the
+// returned reader.
+func (e *K3sEnv) GetContainerLogs(ctx context.Context) (io.ReadCloser, error) {
+	if err := e.checkContainer("getting container logs"); err != nil {
+		return nil, err
+	}
+
+	logs, err := e.container.Logs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get k3s container logs: %w", err)
+	}
+
+	return logs, nil
+}
+
+// CopyContainerLogs writes the logs returned by GetContainerLogs to w:
+//
+//	t.Cleanup(func() {
+//	    if t.Failed() {
+//	        var buf bytes.Buffer
+//	        if err := env.CopyContainerLogs(ctx, &buf); err == nil {
+//	            t.Log(buf.String())
+//	        }
+//	    }
+//	})
+func (e *K3sEnv) CopyContainerLogs(ctx context.Context, w io.Writer) error {
+	logs, err := e.GetContainerLogs(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = logs.Close()
+	}()
+
+	if _, err := io.Copy(w, logs); err != nil {
+		return fmt.Errorf("failed to copy k3s container logs: %w", err)
+	}
+
+	return nil
+}
+
+// checkContainer returns an error if the given operation on the k3s container
+// cannot be performed, as the environment is not started or uses a
+// pre-existing cluster.
+func (e *K3sEnv) checkContainer(operation string) error {
+	if !e.IsStarted() {
+		return errors.New("cluster not started - call Start() first")
+	}
+
+	if e.container == nil {
+		return fmt.Errorf("%s is not supported with a pre-existing cluster", operation)
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package k3senv_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/lburgazzoli/k3s-envtest/pkg/k3senv"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestContainerMetadataAccessors(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	env, err := k3senv.New()
+	g.Expect(err).NotTo(HaveOccurred())
+	t.Cleanup(func() {
+		_ = env.Stop(ctx)
+	})
+
+	g.Expect(env.ContainerID()).To(BeEmpty())
+
+	_, err = env.ContainerIP(ctx)
+	g.Expect(err).To(MatchError(ContainSubstring("call Start() first")))
+
+	_, err = env.ContainerName(ctx)
+	g.Expect(err).To(MatchError(ContainSubstring("call Start() first")))
+
+	_, err = env.GetHostPort(ctx, 6443)
+	g.Expect(err).To(MatchError(ContainSubstring("call Start() first")))
+
+	err = env.Start(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(env.ContainerID()).NotTo(BeEmpty())
+
+	ip, err := env.ContainerIP(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(net.ParseIP(ip)).NotTo(BeNil())
+
+	name, err := env.ContainerName(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(name).NotTo(BeEmpty())
+	g.Expect(name).NotTo(HavePrefix("/"))
+
+	// the API server port is mapped to the port of the rest config host
+	port, err := env.GetHostPort(ctx, 6443)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	u, err := url.Parse(env.Config().Host)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(u.Port()).To(Equal(strconv.Itoa(port)))
+
+	_, err = env.GetHostPort(ctx, 12345)
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestContainerLogs(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	env, err := k3senv.New()
+	g.Expect(err).NotTo(HaveOccurred())
+	t.Cleanup(func() {
+		_ = env.Stop(ctx)
+	})
+
+	_, err = env.GetContainerLogs(ctx)
+	g.Expect(err).To(MatchError(ContainSubstring("call Start() first")))
+
+	err = env.CopyContainerLogs(ctx, io.Discard)
+	g.Expect(err).To(MatchError(ContainSubstring("call Start() first")))
+
+	err = env.Start(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	logs, err := env.GetContainerLogs(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	content, err := io.ReadAll(logs)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(logs.Close()).To(Succeed())
+	g.Expect(string(content)).To(ContainSubstring("Starting k3s"))
+
+	var buf bytes.Buffer
+	g.Expect(env.CopyContainerLogs(ctx, &buf)).To(Succeed())
+	g.Expect(buf.String()).To(ContainSubstring("Starting k3s"))
+}
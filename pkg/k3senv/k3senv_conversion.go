@@ -0,0 +1,197 @@
+package k3senv
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/lburgazzoli/k3s-envtest/internal/gvk"
+	"github.com/lburgazzoli/k3s-envtest/internal/resources"
+	"github.com/lburgazzoli/k3s-envtest/internal/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// getInstalledCRD returns the named CRD from the cluster. It is read as
+// unstructured, so that the scheme is not required to include the
+// apiextensions types.
+func (e *K3sEnv) getInstalledCRD(ctx context.Context, name string) (*apiextensionsv1.CustomResourceDefinition, error) {
+	u := unstructured.Unstructured{}
+	u.SetGroupVersionKind(gvk.CustomResourceDefinition)
+
+	if err := e.cli.Get(ctx, client.ObjectKey{Name: name}, &u); err != nil {
+		return nil, fmt.Errorf("failed to get CRD %s: %w", name, err)
+	}
+
+	crd := apiextensionsv1.CustomResourceDefinition{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, &crd); err != nil {
+		return nil, fmt.Errorf("failed to convert CRD %s: %w", name, err)
+	}
+
+	return &crd, nil
+}
+
+// IsCRDConversionReady returns whether the conversion webhook of the named CRD,
+// as configured by InstallWebhooks, is served by the local webhook server. Any
+// response counts as ready, as conversion webhooks reject the probe sent, but
+// 404 and 405, returned when no handler is registered for the path. An error
+// is returned if the CRD has no conversion webhook or the probe fails before
+// being sent.
+func (e *K3sEnv) IsCRDConversionReady(ctx context.Context, crdName string) (bool, error) {
+	if e.cli == nil || e.certData == nil {
+		return false, errors.New("cluster not started - call Start() first")
+	}
+
+	crd, err := e.getInstalledCRD(ctx, crdName)
+	if err != nil {
+		return false, err
+	}
+
+	conversionURL, err := resources.GetConversionURL(crd)
+	if err != nil {
+		return false, err
+	}
+
+	parsedURL, err := url.Parse(conversionURL)
+	if err != nil {
+		return false, fmt.Errorf("invalid conversion URL %s of CRD %s: %w", conversionURL, crdName, err)
+	}
+
+	webhookClient, err := webhook.NewClient(
+		"127.0.0.1",
+		e.options.Webhook.Port,
+		webhook.WithClientCACert(e.certData.CACert),
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to create webhook client: %w", err)
+	}
+
+	err = webhookClient.CheckEndpoint(ctx, parsedURL.Path)
+
+	var unreachable *webhook.EndpointUnreachableError
+	var status *webhook.EndpointStatusError
+
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.As(err, &unreachable):
+		e.debugf("Conversion webhook of CRD %s not ready: %v", crdName, err)
+		return false, nil
+	case errors.As(err, &status):
+		// the server is up, but no handler is registered for the path
+		if status.StatusCode == http.StatusNotFound || status.StatusCode == http.StatusMethodNotAllowed {
+			e.debugf("Conversion webhook of CRD %s not ready: %v", crdName, err)
+			return false, nil
+		}
+
+		return true, nil
+	default:
+		return false, fmt.Errorf("failed to check conversion webhook of CRD %s: %w", crdName, err)
+	}
+}
+
+// ValidateCRDConversion checks the conversion of obj, a resource of the named
+// CRD, end-to-end: obj is created in fromVersion through the API server, read
+// back in toVersion, and its fields other than metadata and status are
+// compared with the ones returned by the conversion webhook when called
+// directly with GetConversionClient. The object is deleted afterwards.
+func (e *K3sEnv) ValidateCRDConversion(
+	ctx context.Context,
+	crdName string,
+	fromVersion string,
+	toVersion string,
+	obj runtime.Object,
+) error {
+	if e.cli == nil || e.certData == nil {
+		return errors.New("cluster not started - call Start() first")
+	}
+
+	crd, err := e.getInstalledCRD(ctx, crdName)
+	if err != nil {
+		return err
+	}
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return fmt.Errorf("failed to convert %T to unstructured: %w", obj, err)
+	}
+
+	from := &unstructured.Unstructured{Object: content}
+	from.SetAPIVersion(schema.GroupVersion{Group: crd.Spec.Group, Version: fromVersion}.String())
+	from.SetKind(crd.Spec.Names.Kind)
+
+	if crd.Spec.Scope == apiextensionsv1.NamespaceScoped && from.GetNamespace() == "" {
+		from.SetNamespace(metav1.NamespaceDefault)
+	}
+
+	conversionClient, err := e.GetConversionClient()
+	if err != nil {
+		return err
+	}
+
+	toAPIVersion := schema.GroupVersion{Group: crd.Spec.Group, Version: toVersion}.String()
+
+	converted, err := conversionClient.Convert(ctx, from, toAPIVersion)
+	if err != nil {
+		return fmt.Errorf("failed to convert %s to %s with the webhook: %w", from.GetName(), toAPIVersion, err)
+	}
+
+	expected, err := runtime.DefaultUnstructuredConverter.ToUnstructured(converted)
+	if err != nil {
+		return fmt.Errorf("failed to convert %T to unstructured: %w", converted, err)
+	}
+
+	ref := resources.FormatObjectReference(from)
+
+	if err := e.cli.Create(ctx, from.DeepCopy()); err != nil {
+		return fmt.Errorf("failed to create %s: %w", ref, err)
+	}
+	defer func() {
+		if err := e.cli.Delete(ctx, from); client.IgnoreNotFound(err) != nil {
+			e.debugf("Failed to delete %s: %v", ref, err)
+		}
+	}()
+
+	actual := unstructured.Unstructured{}
+	actual.SetAPIVersion(toAPIVersion)
+	actual.SetKind(crd.Spec.Names.Kind)
+
+	if err := e.cli.Get(ctx, client.ObjectKeyFromObject(from), &actual); err != nil {
+		return fmt.Errorf("failed to get %s in version %s: %w", ref, toVersion, err)
+	}
+
+	var errs []error
+
+	for _, field := range conversionFields(expected, actual.Object) {
+		// compare the JSON encodings, as numbers are typed differently
+		// depending on how the objects were decoded
+		want, err := json.Marshal(expected[field])
+		if err != nil {
+			return fmt.Errorf("failed to marshal field %s: %w", field, err)
+		}
+
+		got, err := json.Marshal(actual.Object[field])
+		if err != nil {
+			return fmt.Errorf("failed to marshal field %s: %w", field, err)
+		}
+
+		if !bytes.Equal(want, got) {
+			errs = append(errs, fmt.Errorf("field %s mismatch: expected %s, got %s", field, want, got))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("conversion of %s to %s does not match the webhook output: %w", ref, toVersion, errors.Join(errs...))
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package k3senv_test
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/lburgazzoli/k3s-envtest/internal/testdata/v1alpha1"
+	"github.com/lburgazzoli/k3s-envtest/pkg/k3senv"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestCRDConversion(t *testing.T) {
+	g := NewWithT(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	port, err := k3senv.FindAvailablePort()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	scheme := setupTestScheme(t)
+
+	env, err := k3senv.New(
+		k3senv.WithScheme(scheme),
+		k3senv.WithObjects(newTestCRDWithConversion()),
+		k3senv.WithWebhookPort(port),
+	)
+	g.Expect(err).NotTo(HaveOccurred())
+	t.Cleanup(func() {
+		_ = env.Stop(context.Background())
+	})
+
+	obj := &v1alpha1.SampleResource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "converted",
+		},
+		Spec: v1alpha1.SampleResourceSpec{
+			FieldAlpha: "value",
+		},
+	}
+
+	_, err = env.IsCRDConversionReady(ctx, testCRDName)
+	g.Expect(err).To(MatchError(ContainSubstring("call Start() first")))
+
+	err = env.ValidateCRDConversion(ctx, testCRDName, "v1alpha1", "v1beta1", obj)
+	g.Expect(err).To(MatchError(ContainSubstring("call Start() first")))
+
+	err = env.Start(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	err = env.InstallWebhooks(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	ready, err := env.IsCRDConversionReady(ctx, testCRDName)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(ready).To(BeFalse())
+
+	_, err = env.IsCRDConversionReady(ctx, "missing.example.com")
+	g.Expect(err).To(HaveOccurred())
+
+	server := env.WebhookServer()
+
+	go func() {
+		_ = server.Start(ctx)
+	}()
+
+	g.Eventually(func() error {
+		conn, err := net.Dial("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)))
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}).WithTimeout(30 * time.Second).Should(Succeed())
+
+	// the server answers 404 until the conversion handler is registered
+	ready, err = env.IsCRDConversionReady(ctx, testCRDName)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(ready).To(BeFalse())
+
+	server.Register("/convert", conversion.NewWebhookHandler(scheme, conversion.NewRegistry()))
+
+	g.Eventually(func() (bool, error) {
+		return env.IsCRDConversionReady(ctx, testCRDName)
+	}).WithTimeout(30 * time.Second).Should(BeTrue())
+
+	err = env.ValidateCRDConversion(ctx, testCRDName, "v1alpha1", "v1beta1", obj)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// the object created to check the conversion is deleted
+	err = env.Client().Get(ctx, client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: obj.Name}, &v1alpha1.SampleResource{})
+	g.Expect(k8serr.IsNotFound(err)).To(BeTrue(), "expected not found, got: %v", err)
+}
@@ -0,0 +1,96 @@
+package k3senv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/lburgazzoli/k3s-envtest/internal/gvk"
+	"github.com/lburgazzoli/k3s-envtest/internal/resources"
+	"golang.org/x/sync/errgroup"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// GetInstalledCRDs lists the CRDs installed in the cluster, which, unlike
+// CustomResourceDefinitions, include the ones not loaded from the manifests
+// and reflect their removal.
+func (e *K3sEnv) GetInstalledCRDs(ctx context.Context) ([]apiextensionsv1.CustomResourceDefinition, error) {
+	if e.cli == nil {
+		return nil, errors.New("cluster not started - call Start() first")
+	}
+
+	// CRDs are read as unstructured, so that the scheme is not required to
+	// include the apiextensions types
+	list := unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(gvk.CustomResourceDefinition.GroupVersion().WithKind(gvk.CustomResourceDefinition.Kind + "List"))
+
+	if err := e.cli.List(ctx, &list); err != nil {
+		return nil, fmt.Errorf("failed to list CRDs: %w", err)
+	}
+
+	result := make([]apiextensionsv1.CustomResourceDefinition, len(list.Items))
+	for i := range list.Items {
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(list.Items[i].Object, &result[i]); err != nil {
+			return nil, fmt.Errorf("failed to convert CRD %s: %w", list.Items[i].GetName(), err)
+		}
+	}
+
+	return result, nil
+}
+
+// CRDExists returns whether the CRD with the given name, e.g.
+// "widgets.example.com", is installed in the cluster.
+func (e *K3sEnv) CRDExists(ctx context.Context, name string) (bool, error) {
+	if e.cli == nil {
+		return false, errors.New("cluster not started - call Start() first")
+	}
+
+	u := unstructured.Unstructured{}
+	u.SetGroupVersionKind(gvk.CustomResourceDefinition)
+
+	if err := e.cli.Get(ctx, client.ObjectKey{Name: name}, &u); err != nil {
+		if k8serr.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get CRD %s: %w", name, err)
+	}
+
+	return true, nil
+}
+
+// WaitForAllCRDs waits for the named CRDs to be established, polling them
+// concurrently so that the wait lasts as long as the slowest one rather than
+// the sum of all of them. It returns the first error encountered.
+func (e *K3sEnv) WaitForAllCRDs(ctx context.Context, names []string) error {
+	if e.cli == nil {
+		return errors.New("cluster not started - call Start() first")
+	}
+
+	g, gctx := errgroup.WithContext(ctx)
+
+	for _, name := range names {
+		g.Go(func() error {
+			err := resources.WaitForCRDEstablished(
+				gctx,
+				e.cli,
+				name,
+				e.options.CRD.PollInterval,
+				e.options.CRD.ReadyTimeout,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to wait for CRD to be established: %w", err)
+			}
+
+			e.debugf("CRD %s is now active", name)
+
+			return nil
+		})
+	}
+
+	return g.Wait()
+}
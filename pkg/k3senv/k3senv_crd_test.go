@@ -0,0 +1,94 @@
+package k3senv_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lburgazzoli/k3s-envtest/pkg/k3senv"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestGetInstalledCRDs(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	g.Expect(apiextensionsv1.AddToScheme(scheme)).To(Succeed())
+
+	crd := newTestCRDNonConvertible()
+
+	env, err := k3senv.New(
+		k3senv.WithScheme(scheme),
+		k3senv.WithObjects(crd),
+	)
+	g.Expect(err).NotTo(HaveOccurred())
+	t.Cleanup(func() {
+		_ = env.Stop(ctx)
+	})
+
+	_, err = env.GetInstalledCRDs(ctx)
+	g.Expect(err).To(MatchError(ContainSubstring("call Start() first")))
+
+	_, err = env.CRDExists(ctx, crd.Name)
+	g.Expect(err).To(MatchError(ContainSubstring("call Start() first")))
+
+	err = env.Start(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	crds, err := env.GetInstalledCRDs(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(crds).To(ContainElement(HaveField("Name", crd.Name)))
+
+	exists, err := env.CRDExists(ctx, crd.Name)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(exists).To(BeTrue())
+
+	exists, err = env.CRDExists(ctx, "missing.example.com")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(exists).To(BeFalse())
+
+	g.Expect(env.Client().Delete(ctx, newTestCRDNonConvertible())).To(Succeed())
+
+	g.Eventually(func() (bool, error) {
+		return env.CRDExists(ctx, crd.Name)
+	}).WithTimeout(30 * time.Second).Should(BeFalse())
+
+	crds, err = env.GetInstalledCRDs(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(crds).NotTo(ContainElement(HaveField("Name", crd.Name)))
+	g.Expect(env.CustomResourceDefinitions()).To(ContainElement(HaveField("Name", crd.Name)))
+}
+
+func TestWaitForAllCRDs(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	scheme := setupTestScheme(t)
+
+	env, err := k3senv.New(
+		k3senv.WithScheme(scheme),
+		k3senv.WithObjects(newTestCRDWithConversion(), newTestCRDNonConvertible()),
+		k3senv.WithCRDReadyTimeout(5*time.Second),
+	)
+	g.Expect(err).NotTo(HaveOccurred())
+	t.Cleanup(func() {
+		_ = env.Stop(ctx)
+	})
+
+	err = env.WaitForAllCRDs(ctx, []string{testCRDName})
+	g.Expect(err).To(MatchError(ContainSubstring("call Start() first")))
+
+	err = env.Start(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	err = env.WaitForAllCRDs(ctx, []string{testCRDName, newTestCRDNonConvertible().Name})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	err = env.WaitForAllCRDs(ctx, []string{testCRDName, "missing.example.com"})
+	g.Expect(err).To(MatchError(ContainSubstring("missing.example.com")))
+}
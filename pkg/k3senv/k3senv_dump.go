@@ -0,0 +1,216 @@
+package k3senv
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lburgazzoli/k3s-envtest/internal/cert"
+	"github.com/lburgazzoli/k3s-envtest/internal/gvk"
+	"gopkg.in/yaml.v3"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// DefaultDumpTypes are the resource types dumped by DumpAll unless
+// WithDumpTypes is given: the ones K3sEnv installs, plus namespaces.
+var DefaultDumpTypes = []schema.GroupVersionKind{
+	gvk.CustomResourceDefinition,
+	gvk.MutatingWebhookConfiguration,
+	gvk.ValidatingWebhookConfiguration,
+	gvk.ValidatingAdmissionPolicy,
+	gvk.ValidatingAdmissionPolicyBinding,
+	gvk.Namespace,
+}
+
+// dumpFileNames maps the default resource types to the name of the file they
+// are dumped to. Other types are dumped to a file named after their resource.
+var dumpFileNames = map[schema.GroupVersionKind]string{
+	gvk.CustomResourceDefinition:         "crds",
+	gvk.MutatingWebhookConfiguration:     "webhooks",
+	gvk.ValidatingWebhookConfiguration:   "webhooks",
+	gvk.ValidatingAdmissionPolicy:        "policies",
+	gvk.ValidatingAdmissionPolicyBinding: "policies",
+	gvk.Namespace:                        "namespaces",
+}
+
+// DumpOption configures DumpAll and DumpToTemp.
+type DumpOption interface {
+	ApplyToDumpOptions(opts *DumpOptions)
+}
+
+type dumpOptionFunc func(*DumpOptions)
+
+func (f dumpOptionFunc) ApplyToDumpOptions(opts *DumpOptions) {
+	f(opts)
+}
+
+// DumpOptions contains the configuration of DumpAll and DumpToTemp.
+type DumpOptions struct {
+	// Types are the resource types to dump. Defaults to DefaultDumpTypes.
+	Types []schema.GroupVersionKind
+}
+
+func (opts *DumpOptions) ApplyOptions(options []DumpOption) {
+	for _, opt := range options {
+		opt.ApplyToDumpOptions(opts)
+	}
+}
+
+// WithDumpTypes sets the resource types to dump, replacing DefaultDumpTypes.
+func WithDumpTypes(gvks ...schema.GroupVersionKind) DumpOption {
+	return dumpOptionFunc(func(opts *DumpOptions) {
+		opts.Types = append(opts.Types, gvks...)
+	})
+}
+
+// DumpAll writes the resources of the cluster to targetDir as multi-document
+// YAML files, such as crds.yaml, webhooks.yaml and namespaces.yaml, to inspect
+// the state of the cluster after a test failure:
+//
+//	t.Cleanup(func() {
+//	    if t.Failed() {
+//	        dir, _ := env.DumpToTemp(ctx)
+//	        t.Logf("cluster state dumped to %s", dir)
+//	    }
+//	})
+//
+// Resource types not served by the cluster are skipped. Managed fields are
+// omitted to keep the output readable.
+func (e *K3sEnv) DumpAll(ctx context.Context, targetDir string, opts ...DumpOption) error {
+	if e.cli == nil {
+		return errors.New("cluster not started - call Start() first")
+	}
+
+	dumpOpts := DumpOptions{}
+	dumpOpts.ApplyOptions(opts)
+
+	if len(dumpOpts.Types) == 0 {
+		dumpOpts.Types = DefaultDumpTypes
+	}
+
+	if err := os.MkdirAll(targetDir, cert.DefaultDirPermission); err != nil {
+		return fmt.Errorf("failed to create dump directory %s: %w", targetDir, err)
+	}
+
+	// files are kept in the order of the types, which may share a file
+	var names []string
+	files := make(map[string]*bytes.Buffer)
+
+	for _, objGVK := range dumpOpts.Types {
+		items, err := e.listForDump(ctx, objGVK)
+		if err != nil {
+			return err
+		}
+		if items == nil {
+			continue
+		}
+
+		name, err := e.dumpFileName(objGVK)
+		if err != nil {
+			return err
+		}
+
+		buf, ok := files[name]
+		if !ok {
+			buf = &bytes.Buffer{}
+			files[name] = buf
+			names = append(names, name)
+		}
+
+		enc := yaml.NewEncoder(buf)
+		enc.SetIndent(2)
+
+		for i := range items {
+			if err := enc.Encode(items[i].Object); err != nil {
+				return fmt.Errorf("failed to marshal %s %s: %w", objGVK.Kind, items[i].GetName(), err)
+			}
+		}
+
+		if err := enc.Close(); err != nil {
+			return fmt.Errorf("failed to marshal %s: %w", objGVK.Kind, err)
+		}
+	}
+
+	for _, name := range names {
+		path := filepath.Join(targetDir, name+".yaml")
+
+		if err := os.WriteFile(path, files[name].Bytes(), cert.DefaultFilePermission); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+
+	e.debugf("Dumped %d resource types to %s", len(dumpOpts.Types), targetDir)
+
+	return nil
+}
+
+// DumpToTemp dumps the resources of the cluster, as DumpAll does, to a newly
+// created temporary directory, whose path is returned. The directory is not
+// removed when the environment is stopped.
+func (e *K3sEnv) DumpToTemp(ctx context.Context, opts ...DumpOption) (string, error) {
+	if e.cli == nil {
+		return "", errors.New("cluster not started - call Start() first")
+	}
+
+	dir, err := os.MkdirTemp("", "k3senv-dump-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create dump directory: %w", err)
+	}
+
+	if err := e.DumpAll(ctx, dir, opts...); err != nil {
+		return "", err
+	}
+
+	return dir, nil
+}
+
+// listForDump lists all the resources of the given type across namespaces,
+// without managed fields. It returns nil if the type is not served.
+func (e *K3sEnv) listForDump(ctx context.Context, objGVK schema.GroupVersionKind) ([]unstructured.Unstructured, error) {
+	list := unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(objGVK.GroupVersion().WithKind(objGVK.Kind + "List"))
+
+	if err := e.cli.List(ctx, &list); err != nil {
+		if meta.IsNoMatchError(err) {
+			e.debugf("Skipping dump of %s: not served by the cluster", objGVK)
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list %s: %w", objGVK.Kind, err)
+	}
+
+	items := make([]unstructured.Unstructured, 0, len(list.Items))
+	for i := range list.Items {
+		item := list.Items[i]
+		item.SetManagedFields(nil)
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// dumpFileName returns the name, without extension, of the file the resources
+// of the given type are dumped to.
+func (e *K3sEnv) dumpFileName(objGVK schema.GroupVersionKind) (string, error) {
+	if name, ok := dumpFileNames[objGVK]; ok {
+		return name, nil
+	}
+
+	mapping, err := e.mapper.RESTMapping(objGVK.GroupKind(), objGVK.Version)
+	if err != nil {
+		return "", fmt.Errorf("failed to get REST mapping for %s: %w", objGVK, err)
+	}
+
+	name := mapping.Resource.Resource
+	if mapping.Resource.Group != "" {
+		name += "." + mapping.Resource.Group
+	}
+
+	return strings.ToLower(name), nil
+}
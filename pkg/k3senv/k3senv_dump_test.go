@@ -0,0 +1,104 @@
+package k3senv_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lburgazzoli/k3s-envtest/internal/gvk"
+	"github.com/lburgazzoli/k3s-envtest/internal/resources"
+	"github.com/lburgazzoli/k3s-envtest/pkg/k3senv"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestDumpOptions(t *testing.T) {
+	g := NewWithT(t)
+
+	opts := &k3senv.DumpOptions{}
+	opts.ApplyOptions([]k3senv.DumpOption{
+		k3senv.WithDumpTypes(gvk.ConfigMap),
+		k3senv.WithDumpTypes(gvk.Deployment, gvk.Secret),
+	})
+
+	g.Expect(opts.Types).To(Equal([]schema.GroupVersionKind{gvk.ConfigMap, gvk.Deployment, gvk.Secret}))
+}
+
+func TestDumpAll(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	g.Expect(apiextensionsv1.AddToScheme(scheme)).To(Succeed())
+
+	crd := newTestCRDNonConvertible()
+
+	env, err := k3senv.New(
+		k3senv.WithScheme(scheme),
+		k3senv.WithObjects(crd),
+	)
+	g.Expect(err).NotTo(HaveOccurred())
+	t.Cleanup(func() {
+		_ = env.Stop(ctx)
+	})
+
+	err = env.DumpAll(ctx, t.TempDir())
+	g.Expect(err).To(MatchError(ContainSubstring("call Start() first")))
+
+	err = env.Start(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	t.Run("Default types", func(t *testing.T) {
+		g := NewWithT(t)
+
+		dir := t.TempDir()
+		g.Expect(env.DumpAll(ctx, dir)).To(Succeed())
+
+		for _, name := range []string{"crds.yaml", "webhooks.yaml", "policies.yaml", "namespaces.yaml"} {
+			g.Expect(filepath.Join(dir, name)).To(BeAnExistingFile())
+		}
+
+		content, err := os.ReadFile(filepath.Join(dir, "crds.yaml"))
+		g.Expect(err).NotTo(HaveOccurred())
+
+		objs, err := resources.Decode(content)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(objs).To(ContainElement(WithTransform(func(u unstructured.Unstructured) string {
+			return u.GetName()
+		}, Equal(crd.Name))))
+
+		for _, obj := range objs {
+			g.Expect(obj.GetManagedFields()).To(BeEmpty())
+		}
+	})
+
+	t.Run("Custom types", func(t *testing.T) {
+		g := NewWithT(t)
+
+		dir := t.TempDir()
+		g.Expect(env.DumpAll(ctx, dir, k3senv.WithDumpTypes(gvk.ConfigMap))).To(Succeed())
+
+		entries, err := os.ReadDir(dir)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(entries).To(HaveLen(1))
+		g.Expect(entries[0].Name()).To(Equal("configmaps.yaml"))
+	})
+
+	t.Run("Temp directory", func(t *testing.T) {
+		g := NewWithT(t)
+
+		dir, err := env.DumpToTemp(ctx)
+		g.Expect(err).NotTo(HaveOccurred())
+		t.Cleanup(func() {
+			_ = os.RemoveAll(dir)
+		})
+
+		g.Expect(filepath.Join(dir, "namespaces.yaml")).To(BeAnExistingFile())
+	})
+}
@@ -0,0 +1,148 @@
+package k3senv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+// GetEvents lists the events of the given namespace, or of all namespaces if
+// empty. The events of a single object can be selected with a field selector:
+//
+//	events, err := env.GetEvents(ctx, ns, client.MatchingFields{"involvedObject.name": name})
+//
+// The scheme of the environment must include the core types, which is the
+// case of the default one.
+func (e *K3sEnv) GetEvents(ctx context.Context, namespace string, opts ...client.ListOption) ([]corev1.Event, error) {
+	if e.cli == nil {
+		return nil, errors.New("cluster not started - call Start() first")
+	}
+
+	list := corev1.EventList{}
+
+	if err := e.cli.List(ctx, &list, append([]client.ListOption{client.InNamespace(namespace)}, opts...)...); err != nil {
+		return nil, fmt.Errorf("failed to list events in namespace %q: %w", namespace, err)
+	}
+
+	return list.Items, nil
+}
+
+// WatchEvents streams the events of the given namespace, or of all namespaces
+// if empty, as they are recorded. The channel is closed when ctx is cancelled
+// or the watch is closed by the API server.
+//
+// The scheme of the environment must include the core types, which is the
+// case of the default one.
+func (e *K3sEnv) WatchEvents(ctx context.Context, namespace string) (<-chan corev1.Event, error) {
+	if e.cli == nil {
+		return nil, errors.New("cluster not started - call Start() first")
+	}
+
+	w, err := e.cli.Watch(ctx, &corev1.EventList{}, client.InNamespace(namespace))
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch events in namespace %q: %w", namespace, err)
+	}
+
+	events := make(chan corev1.Event)
+
+	go func() {
+		defer close(events)
+		defer w.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case we, ok := <-w.ResultChan():
+				if !ok {
+					return
+				}
+				if we.Type == watch.Error {
+					e.debugf("Error watching events in namespace %q: %v", namespace, we.Object)
+					continue
+				}
+
+				event, ok := we.Object.(*corev1.Event)
+				if !ok {
+					continue
+				}
+
+				select {
+				case events <- *event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// GetLatestEvents lists the events of the given namespace, or of all
+// namespaces if empty, with the given reason, the most recent first:
+//
+//	events, err := env.GetLatestEvents(ctx, ns, "ReconcileError")
+//	g.Expect(events).To(ContainElement(HaveField("InvolvedObject.Name", name)))
+//
+// As events are recorded asynchronously, assertions are best made with
+// Eventually.
+func (e *K3sEnv) GetLatestEvents(ctx context.Context, namespace string, reason string) ([]corev1.Event, error) {
+	events, err := e.GetEvents(ctx, namespace, client.MatchingFields{"reason": reason})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortStableFunc(events, func(a corev1.Event, b corev1.Event) int {
+		return eventTimestamp(&b).Compare(eventTimestamp(&a))
+	})
+
+	return events, nil
+}
+
+// GetEventRecorder returns an event recorder reporting events from the given
+// component, e.g. to pass to the reconciler under test. The events are sent to
+// the cluster by an event broadcaster, which is shut down on Stop.
+//
+// The objects events are recorded for must be registered in the scheme of the
+// environment.
+func (e *K3sEnv) GetEventRecorder(component string) (record.EventRecorder, error) {
+	cs, err := e.GetClientset()
+	if err != nil {
+		return nil, err
+	}
+
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{
+		Interface: cs.CoreV1().Events(""),
+	})
+
+	e.AddTeardown(func(_ context.Context) error {
+		broadcaster.Shutdown()
+		return nil
+	})
+
+	return broadcaster.NewRecorder(e.options.Scheme, corev1.EventSource{Component: component}), nil
+}
+
+// eventTimestamp returns the time an event was last observed, falling back to
+// the time it was first recorded.
+func eventTimestamp(event *corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	default:
+		return event.CreationTimestamp.Time
+	}
+}
@@ -0,0 +1,137 @@
+package k3senv_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lburgazzoli/k3s-envtest/pkg/k3senv"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/onsi/gomega"
+)
+
+func newTestEvent(namespace string, name string, involvedObject string) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+		},
+		InvolvedObject: corev1.ObjectReference{
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+			Namespace:  namespace,
+			Name:       involvedObject,
+		},
+		Reason:  "Testing",
+		Message: "event for " + involvedObject,
+		Type:    corev1.EventTypeNormal,
+	}
+}
+
+func TestEvents(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	env, err := k3senv.New()
+	g.Expect(err).NotTo(HaveOccurred())
+	t.Cleanup(func() {
+		_ = env.Stop(ctx)
+	})
+
+	_, err = env.GetEvents(ctx, metav1.NamespaceDefault)
+	g.Expect(err).To(MatchError(ContainSubstring("call Start() first")))
+
+	_, err = env.WatchEvents(ctx, metav1.NamespaceDefault)
+	g.Expect(err).To(MatchError(ContainSubstring("call Start() first")))
+
+	err = env.Start(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	ns, err := env.CreateTestNamespace(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	t.Run("Watch", func(t *testing.T) {
+		g := NewWithT(t)
+
+		watchCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		events, err := env.WatchEvents(watchCtx, ns)
+		g.Expect(err).NotTo(HaveOccurred())
+
+		g.Expect(env.Client().Create(ctx, newTestEvent(ns, "watched", "cm-a"))).To(Succeed())
+
+		g.Eventually(events).WithTimeout(30 * time.Second).Should(Receive(
+			HaveField("InvolvedObject.Name", "cm-a"),
+		))
+
+		cancel()
+
+		g.Eventually(events).Should(BeClosed())
+	})
+
+	t.Run("List", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(env.Client().Create(ctx, newTestEvent(ns, "listed", "cm-b"))).To(Succeed())
+
+		events, err := env.GetEvents(ctx, ns)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(events).To(ContainElement(HaveField("Name", "listed")))
+
+		events, err = env.GetEvents(ctx, ns, client.MatchingFields{"involvedObject.name": "cm-b"})
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(events).To(HaveLen(1))
+		g.Expect(events[0].Name).To(Equal("listed"))
+	})
+}
+
+func TestEventRecorder(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	env, err := k3senv.New()
+	g.Expect(err).NotTo(HaveOccurred())
+	t.Cleanup(func() {
+		_ = env.Stop(ctx)
+	})
+
+	_, err = env.GetEventRecorder("k3senv-test")
+	g.Expect(err).To(MatchError(ContainSubstring("call Start() first")))
+
+	_, err = env.GetLatestEvents(ctx, metav1.NamespaceDefault, "ReconcileError")
+	g.Expect(err).To(MatchError(ContainSubstring("call Start() first")))
+
+	err = env.Start(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	ns, err := env.CreateTestNamespace(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: ns,
+			Name:      "recorded",
+		},
+	}
+	g.Expect(env.Client().Create(ctx, cm)).To(Succeed())
+
+	recorder, err := env.GetEventRecorder("k3senv-test")
+	g.Expect(err).NotTo(HaveOccurred())
+
+	recorder.Event(cm, corev1.EventTypeWarning, "ReconcileError", "reconcile failed")
+	recorder.Event(cm, corev1.EventTypeNormal, "Reconciled", "reconcile succeeded")
+
+	g.Eventually(func(g Gomega) {
+		events, err := env.GetLatestEvents(ctx, ns, "ReconcileError")
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(events).To(HaveLen(1))
+		g.Expect(events[0].InvolvedObject.Name).To(Equal("recorded"))
+		g.Expect(events[0].Source.Component).To(Equal("k3senv-test"))
+		g.Expect(events[0].Message).To(Equal("reconcile failed"))
+	}).WithTimeout(30 * time.Second).Should(Succeed())
+}
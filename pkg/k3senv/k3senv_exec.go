@@ -0,0 +1,68 @@
+package k3senv
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// k3sKubeconfigPath is the path of the admin kubeconfig written by k3s in the
+// container.
+const k3sKubeconfigPath = "/etc/rancher/k3s/k3s.yaml"
+
+// ExecInContainer runs the given command in the k3s container, e.g. to
+// diagnose test failures, and returns its output and exit code. The command is
+// not run through a shell, so its arguments need no quoting. A non-zero exit
+// code is not an error.
+func (e *K3sEnv) ExecInContainer(
+	ctx context.Context,
+	cmd []string,
+) (stdout string, stderr string, exitCode int, err error) {
+	if err := e.checkContainer("exec"); err != nil {
+		return "", "", 0, err
+	}
+
+	if len(cmd) == 0 {
+		return "", "", 0, errors.New("command cannot be empty")
+	}
+
+	e.debugf("Executing in k3s container: %v", cmd)
+
+	code, reader, err := e.container.Exec(ctx, cmd)
+	if err != nil {
+		return "", "", 0, fmt.Errorf("failed to execute %q in container: %w", cmd[0], err)
+	}
+
+	var outBuf, errBuf bytes.Buffer
+	if _, err := stdcopy.StdCopy(&outBuf, &errBuf, reader); err != nil {
+		return "", "", code, fmt.Errorf("failed to read output of %q: %w", cmd[0], err)
+	}
+
+	return outBuf.String(), errBuf.String(), code, nil
+}
+
+// ExecKubectl runs kubectl with the given arguments in the k3s container, with
+// the admin kubeconfig of k3s, and returns its standard output:
+//
+//	out, err := env.ExecKubectl(ctx, "get", "pods", "-A", "-o", "wide")
+//
+// Unlike ExecInContainer, a non-zero exit code is returned as an error,
+// including the standard error of kubectl.
+func (e *K3sEnv) ExecKubectl(ctx context.Context, args ...string) (string, error) {
+	cmd := append([]string{"kubectl", "--kubeconfig=" + k3sKubeconfigPath}, args...)
+
+	stdout, stderr, code, err := e.ExecInContainer(ctx, cmd)
+	if err != nil {
+		return "", err
+	}
+
+	if code != 0 {
+		return stdout, fmt.Errorf("kubectl %s exited with code %d: %s", strings.Join(args, " "), code, strings.TrimSpace(stderr))
+	}
+
+	return stdout, nil
+}
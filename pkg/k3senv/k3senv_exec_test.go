@@ -0,0 +1,70 @@
+package k3senv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lburgazzoli/k3s-envtest/pkg/k3senv"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestExecInContainer(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	env, err := k3senv.New()
+	g.Expect(err).NotTo(HaveOccurred())
+	t.Cleanup(func() {
+		_ = env.Stop(ctx)
+	})
+
+	_, _, _, err = env.ExecInContainer(ctx, []string{"true"})
+	g.Expect(err).To(MatchError(ContainSubstring("call Start() first")))
+
+	_, err = env.ExecKubectl(ctx, "version")
+	g.Expect(err).To(MatchError(ContainSubstring("call Start() first")))
+
+	err = env.Start(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	t.Run("Output and exit code", func(t *testing.T) {
+		g := NewWithT(t)
+
+		stdout, stderr, code, err := env.ExecInContainer(ctx, []string{"sh", "-c", "echo out; echo err >&2; exit 3"})
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(stdout).To(Equal("out\n"))
+		g.Expect(stderr).To(Equal("err\n"))
+		g.Expect(code).To(Equal(3))
+	})
+
+	t.Run("Arguments are not interpreted by a shell", func(t *testing.T) {
+		g := NewWithT(t)
+
+		stdout, _, code, err := env.ExecInContainer(ctx, []string{"echo", "$HOME; true"})
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(code).To(Equal(0))
+		g.Expect(stdout).To(Equal("$HOME; true\n"))
+	})
+
+	t.Run("Empty command", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, _, _, err := env.ExecInContainer(ctx, nil)
+		g.Expect(err).To(MatchError(ContainSubstring("command cannot be empty")))
+	})
+
+	t.Run("Kubectl", func(t *testing.T) {
+		g := NewWithT(t)
+
+		out, err := env.ExecKubectl(ctx, "get", "namespace", "default", "-o", "name")
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(out).To(Equal("namespace/default\n"))
+
+		_, err = env.ExecKubectl(ctx, "get", "namespace", "k3senv-missing")
+		g.Expect(err).To(MatchError(And(
+			ContainSubstring("exited with code 1"),
+			ContainSubstring("not found"),
+		)))
+	})
+}
@@ -0,0 +1,61 @@
+package k3senv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// IsAPIServerHealthy reports whether the /healthz endpoint of the API server
+// responds with 200. It returns false before Start and while the environment
+// is paused, without sending any request.
+func (e *K3sEnv) IsAPIServerHealthy(ctx context.Context) bool {
+	if e.cfg == nil || e.IsPaused() {
+		return false
+	}
+
+	dc, err := e.GetDiscoveryClient()
+	if err != nil {
+		return false
+	}
+
+	status := 0
+
+	err = dc.RESTClient().Get().AbsPath("/healthz").Do(ctx).StatusCode(&status).Error()
+	if err != nil {
+		e.debugf("API server not healthy: %v", err)
+		return false
+	}
+
+	return status == http.StatusOK
+}
+
+// WaitForAPIServerHealthy waits for IsAPIServerHealthy to report the API
+// server as healthy, polling it with the CRD poll interval. It returns an error
+// wrapping ErrWaitTimeout if the API server is not healthy within the timeout,
+// and ErrEnvironmentPaused while the environment is paused.
+func (e *K3sEnv) WaitForAPIServerHealthy(ctx context.Context, timeout time.Duration) error {
+	if e.cfg == nil {
+		return errors.New("cluster not started - call Start() first")
+	}
+
+	if e.IsPaused() {
+		return ErrEnvironmentPaused
+	}
+
+	waitOpts := WaitOptions{
+		PollInterval: e.options.CRD.PollInterval,
+		Timeout:      timeout,
+	}
+
+	err := e.poll(ctx, &waitOpts, func(ctx context.Context) (bool, error) {
+		return e.IsAPIServerHealthy(ctx), nil
+	})
+	if err != nil {
+		return fmt.Errorf("API server not healthy: %w", err)
+	}
+
+	return nil
+}
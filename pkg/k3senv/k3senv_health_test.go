@@ -0,0 +1,50 @@
+package k3senv_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lburgazzoli/k3s-envtest/pkg/k3senv"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestAPIServerHealth(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	env, err := k3senv.New()
+	g.Expect(err).NotTo(HaveOccurred())
+	t.Cleanup(func() {
+		_ = env.Stop(ctx)
+	})
+
+	g.Expect(env.IsAPIServerHealthy(ctx)).To(BeFalse())
+
+	err = env.WaitForAPIServerHealthy(ctx, time.Second)
+	g.Expect(err).To(MatchError(ContainSubstring("call Start() first")))
+
+	err = env.Start(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(env.IsAPIServerHealthy(ctx)).To(BeTrue())
+	g.Expect(env.WaitForAPIServerHealthy(ctx, 30*time.Second)).To(Succeed())
+
+	err = env.Pause(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(env.IsAPIServerHealthy(ctx)).To(BeFalse())
+
+	err = env.WaitForAPIServerHealthy(ctx, time.Second)
+	g.Expect(err).To(MatchError(k3senv.ErrEnvironmentPaused))
+
+	err = env.Reset(ctx)
+	g.Expect(err).To(MatchError(k3senv.ErrEnvironmentPaused))
+
+	err = env.Resume(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(env.IsAPIServerHealthy(ctx)).To(BeTrue())
+	g.Expect(env.Reset(ctx)).To(Succeed())
+}
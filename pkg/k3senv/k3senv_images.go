@@ -0,0 +1,77 @@
+package k3senv
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/lburgazzoli/k3s-envtest/internal/cert"
+)
+
+// k3sImagesImportDir is the directory of the container image tarballs are
+// copied to before being imported.
+const k3sImagesImportDir = "/tmp"
+
+// LoadImage imports an image of the local Docker or Podman image store, e.g. a
+// controller image built by the test suite, into the k3s container, so that
+// pods can run it without pulling it. The pods must not use the Always pull
+// policy, which is the default of images with the latest tag.
+func (e *K3sEnv) LoadImage(ctx context.Context, imageRef string) error {
+	if err := e.checkContainer("loading images"); err != nil {
+		return err
+	}
+
+	e.debugf("Loading image %s into k3s container", imageRef)
+
+	if err := e.container.LoadImages(ctx, imageRef); err != nil {
+		return fmt.Errorf("failed to load image %s: %w", imageRef, err)
+	}
+
+	return nil
+}
+
+// ImportImageFromTar imports the images of a tarball, e.g. saved with
+// "docker save", into the k3s container, like LoadImage.
+func (e *K3sEnv) ImportImageFromTar(ctx context.Context, tarPath string) error {
+	if err := e.checkContainer("loading images"); err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(tarPath); err != nil {
+		return fmt.Errorf("failed to read image tarball %s: %w", tarPath, err)
+	}
+
+	e.debugf("Importing image tarball %s into k3s container", tarPath)
+
+	containerPath := path.Join(k3sImagesImportDir, filepath.Base(tarPath))
+
+	if err := e.container.CopyFileToContainer(ctx, tarPath, containerPath, int64(cert.DefaultFilePermission)); err != nil {
+		return fmt.Errorf("failed to copy image tarball %s to container: %w", tarPath, err)
+	}
+
+	defer func() {
+		if _, _, _, err := e.ExecInContainer(ctx, []string{"rm", "-f", containerPath}); err != nil {
+			e.debugf("Failed to remove image tarball %s from container: %v", containerPath, err)
+		}
+	}()
+
+	_, stderr, code, err := e.ExecInContainer(
+		ctx,
+		[]string{"ctr", "-n=k8s.io", "images", "import", "--all-platforms", containerPath},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to import image tarball %s: %w", tarPath, err)
+	}
+
+	if code != 0 {
+		return fmt.Errorf(
+			"failed to import image tarball %s: ctr exited with code %d: %s",
+			tarPath, code, strings.TrimSpace(stderr),
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package k3senv_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lburgazzoli/k3s-envtest/pkg/k3senv"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestLoadImage(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	env, err := k3senv.New()
+	g.Expect(err).NotTo(HaveOccurred())
+	t.Cleanup(func() {
+		_ = env.Stop(ctx)
+	})
+
+	err = env.LoadImage(ctx, testPauseImage)
+	g.Expect(err).To(MatchError(ContainSubstring("call Start() first")))
+
+	err = env.ImportImageFromTar(ctx, "image.tar")
+	g.Expect(err).To(MatchError(ContainSubstring("call Start() first")))
+
+	err = env.Start(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	t.Run("Missing image", func(t *testing.T) {
+		g := NewWithT(t)
+
+		err := env.LoadImage(ctx, "k3senv.example.com/missing:latest")
+		g.Expect(err).To(MatchError(ContainSubstring("failed to load image k3senv.example.com/missing:latest")))
+	})
+
+	t.Run("Missing tarball", func(t *testing.T) {
+		g := NewWithT(t)
+
+		tarPath := filepath.Join(t.TempDir(), "missing.tar")
+
+		err := env.ImportImageFromTar(ctx, tarPath)
+		g.Expect(err).To(MatchError(ContainSubstring("failed to read image tarball")))
+	})
+
+	t.Run("Invalid tarball", func(t *testing.T) {
+		g := NewWithT(t)
+
+		tarPath := filepath.Join(t.TempDir(), "invalid.tar")
+		g.Expect(os.WriteFile(tarPath, []byte("not a tarball"), 0o600)).To(Succeed())
+
+		err := env.ImportImageFromTar(ctx, tarPath)
+		g.Expect(err).To(MatchError(ContainSubstring("failed to import image tarball")))
+	})
+}
@@ -0,0 +1,91 @@
+package k3senv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/lburgazzoli/k3s-envtest/internal/cert"
+
+	"k8s.io/apimachinery/pkg/util/uuid"
+)
+
+// GetAPIServerURL returns the URL of the API server, as in the server field
+// of the kubeconfig returned by GetKubeconfig, or an empty string before
+// Start. It is the Host of the rest config returned by Config.
+func (e *K3sEnv) GetAPIServerURL() string {
+	if e.cfg == nil {
+		return ""
+	}
+
+	return e.cfg.Host
+}
+
+// GetAPIServerCA returns the PEM encoded CA certificate of the API server, as
+// in the certificate-authority-data field of the kubeconfig returned by
+// GetKubeconfig, or nil before Start. Along with GetAPIServerURL, it allows
+// building custom kubeconfigs or clients.
+func (e *K3sEnv) GetAPIServerCA() []byte {
+	if e.cfg == nil {
+		return nil
+	}
+
+	return slices.Clone(e.cfg.CAData)
+}
+
+// WriteKubeconfig writes the kubeconfig returned by GetKubeconfig to the given
+// path, readable by the current user only, for tools such as helm or
+// kustomize that only read kubeconfig files.
+func (e *K3sEnv) WriteKubeconfig(ctx context.Context, path string) error {
+	kc, err := e.GetKubeconfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(path, kc, cert.DefaultFilePermission); err != nil {
+		return fmt.Errorf("failed to write kubeconfig to %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// GetKubeconfigPath writes the kubeconfig to a kubeconfig-<container ID> file
+// of the temporary directory, on first call, and returns its path. The file is
+// deleted by a teardown task on Stop:
+//
+//	path, err := env.GetKubeconfigPath(ctx)
+//	cmd := exec.CommandContext(ctx, "helm", "install", "--kubeconfig", path, ...)
+func (e *K3sEnv) GetKubeconfigPath(ctx context.Context) (string, error) {
+	if e.kubeconfigPath != "" {
+		return e.kubeconfigPath, nil
+	}
+
+	id := e.ContainerID()
+	if id == "" {
+		// pre-existing clusters have no container
+		id = string(uuid.NewUUID())
+	}
+
+	path := filepath.Join(os.TempDir(), "kubeconfig-"+id)
+
+	if err := e.WriteKubeconfig(ctx, path); err != nil {
+		return "", err
+	}
+
+	e.kubeconfigPath = path
+
+	e.AddTeardown(func(context.Context) error {
+		e.kubeconfigPath = ""
+
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove kubeconfig %s: %w", path, err)
+		}
+
+		return nil
+	})
+
+	return path, nil
+}
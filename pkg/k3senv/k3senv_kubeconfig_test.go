@@ -0,0 +1,82 @@
+package k3senv_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lburgazzoli/k3s-envtest/pkg/k3senv"
+
+	"k8s.io/client-go/tools/clientcmd"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestKubeconfigFiles(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	env, err := k3senv.New()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	err = env.WriteKubeconfig(ctx, filepath.Join(t.TempDir(), "kubeconfig"))
+	g.Expect(err).To(MatchError(ContainSubstring("call Start() first")))
+
+	err = env.Start(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	t.Run("Write", func(t *testing.T) {
+		g := NewWithT(t)
+
+		path := filepath.Join(t.TempDir(), "kubeconfig")
+		g.Expect(env.WriteKubeconfig(ctx, path)).To(Succeed())
+
+		info, err := os.Stat(path)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(info.Mode().Perm()).To(Equal(os.FileMode(0o600)))
+
+		cfg, err := clientcmd.BuildConfigFromFlags("", path)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(cfg.Host).To(Equal(env.Config().Host))
+	})
+
+	path, err := env.GetKubeconfigPath(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(filepath.Base(path)).To(Equal("kubeconfig-" + env.ContainerID()))
+	g.Expect(path).To(BeAnExistingFile())
+
+	again, err := env.GetKubeconfigPath(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(again).To(Equal(path))
+
+	g.Expect(env.Stop(ctx)).To(Succeed())
+	g.Expect(path).NotTo(BeAnExistingFile())
+}
+
+func TestAPIServerAccessors(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	env, err := k3senv.New()
+	g.Expect(err).NotTo(HaveOccurred())
+	t.Cleanup(func() {
+		_ = env.Stop(ctx)
+	})
+
+	g.Expect(env.GetAPIServerURL()).To(BeEmpty())
+	g.Expect(env.GetAPIServerCA()).To(BeNil())
+
+	err = env.Start(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	kc, err := env.GetKubeconfig(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	raw, err := clientcmd.Load(kc)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	cluster := raw.Clusters[raw.Contexts[raw.CurrentContext].Cluster]
+	g.Expect(env.GetAPIServerURL()).To(Equal(cluster.Server))
+	g.Expect(env.GetAPIServerCA()).To(Equal(cluster.CertificateAuthorityData))
+}
@@ -0,0 +1,102 @@
+package k3senv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+// GetNodes lists the nodes of the cluster, a single one for a k3s container.
+//
+// The scheme of the environment must include the core types, which is the
+// case of the default one, as for TaintNode, UntaintNode and LabelNode.
+func (e *K3sEnv) GetNodes(ctx context.Context) ([]corev1.Node, error) {
+	if e.cli == nil {
+		return nil, errors.New("cluster not started - call Start() first")
+	}
+
+	list := corev1.NodeList{}
+
+	if err := e.cli.List(ctx, &list); err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	return list.Items, nil
+}
+
+// TaintNode adds the given taint to the node, replacing the existing one with
+// the same key and effect, if any, as kubectl taint does. The other taints of
+// the node are preserved.
+func (e *K3sEnv) TaintNode(ctx context.Context, nodeName string, taint corev1.Taint) error {
+	err := e.patchNode(ctx, nodeName, func(node *corev1.Node) {
+		node.Spec.Taints = slices.DeleteFunc(node.Spec.Taints, func(t corev1.Taint) bool {
+			return t.Key == taint.Key && t.Effect == taint.Effect
+		})
+		node.Spec.Taints = append(node.Spec.Taints, taint)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to taint node %s with %s: %w", nodeName, taint.ToString(), err)
+	}
+
+	return nil
+}
+
+// UntaintNode removes the taints with the given key, whatever their effect,
+// from the node. Removing a taint the node does not have is a no-op.
+func (e *K3sEnv) UntaintNode(ctx context.Context, nodeName string, taintKey string) error {
+	err := e.patchNode(ctx, nodeName, func(node *corev1.Node) {
+		node.Spec.Taints = slices.DeleteFunc(node.Spec.Taints, func(t corev1.Taint) bool {
+			return t.Key == taintKey
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to remove taint %s from node %s: %w", taintKey, nodeName, err)
+	}
+
+	return nil
+}
+
+// LabelNode sets the given labels on the node, overwriting the values of the
+// existing ones with the same keys. The other labels of the node are preserved.
+func (e *K3sEnv) LabelNode(ctx context.Context, nodeName string, labels map[string]string) error {
+	err := e.patchNode(ctx, nodeName, func(node *corev1.Node) {
+		if node.Labels == nil {
+			node.Labels = make(map[string]string, len(labels))
+		}
+		maps.Copy(node.Labels, labels)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to label node %s: %w", nodeName, err)
+	}
+
+	return nil
+}
+
+// patchNode applies mutate to the node with a strategic merge patch. As taints
+// are replaced as a whole by strategic merge patches, the patch is computed
+// from the current node, with optimistic locking, and retried on conflicts,
+// e.g. with the node status updates of the kubelet.
+func (e *K3sEnv) patchNode(ctx context.Context, nodeName string, mutate func(*corev1.Node)) error {
+	if e.cli == nil {
+		return errors.New("cluster not started - call Start() first")
+	}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		node := corev1.Node{}
+		if err := e.cli.Get(ctx, client.ObjectKey{Name: nodeName}, &node); err != nil {
+			return err
+		}
+
+		original := node.DeepCopy()
+		mutate(&node)
+
+		return e.cli.Patch(ctx, &node, client.StrategicMergeFrom(original, client.MergeFromWithOptimisticLock{}))
+	})
+}
@@ -0,0 +1,95 @@
+package k3senv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lburgazzoli/k3s-envtest/pkg/k3senv"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	corev1 "k8s.io/api/core/v1"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestNodes(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	env, err := k3senv.New()
+	g.Expect(err).NotTo(HaveOccurred())
+	t.Cleanup(func() {
+		_ = env.Stop(ctx)
+	})
+
+	_, err = env.GetNodes(ctx)
+	g.Expect(err).To(MatchError(ContainSubstring("call Start() first")))
+
+	err = env.LabelNode(ctx, "node", map[string]string{"key": "value"})
+	g.Expect(err).To(MatchError(ContainSubstring("call Start() first")))
+
+	err = env.Start(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	nodes, err := env.GetNodes(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(nodes).To(HaveLen(1))
+
+	nodeName := nodes[0].Name
+	getNode := func() corev1.Node {
+		node := corev1.Node{}
+		g.Expect(env.Client().Get(ctx, client.ObjectKey{Name: nodeName}, &node)).To(Succeed())
+		return node
+	}
+
+	t.Run("Labels", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(env.LabelNode(ctx, nodeName, map[string]string{"k3senv.io/zone": "a"})).To(Succeed())
+		g.Expect(env.LabelNode(ctx, nodeName, map[string]string{"k3senv.io/zone": "b", "k3senv.io/tier": "test"})).To(Succeed())
+
+		labels := getNode().Labels
+		g.Expect(labels).To(HaveKeyWithValue("k3senv.io/zone", "b"))
+		g.Expect(labels).To(HaveKeyWithValue("k3senv.io/tier", "test"))
+		g.Expect(labels).To(HaveKeyWithValue(corev1.LabelHostname, nodeName))
+	})
+
+	t.Run("Taints", func(t *testing.T) {
+		g := NewWithT(t)
+
+		noSchedule := corev1.Taint{Key: "k3senv.io/dedicated", Value: "a", Effect: corev1.TaintEffectNoSchedule}
+		preferNoSchedule := corev1.Taint{Key: "k3senv.io/other", Effect: corev1.TaintEffectPreferNoSchedule}
+
+		g.Expect(env.TaintNode(ctx, nodeName, noSchedule)).To(Succeed())
+		g.Expect(env.TaintNode(ctx, nodeName, preferNoSchedule)).To(Succeed())
+
+		// same key and effect replaces the taint
+		noSchedule.Value = "b"
+		g.Expect(env.TaintNode(ctx, nodeName, noSchedule)).To(Succeed())
+
+		taints := getNode().Spec.Taints
+		g.Expect(taints).To(HaveLen(2))
+		g.Expect(taints).To(ContainElement(And(
+			HaveField("Key", "k3senv.io/dedicated"),
+			HaveField("Value", "b"),
+		)))
+		g.Expect(taints).To(ContainElement(HaveField("Key", "k3senv.io/other")))
+
+		g.Expect(env.UntaintNode(ctx, nodeName, "k3senv.io/dedicated")).To(Succeed())
+		g.Expect(env.UntaintNode(ctx, nodeName, "k3senv.io/missing")).To(Succeed())
+
+		taints = getNode().Spec.Taints
+		g.Expect(taints).To(HaveLen(1))
+		g.Expect(taints[0].Key).To(Equal("k3senv.io/other"))
+
+		g.Expect(env.UntaintNode(ctx, nodeName, "k3senv.io/other")).To(Succeed())
+		g.Expect(getNode().Spec.Taints).To(BeEmpty())
+	})
+
+	t.Run("Missing node", func(t *testing.T) {
+		g := NewWithT(t)
+
+		err := env.LabelNode(ctx, "missing", map[string]string{"key": "value"})
+		g.Expect(err).To(MatchError(ContainSubstring("failed to label node missing")))
+	})
+}
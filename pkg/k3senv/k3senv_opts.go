@@ -1,14 +1,26 @@
 package k3senv
 
 import (
+	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
+	"log/slog"
+	"maps"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
 	"slices"
 	"strings"
 	"time"
 
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/lburgazzoli/k3s-envtest/internal/cert"
 	"github.com/spf13/viper"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlwebhook "sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/utils/ptr"
@@ -17,12 +29,14 @@ import (
 const (
 	DefaultK3sImage          = "rancher/k3s:v1.32.9-k3s1"
 	DefaultK3sLogRedirection = false
+	DefaultK3sStartTimeout   = 5 * time.Minute
 	DefaultWebhookPort       = 9443
 	DefaultCertDirPrefix     = "/tmp/k3senv-certs-"
 	DefaultCertValidity      = 24 * time.Hour
 
-	DefaultWebhookPollInterval = 500 * time.Millisecond
-	DefaultCRDPollInterval     = 100 * time.Millisecond
+	DefaultWebhookPollInterval  = 500 * time.Millisecond
+	DefaultWebhookTLSMinVersion = tls.VersionTLS12
+	DefaultCRDPollInterval      = 100 * time.Millisecond
 
 	// WebhookReadyTimeout is the internal default maximum time to wait for each
 	// individual webhook endpoint to become ready. The system polls each endpoint
@@ -40,6 +54,36 @@ const (
 	CRDReadyTimeout = 30 * time.Second
 )
 
+// CertKeyAlgorithm identifies the algorithm used to generate certificate keys.
+// It aliases the internal cert.KeyAlgorithm so callers outside this module can
+// refer to the supported algorithms.
+type CertKeyAlgorithm = cert.KeyAlgorithm
+
+const (
+	CertKeyAlgorithmRSA2048  = cert.RSA2048
+	CertKeyAlgorithmRSA4096  = cert.RSA4096
+	CertKeyAlgorithmECDSA256 = cert.ECDSA256
+	CertKeyAlgorithmECDSA384 = cert.ECDSA384
+	CertKeyAlgorithmEd25519  = cert.Ed25519
+)
+
+// K3sPullPolicy is the policy pulling the k3s image when the container starts.
+type K3sPullPolicy string
+
+const (
+	// K3sPullIfNotPresent pulls the k3s image only if it is not present
+	// locally, the default.
+	K3sPullIfNotPresent K3sPullPolicy = "IfNotPresent"
+
+	// K3sPullAlways pulls the k3s image even if present locally, e.g. to get
+	// the latest version of a tag.
+	K3sPullAlways K3sPullPolicy = "Always"
+
+	// K3sPullNever never pulls the k3s image, which must be present locally,
+	// e.g. in air-gapped environments.
+	K3sPullNever K3sPullPolicy = "Never"
+)
+
 // Bool returns a pointer to the boolean value passed in.
 // This is a convenience alias to ptr.To from k8s.io/utils/ptr.
 // Use this for creating pointer boolean values for configuration.
@@ -68,6 +112,15 @@ type Logger interface {
 	Logf(format string, args ...any)
 }
 
+// TestingT is the subset of testing.TB used by WithTestingT and MustStart, so
+// that *testing.T and *testing.B can be passed directly.
+type TestingT interface {
+	Logger
+	Helper()
+	Cleanup(f func())
+	Fatalf(format string, args ...any)
+}
+
 // LoggerFunc is an adapter that allows a printf-style function to be used as a Logger.
 // This makes it easy to integrate with any logging framework that provides a Printf-like method.
 //
@@ -96,12 +149,45 @@ func (f optionFunc) ApplyToOptions(o *Options) {
 
 // WebhookConfig groups all webhook-related configuration.
 type WebhookConfig struct {
-	Port               int           `mapstructure:"port"`
-	AutoInstall        *bool         `mapstructure:"auto_install"`
-	CheckReadiness     *bool         `mapstructure:"check_readiness"`
-	ReadyTimeout       time.Duration `mapstructure:"ready_timeout"`
-	HealthCheckTimeout time.Duration `mapstructure:"health_check_timeout"`
-	PollInterval       time.Duration `mapstructure:"poll_interval"`
+	Port               int              `mapstructure:"port"`
+	AutoInstall        *bool            `mapstructure:"auto_install"`
+	CheckReadiness     *bool            `mapstructure:"check_readiness"`
+	ReadyTimeout       time.Duration    `mapstructure:"ready_timeout"`
+	HealthCheckTimeout time.Duration    `mapstructure:"health_check_timeout"`
+	PollInterval       time.Duration    `mapstructure:"poll_interval"`
+	TLS                WebhookTLSConfig `mapstructure:"tls"`
+
+	// HostResolver resolves the hostname containers use to reach the webhook
+	// server. Defaults to DefaultWebhookHostResolver.
+	HostResolver WebhookHostResolver `mapstructure:"-"`
+
+	// OptionsMutator customizes the options of the server returned by
+	// WebhookServer, see WithWebhookServerOptions.
+	OptionsMutator func(*ctrlwebhook.Options) `mapstructure:"-"`
+}
+
+// WebhookHostResolver resolves the hostname the k3s container uses to reach the
+// webhook server running on the host machine.
+type WebhookHostResolver func(ctx context.Context) (string, error)
+
+// DefaultWebhookHostResolver resolves to DefaultWebhookContainerHost, which is
+// mapped to the host gateway in the k3s container.
+func DefaultWebhookHostResolver(_ context.Context) (string, error) {
+	return DefaultWebhookContainerHost, nil
+}
+
+// WebhookTLSConfig groups the TLS settings of the webhook server.
+// Values are crypto/tls constants, e.g. tls.VersionTLS13 or
+// tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256. When set through environment
+// variables they can be given in decimal or hexadecimal (0x0304) notation.
+type WebhookTLSConfig struct {
+	// MinVersion is the minimum TLS version accepted by the webhook server.
+	// Defaults to tls.VersionTLS12.
+	MinVersion uint16 `mapstructure:"min_version"`
+
+	// CipherSuites restricts the cipher suites accepted by the webhook server
+	// for TLS 1.2 and below. If empty, the crypto/tls defaults are used.
+	CipherSuites []uint16 `mapstructure:"cipher_suites"`
 }
 
 // CRDConfig groups all CRD-related configuration.
@@ -131,12 +217,95 @@ type K3sConfig struct {
 	Args           []string       `mapstructure:"args"`
 	LogRedirection *bool          `mapstructure:"log_redirection"`
 	Network        *NetworkConfig `mapstructure:"network"`
+
+	// StartupRetries is the number of times starting the container is retried,
+	// with exponential backoff, if it fails. Defaults to 0 (no retry).
+	StartupRetries int `mapstructure:"startup_retries"`
+
+	// StartTimeout is the maximum time for each attempt to start the container
+	// and wait for k3s to be ready. Defaults to DefaultK3sStartTimeout.
+	StartTimeout time.Duration `mapstructure:"start_timeout"`
+
+	// PullPolicy is the policy pulling the k3s image. Defaults to
+	// K3sPullIfNotPresent.
+	PullPolicy K3sPullPolicy `mapstructure:"pull_policy"`
+
+	// RegistryMirrors maps registries, e.g. "docker.io", to the URL of the
+	// mirror their images are pulled from. From environment variables, they
+	// are read as "registry=url" pairs separated by commas.
+	RegistryMirrors map[string]string `mapstructure:"registry_mirrors"`
+
+	// RegistryCreds maps registries or mirror hosts to their credentials. From
+	// environment variables, they are read as "registry=username:password"
+	// pairs separated by commas.
+	RegistryCreds map[string]RegistryCredentials `mapstructure:"registry_creds"`
+
+	// ContainerLabels are the Docker labels of the k3s container, e.g. to
+	// identify it in cleanup scripts. From environment variables, they are
+	// read as "key=value" pairs separated by commas.
+	ContainerLabels map[string]string `mapstructure:"container_labels"`
+
+	// ContainerEnv are the environment variables of the k3s container, e.g.
+	// K3S_* configuration variables. From environment variables, they are
+	// read as "name=value" pairs separated by commas.
+	ContainerEnv map[string]string `mapstructure:"container_env"`
+
+	// AuditPolicy is the YAML audit policy of the API server, enabling audit
+	// logging if set, see WithK3sAuditPolicy.
+	AuditPolicy string `mapstructure:"audit_policy"`
+}
+
+// RegistryCredentials are the credentials used to authenticate to a registry.
+type RegistryCredentials struct {
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+}
+
+// ClusterConfig groups the configuration of a pre-existing cluster used
+// instead of the k3s container.
+type ClusterConfig struct {
+	// Kubeconfig is the path of the kubeconfig file of the pre-existing
+	// cluster. If empty, a k3s container is started.
+	Kubeconfig string `mapstructure:"kubeconfig"`
 }
 
 // CertificateConfig groups all certificate-related configuration.
 type CertificateConfig struct {
 	Path     string        `mapstructure:"path"`
 	Validity time.Duration `mapstructure:"validity"`
+
+	// SANs are the Subject Alternative Names of the generated webhook certificate.
+	// Defaults to CertificateSANs.
+	SANs []string `mapstructure:"sans"`
+
+	// CACert and CAKey are the PEM encoded CA used to sign the webhook certificate.
+	// If not set, a self-signed CA is generated.
+	CACert []byte `mapstructure:"-"`
+	CAKey  []byte `mapstructure:"-"`
+
+	// KeyAlgorithm is the algorithm used to generate the certificate keys.
+	// Defaults to cert.DefaultKeyAlgorithm (RSA2048).
+	KeyAlgorithm cert.KeyAlgorithm `mapstructure:"key_algorithm"`
+
+	// CertFile, KeyFile and CAFile are pre-generated PEM files to use instead of
+	// generating certificates. Relative paths are resolved against Path, if set.
+	CertFile string `mapstructure:"cert_file"`
+	KeyFile  string `mapstructure:"key_file"`
+	CAFile   string `mapstructure:"ca_file"`
+}
+
+// hasFiles reports whether pre-generated certificate files are configured.
+func (c *CertificateConfig) hasFiles() bool {
+	return c.CertFile != "" || c.KeyFile != "" || c.CAFile != ""
+}
+
+// resolveFile resolves a relative certificate file path against Path, if set.
+func (c *CertificateConfig) resolveFile(file string) string {
+	if file == "" || filepath.IsAbs(file) || c.Path == "" {
+		return file
+	}
+
+	return filepath.Join(c.Path, file)
 }
 
 // ManifestConfig groups all manifest-related configuration.
@@ -153,15 +322,39 @@ type LoggingConfig struct {
 	Enabled *bool `mapstructure:"enabled"`
 }
 
+// Hook is a lifecycle hook run before Start or Stop.
+type Hook func(ctx context.Context) error
+
+// PostStartHook is a lifecycle hook run once Start completed, receiving the
+// fully initialized environment.
+type PostStartHook func(ctx context.Context, env *K3sEnv) error
+
+// HooksConfig groups the lifecycle hooks. Hooks of each kind run in the order
+// they have been registered, and the first error aborts the lifecycle step.
+type HooksConfig struct {
+	PreStart  []Hook          `mapstructure:"-"`
+	PostStart []PostStartHook `mapstructure:"-"`
+	PreStop   []Hook          `mapstructure:"-"`
+}
+
 type Options struct {
 	Scheme      *runtime.Scheme   `mapstructure:"-"`
 	Webhook     WebhookConfig     `mapstructure:"webhook"`
 	CRD         CRDConfig         `mapstructure:"crd"`
 	K3s         K3sConfig         `mapstructure:"k3s"`
+	Cluster     ClusterConfig     `mapstructure:"cluster"`
 	Certificate CertificateConfig `mapstructure:"certificate"`
 	Manifest    ManifestConfig    `mapstructure:"manifest"`
 	Logging     LoggingConfig     `mapstructure:"logging"`
+	Hooks       HooksConfig       `mapstructure:"-"`
 	Logger      Logger            `mapstructure:"-"`
+
+	// SlogHandler receives the logs as structured records, taking precedence
+	// over Logger, see WithSlogHandler.
+	SlogHandler slog.Handler `mapstructure:"-"`
+
+	// TestingT is the test the environment belongs to, see WithTestingT.
+	TestingT TestingT `mapstructure:"-"`
 }
 
 func (o *Options) ApplyOptions(opts []Option) *Options {
@@ -195,6 +388,18 @@ func (o *Options) ApplyToOptions(target *Options) {
 	if o.Webhook.PollInterval != 0 {
 		target.Webhook.PollInterval = o.Webhook.PollInterval
 	}
+	if o.Webhook.TLS.MinVersion != 0 {
+		target.Webhook.TLS.MinVersion = o.Webhook.TLS.MinVersion
+	}
+	if len(o.Webhook.TLS.CipherSuites) > 0 {
+		target.Webhook.TLS.CipherSuites = slices.Clone(o.Webhook.TLS.CipherSuites)
+	}
+	if o.Webhook.HostResolver != nil {
+		target.Webhook.HostResolver = o.Webhook.HostResolver
+	}
+	if o.Webhook.OptionsMutator != nil {
+		target.Webhook.OptionsMutator = o.Webhook.OptionsMutator
+	}
 
 	// CRD config
 	if o.CRD.ReadyTimeout != 0 {
@@ -214,6 +419,42 @@ func (o *Options) ApplyToOptions(target *Options) {
 	if o.K3s.LogRedirection != nil {
 		target.K3s.LogRedirection = o.K3s.LogRedirection
 	}
+	if o.K3s.StartupRetries != 0 {
+		target.K3s.StartupRetries = o.K3s.StartupRetries
+	}
+	if o.K3s.StartTimeout != 0 {
+		target.K3s.StartTimeout = o.K3s.StartTimeout
+	}
+	if o.K3s.PullPolicy != "" {
+		target.K3s.PullPolicy = o.K3s.PullPolicy
+	}
+	if o.K3s.AuditPolicy != "" {
+		target.K3s.AuditPolicy = o.K3s.AuditPolicy
+	}
+	if len(o.K3s.RegistryMirrors) > 0 {
+		if target.K3s.RegistryMirrors == nil {
+			target.K3s.RegistryMirrors = make(map[string]string, len(o.K3s.RegistryMirrors))
+		}
+		maps.Copy(target.K3s.RegistryMirrors, o.K3s.RegistryMirrors)
+	}
+	if len(o.K3s.RegistryCreds) > 0 {
+		if target.K3s.RegistryCreds == nil {
+			target.K3s.RegistryCreds = make(map[string]RegistryCredentials, len(o.K3s.RegistryCreds))
+		}
+		maps.Copy(target.K3s.RegistryCreds, o.K3s.RegistryCreds)
+	}
+	if len(o.K3s.ContainerLabels) > 0 {
+		if target.K3s.ContainerLabels == nil {
+			target.K3s.ContainerLabels = make(map[string]string, len(o.K3s.ContainerLabels))
+		}
+		maps.Copy(target.K3s.ContainerLabels, o.K3s.ContainerLabels)
+	}
+	if len(o.K3s.ContainerEnv) > 0 {
+		if target.K3s.ContainerEnv == nil {
+			target.K3s.ContainerEnv = make(map[string]string, len(o.K3s.ContainerEnv))
+		}
+		maps.Copy(target.K3s.ContainerEnv, o.K3s.ContainerEnv)
+	}
 	if o.K3s.Network != nil {
 		if target.K3s.Network == nil {
 			target.K3s.Network = &NetworkConfig{}
@@ -229,6 +470,11 @@ func (o *Options) ApplyToOptions(target *Options) {
 		}
 	}
 
+	// Cluster config
+	if o.Cluster.Kubeconfig != "" {
+		target.Cluster.Kubeconfig = o.Cluster.Kubeconfig
+	}
+
 	// Certificate config
 	if o.Certificate.Path != "" {
 		target.Certificate.Path = o.Certificate.Path
@@ -236,6 +482,27 @@ func (o *Options) ApplyToOptions(target *Options) {
 	if o.Certificate.Validity != 0 {
 		target.Certificate.Validity = o.Certificate.Validity
 	}
+	if len(o.Certificate.SANs) > 0 {
+		target.Certificate.SANs = slices.Clone(o.Certificate.SANs)
+	}
+	if len(o.Certificate.CACert) > 0 {
+		target.Certificate.CACert = o.Certificate.CACert
+	}
+	if len(o.Certificate.CAKey) > 0 {
+		target.Certificate.CAKey = o.Certificate.CAKey
+	}
+	if o.Certificate.KeyAlgorithm != "" {
+		target.Certificate.KeyAlgorithm = o.Certificate.KeyAlgorithm
+	}
+	if o.Certificate.CertFile != "" {
+		target.Certificate.CertFile = o.Certificate.CertFile
+	}
+	if o.Certificate.KeyFile != "" {
+		target.Certificate.KeyFile = o.Certificate.KeyFile
+	}
+	if o.Certificate.CAFile != "" {
+		target.Certificate.CAFile = o.Certificate.CAFile
+	}
 
 	// Manifest config
 	if len(o.Manifest.Paths) > 0 {
@@ -250,10 +517,82 @@ func (o *Options) ApplyToOptions(target *Options) {
 		target.Logging.Enabled = o.Logging.Enabled
 	}
 
+	// Hooks
+	if len(o.Hooks.PreStart) > 0 {
+		target.Hooks.PreStart = append(target.Hooks.PreStart, o.Hooks.PreStart...)
+	}
+	if len(o.Hooks.PostStart) > 0 {
+		target.Hooks.PostStart = append(target.Hooks.PostStart, o.Hooks.PostStart...)
+	}
+	if len(o.Hooks.PreStop) > 0 {
+		target.Hooks.PreStop = append(target.Hooks.PreStop, o.Hooks.PreStop...)
+	}
+
 	// Logger
 	if o.Logger != nil {
 		target.Logger = o.Logger
 	}
+	if o.SlogHandler != nil {
+		target.SlogHandler = o.SlogHandler
+	}
+	if o.TestingT != nil {
+		target.TestingT = o.TestingT
+	}
+}
+
+// DeepCopy returns a deep copy of the options. The Scheme, the Logger and
+// SlogHandler, the TestingT, the hooks and the webhook HostResolver and OptionsMutator are
+// shared with the copy, as they are not copyable.
+func (o *Options) DeepCopy() *Options {
+	out := *o
+
+	out.Webhook.AutoInstall = copyBool(o.Webhook.AutoInstall)
+	out.Webhook.CheckReadiness = copyBool(o.Webhook.CheckReadiness)
+	out.Webhook.TLS.CipherSuites = slices.Clone(o.Webhook.TLS.CipherSuites)
+
+	out.K3s.Args = slices.Clone(o.K3s.Args)
+	out.K3s.LogRedirection = copyBool(o.K3s.LogRedirection)
+	out.K3s.RegistryMirrors = maps.Clone(o.K3s.RegistryMirrors)
+	out.K3s.RegistryCreds = maps.Clone(o.K3s.RegistryCreds)
+	out.K3s.ContainerLabels = maps.Clone(o.K3s.ContainerLabels)
+	out.K3s.ContainerEnv = maps.Clone(o.K3s.ContainerEnv)
+	if o.K3s.Network != nil {
+		network := *o.K3s.Network
+		network.Aliases = slices.Clone(o.K3s.Network.Aliases)
+		out.K3s.Network = &network
+	}
+
+	out.Certificate.SANs = slices.Clone(o.Certificate.SANs)
+	out.Certificate.CACert = slices.Clone(o.Certificate.CACert)
+	out.Certificate.CAKey = slices.Clone(o.Certificate.CAKey)
+
+	out.Manifest.Paths = slices.Clone(o.Manifest.Paths)
+	if o.Manifest.Objects != nil {
+		out.Manifest.Objects = make([]client.Object, 0, len(o.Manifest.Objects))
+		for _, obj := range o.Manifest.Objects {
+			copied, ok := obj.DeepCopyObject().(client.Object)
+			if !ok {
+				copied = obj
+			}
+			out.Manifest.Objects = append(out.Manifest.Objects, copied)
+		}
+	}
+
+	out.Logging.Enabled = copyBool(o.Logging.Enabled)
+
+	out.Hooks.PreStart = slices.Clone(o.Hooks.PreStart)
+	out.Hooks.PostStart = slices.Clone(o.Hooks.PostStart)
+	out.Hooks.PreStop = slices.Clone(o.Hooks.PreStop)
+
+	return &out
+}
+
+func copyBool(b *bool) *bool {
+	if b == nil {
+		return nil
+	}
+
+	return ptr.To(*b)
 }
 
 var _ Option = &Options{}
@@ -284,6 +623,49 @@ func WithCertValidity(duration time.Duration) Option {
 	return optionFunc(func(o *Options) { o.Certificate.Validity = duration })
 }
 
+// WithCertSANs replaces the Subject Alternative Names used for the generated
+// webhook certificate. Use WithAdditionalCertSANs to keep the defaults.
+func WithCertSANs(sans ...string) Option {
+	return optionFunc(func(o *Options) { o.Certificate.SANs = slices.Clone(sans) })
+}
+
+// WithAdditionalCertSANs appends Subject Alternative Names to the ones used for
+// the generated webhook certificate (CertificateSANs unless replaced).
+func WithAdditionalCertSANs(sans ...string) Option {
+	return optionFunc(func(o *Options) {
+		if len(o.Certificate.SANs) == 0 {
+			o.Certificate.SANs = slices.Clone(CertificateSANs)
+		}
+		o.Certificate.SANs = append(o.Certificate.SANs, sans...)
+	})
+}
+
+// WithCustomCA configures an existing CA, in PEM format, to sign the webhook
+// certificate instead of the auto-generated self-signed CA.
+func WithCustomCA(caCertPEM []byte, caKeyPEM []byte) Option {
+	return optionFunc(func(o *Options) {
+		o.Certificate.CACert = caCertPEM
+		o.Certificate.CAKey = caKeyPEM
+	})
+}
+
+// WithCertKeyAlgorithm configures the algorithm used to generate the webhook
+// certificate keys, e.g. cert.ECDSA256 for faster startup or non-RSA environments.
+func WithCertKeyAlgorithm(alg cert.KeyAlgorithm) Option {
+	return optionFunc(func(o *Options) { o.Certificate.KeyAlgorithm = alg })
+}
+
+// WithCertFiles configures pre-generated PEM files for the webhook certificate,
+// its key and the CA, skipping certificate generation. Relative paths are
+// resolved against the directory configured with WithCertPath, if any.
+func WithCertFiles(certPath string, keyPath string, caPath string) Option {
+	return optionFunc(func(o *Options) {
+		o.Certificate.CertFile = certPath
+		o.Certificate.KeyFile = keyPath
+		o.Certificate.CAFile = caPath
+	})
+}
+
 // Webhook options
 
 func WithWebhookPort(port int) Option {
@@ -310,6 +692,54 @@ func WithWebhookPollInterval(duration time.Duration) Option {
 	return optionFunc(func(o *Options) { o.Webhook.PollInterval = duration })
 }
 
+// WithWebhookTLSMinVersion sets the minimum TLS version accepted by the webhook
+// server, e.g. tls.VersionTLS13.
+func WithWebhookTLSMinVersion(version uint16) Option {
+	return optionFunc(func(o *Options) { o.Webhook.TLS.MinVersion = version })
+}
+
+// WithWebhookTLSCipherSuites restricts the cipher suites accepted by the webhook
+// server, e.g. tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256. Cipher suites only
+// apply to TLS 1.2 and below.
+func WithWebhookTLSCipherSuites(suites []uint16) Option {
+	return optionFunc(func(o *Options) { o.Webhook.TLS.CipherSuites = slices.Clone(suites) })
+}
+
+// WithWebhookHostResolver configures a custom function resolving the hostname
+// the k3s container uses to reach the webhook server. Use it when the default
+// host.containers.internal does not resolve in your container runtime, e.g.:
+//
+//	// Docker Desktop
+//	k3senv.WithWebhookHostResolver(func(context.Context) (string, error) {
+//	    return "host.docker.internal", nil
+//	})
+//
+//	// Docker on Linux (default bridge gateway)
+//	k3senv.WithWebhookHostResolver(func(context.Context) (string, error) {
+//	    return "172.17.0.1", nil
+//	})
+//
+// The resolved host is cached after the first successful resolution. Make sure
+// it is covered by the certificate SANs (see WithAdditionalCertSANs).
+func WithWebhookHostResolver(fn func(ctx context.Context) (string, error)) Option {
+	return optionFunc(func(o *Options) { o.Webhook.HostResolver = fn })
+}
+
+// WithWebhookServerOptions customizes the options of the server returned by
+// WebhookServer, e.g. to set a WebhookMux or additional TLSOpts, without an
+// option for each field:
+//
+//	k3senv.WithWebhookServerOptions(func(o *ctrlwebhook.Options) {
+//	    o.TLSOpts = append(o.TLSOpts, func(c *tls.Config) { c.NextProtos = []string{"http/1.1"} })
+//	})
+//
+// fn is applied after the default options are assembled. The port and the
+// certificate fields are always set by the environment afterwards, as the
+// installed webhook configurations depend on them.
+func WithWebhookServerOptions(fn func(*ctrlwebhook.Options)) Option {
+	return optionFunc(func(o *Options) { o.Webhook.OptionsMutator = fn })
+}
+
 // CRD options
 
 func WithCRDReadyTimeout(duration time.Duration) Option {
@@ -330,6 +760,20 @@ func WithK3sArgs(args ...string) Option {
 	return optionFunc(func(o *Options) { o.K3s.Args = append(o.K3s.Args, args...) })
 }
 
+// WithK3sDisableComponents disables the given components bundled with k3s,
+// among "traefik", "servicelb", "metrics-server", "local-storage" and
+// "coredns", to save resources or avoid interferences with tests. Each
+// component is added to the k3s arguments as --disable=<component>, after
+// those of previous WithK3sArgs calls; New returns an error for unknown
+// components, including those disabled through WithK3sArgs.
+func WithK3sDisableComponents(components ...string) Option {
+	return optionFunc(func(o *Options) {
+		for _, component := range components {
+			o.K3s.Args = append(o.K3s.Args, "--disable="+component)
+		}
+	})
+}
+
 func WithK3sLogRedirection(enable bool) Option {
 	return optionFunc(func(o *Options) { o.K3s.LogRedirection = &enable })
 }
@@ -361,12 +805,191 @@ func WithK3sNetworkMode(mode string) Option {
 	})
 }
 
+// WithStartupRetries retries starting the container up to n times, with
+// exponential backoff, e.g. to cope with transient image pull failures on CI.
+func WithStartupRetries(n int) Option {
+	return optionFunc(func(o *Options) { o.K3s.StartupRetries = n })
+}
+
+// WithContainerStartTimeout sets the maximum time for each attempt to start the
+// container and wait for k3s to be ready, independently of the context deadline.
+func WithContainerStartTimeout(d time.Duration) Option {
+	return optionFunc(func(o *Options) { o.K3s.StartTimeout = d })
+}
+
+// WithK3sPullPolicy sets the policy pulling the k3s image. With K3sPullNever,
+// Start fails if the image is not present locally.
+func WithK3sPullPolicy(policy K3sPullPolicy) Option {
+	return optionFunc(func(o *Options) { o.K3s.PullPolicy = policy })
+}
+
+// WithK3sAlwaysPull pulls the k3s image even if present locally.
+func WithK3sAlwaysPull() Option {
+	return WithK3sPullPolicy(K3sPullAlways)
+}
+
+// WithK3sNeverPull never pulls the k3s image, which must be present locally.
+func WithK3sNeverPull() Option {
+	return WithK3sPullPolicy(K3sPullNever)
+}
+
+// WithK3sRegistryMirror pulls the images of the given registry, e.g.
+// "docker.io", from the mirror at mirrorURL, e.g. in air-gapped environments.
+// The mirrors are written to /etc/rancher/k3s/registries.yaml in the container
+// before it starts. Multiple calls add mirrors for different registries.
+func WithK3sRegistryMirror(registry string, mirrorURL string) Option {
+	return optionFunc(func(o *Options) {
+		if o.K3s.RegistryMirrors == nil {
+			o.K3s.RegistryMirrors = make(map[string]string)
+		}
+		o.K3s.RegistryMirrors[registry] = mirrorURL
+	})
+}
+
+// WithK3sRegistryCreds authenticates to the given registry, or mirror host
+// such as "mirror.example.com:5000", with the given credentials.
+func WithK3sRegistryCreds(registry string, username string, password string) Option {
+	return optionFunc(func(o *Options) {
+		if o.K3s.RegistryCreds == nil {
+			o.K3s.RegistryCreds = make(map[string]RegistryCredentials)
+		}
+		o.K3s.RegistryCreds[registry] = RegistryCredentials{Username: username, Password: password}
+	})
+}
+
+// WithK3sAuditPolicy enables the audit log of the API server with the given
+// YAML audit policy, e.g.:
+//
+//	k3senv.WithK3sAuditPolicy(`
+//	apiVersion: audit.k8s.io/v1
+//	kind: Policy
+//	rules:
+//	  - level: Metadata
+//	`)
+//
+// The policy is copied into the container, and the log is written to
+// /var/log/k3s/audit.log, read by GetAuditLog and WatchAuditLog.
+func WithK3sAuditPolicy(policyYAML string) Option {
+	return optionFunc(func(o *Options) { o.K3s.AuditPolicy = policyYAML })
+}
+
+// WithK3sContainerLabels adds the given Docker labels to the k3s container,
+// e.g. to identify it in CI cleanup scripts. Multiple calls merge the labels,
+// the values of later calls taking precedence.
+func WithK3sContainerLabels(labels map[string]string) Option {
+	return optionFunc(func(o *Options) {
+		if o.K3s.ContainerLabels == nil {
+			o.K3s.ContainerLabels = make(map[string]string, len(labels))
+		}
+		maps.Copy(o.K3s.ContainerLabels, labels)
+	})
+}
+
+// WithK3sContainerEnv adds the given environment variables to the k3s
+// container, e.g. K3S_* variables configuring k3s. Multiple calls merge the
+// variables, the values of later calls taking precedence.
+func WithK3sContainerEnv(env map[string]string) Option {
+	return optionFunc(func(o *Options) {
+		if o.K3s.ContainerEnv == nil {
+			o.K3s.ContainerEnv = make(map[string]string, len(env))
+		}
+		maps.Copy(o.K3s.ContainerEnv, env)
+	})
+}
+
+// Cluster options
+
+// WithPreExistingCluster uses the cluster of the given kubeconfig file, e.g. a
+// KinD or minikube cluster, instead of starting a k3s container. Start loads
+// the kubeconfig and then installs the CRDs and webhooks as usual, and Stop
+// leaves the cluster running.
+//
+// The k3s options, Pause and Resume do not apply to a pre-existing cluster,
+// and the webhook host usually needs to be configured with
+// WithWebhookHostResolver so that the cluster can reach the webhook server.
+func WithPreExistingCluster(kubeconfigPath string) Option {
+	return optionFunc(func(o *Options) { o.Cluster.Kubeconfig = kubeconfigPath })
+}
+
+// WithPreExistingClusterFromEnv is like WithPreExistingCluster, reading the
+// kubeconfig path from the KUBECONFIG environment variable. If KUBECONFIG
+// holds a list of paths the first one is used, and if it is not set the
+// option has no effect.
+func WithPreExistingClusterFromEnv() Option {
+	return optionFunc(func(o *Options) {
+		for _, path := range filepath.SplitList(os.Getenv("KUBECONFIG")) {
+			if path != "" {
+				o.Cluster.Kubeconfig = path
+				return
+			}
+		}
+	})
+}
+
 // Logger options
 
 func WithLogger(logger Logger) Option {
 	return optionFunc(func(o *Options) { o.Logger = logger })
 }
 
+// WithSlogHandler sends the logs to the given slog handler, at debug level,
+// instead of the Logger. The logs of the environment have a "component"
+// attribute, the lines of the k3s container forwarded with log redirection
+// and the testcontainers logs a "source" one:
+//
+//	handler := slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug})
+//	env, err := k3senv.New(k3senv.WithSlogHandler(handler))
+func WithSlogHandler(h slog.Handler) Option {
+	return optionFunc(func(o *Options) { o.SlogHandler = h })
+}
+
+// WithSlogLogger sends the logs to the handler of the given slog logger, see
+// WithSlogHandler.
+func WithSlogLogger(l *slog.Logger) Option {
+	return WithSlogHandler(l.Handler())
+}
+
+// WithTestingT binds the environment to a test: t is used as the Logger, and
+// Start registers Stop as a cleanup of the test, so that the environment is
+// stopped at the end of the test without an explicit t.Cleanup:
+//
+//	env, err := k3senv.New(k3senv.WithTestingT(t))
+//	g.Expect(err).NotTo(HaveOccurred())
+//	g.Expect(env.Start(ctx)).To(Succeed())
+//
+// The cleanup is only registered once Start is called, even if it fails.
+func WithTestingT(t TestingT) Option {
+	return optionFunc(func(o *Options) {
+		o.Logger = t
+		o.TestingT = t
+	})
+}
+
+// Hook options
+
+// WithPreStart registers a hook run at the beginning of Start, before the
+// container is started. An error aborts Start.
+func WithPreStart(fn func(ctx context.Context) error) Option {
+	return optionFunc(func(o *Options) { o.Hooks.PreStart = append(o.Hooks.PreStart, fn) })
+}
+
+// WithPostStart registers a hook run at the end of a successful Start, e.g. to
+// install additional resources. An error makes Start fail, leaving the
+// environment started: Stop must still be called to release it.
+func WithPostStart(fn func(ctx context.Context, env *K3sEnv) error) Option {
+	return optionFunc(func(o *Options) { o.Hooks.PostStart = append(o.Hooks.PostStart, fn) })
+}
+
+// WithPreStop registers a hook run at the beginning of Stop, before the
+// teardown tasks. An error aborts Stop, leaving the environment running, so
+// Stop must be called again once the cause is fixed. The test cleanup
+// registered with WithTestingT or MustStart is the exception: as nothing could
+// stop the environment after it, the environment is stopped anyway and the
+// error is logged to the test.
+func WithPreStop(fn func(ctx context.Context) error) Option {
+	return optionFunc(func(o *Options) { o.Hooks.PreStop = append(o.Hooks.PreStop, fn) })
+}
+
 // Logging options
 
 // WithTestcontainersLogging controls whether testcontainers lifecycle logging is enabled.
@@ -390,11 +1013,55 @@ func SuppressTestcontainersLogging() Option {
 	return WithTestcontainersLogging(false)
 }
 
+// ConfigFileEnv is the environment variable of the path of a configuration
+// file read by LoadConfigFromEnv.
+const ConfigFileEnv = "K3SENV_CONFIG_FILE"
+
 // LoadConfigFromEnv loads configuration from environment variables with K3SENV_ prefix
 // and returns an Options struct that can be used with New().
+//
+// If ConfigFileEnv is set, the configuration file it points to is read first,
+// as with LoadConfigFromFile.
 func LoadConfigFromEnv() (*Options, error) {
+	return loadConfig(os.Getenv(ConfigFileEnv))
+}
+
+// LoadConfigFromFile loads configuration from a YAML, JSON or TOML file,
+// detected from its extension, whose keys mirror the environment variables:
+//
+//	k3s:
+//	  image: rancher/k3s:v1.32.9-k3s1
+//	  args:
+//	    - --disable=traefik
+//	webhook:
+//	  port: 9443
+//	certificate:
+//	  sans: [localhost, host.containers.internal]
+//
+// Maps, such as k3s.registry_mirrors, are given as the "key=value" strings of
+// environment variables, as viper splits map keys containing dots. Environment
+// variables with K3SENV_ prefix take precedence over the file.
+func LoadConfigFromFile(path string) (*Options, error) {
+	if path == "" {
+		return nil, errors.New("config file path cannot be empty")
+	}
+
+	return loadConfig(path)
+}
+
+// loadConfig loads configuration from environment variables and, if path is
+// not empty, from the given configuration file.
+func loadConfig(path string) (*Options, error) {
 	v := viper.New()
 
+	if path != "" {
+		v.SetConfigFile(path)
+
+		if err := v.ReadInConfig(); err != nil {
+			return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+		}
+	}
+
 	// Set environment variable prefix
 	v.SetEnvPrefix("K3SENV")
 	v.AutomaticEnv()
@@ -409,23 +1076,48 @@ func LoadConfigFromEnv() (*Options, error) {
 	v.SetDefault("webhook.ready_timeout", WebhookReadyTimeout)
 	v.SetDefault("webhook.health_check_timeout", WebhookHealthCheckTimeout)
 	v.SetDefault("webhook.poll_interval", DefaultWebhookPollInterval)
+	v.SetDefault("webhook.tls.min_version", DefaultWebhookTLSMinVersion)
+	v.SetDefault("webhook.tls.cipher_suites", []uint16{})
 	v.SetDefault("crd.ready_timeout", CRDReadyTimeout)
 	v.SetDefault("crd.poll_interval", DefaultCRDPollInterval)
 	v.SetDefault("k3s.image", DefaultK3sImage)
 	v.SetDefault("k3s.args", []string{})
 	v.SetDefault("k3s.log_redirection", DefaultK3sLogRedirection)
+	v.SetDefault("k3s.startup_retries", 0)
+	v.SetDefault("k3s.start_timeout", DefaultK3sStartTimeout)
+	v.SetDefault("k3s.pull_policy", string(K3sPullIfNotPresent))
 	v.SetDefault("k3s.network.name", "")
 	v.SetDefault("k3s.network.aliases", []string{})
 	v.SetDefault("k3s.network.mode", "")
+	// maps are set from strings, as empty map defaults are not bound to any
+	// environment variable
+	v.SetDefault("k3s.registry_mirrors", "")
+	v.SetDefault("k3s.registry_creds", "")
+	v.SetDefault("k3s.container_labels", "")
+	v.SetDefault("k3s.container_env", "")
+	v.SetDefault("k3s.audit_policy", "")
+	v.SetDefault("cluster.kubeconfig", "")
 	v.SetDefault("certificate.path", "")
 	v.SetDefault("certificate.validity", DefaultCertValidity)
+	v.SetDefault("certificate.sans", slices.Clone(CertificateSANs))
+	v.SetDefault("certificate.key_algorithm", string(cert.DefaultKeyAlgorithm))
+	v.SetDefault("certificate.cert_file", "")
+	v.SetDefault("certificate.key_file", "")
+	v.SetDefault("certificate.ca_file", "")
 	v.SetDefault("manifest.paths", []string{})
 	v.SetDefault("logging.enabled", true)
 
 	var opts Options
 
-	if err := v.Unmarshal(&opts); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal config from environment: %w", err)
+	// the default viper hooks, plus the maps one
+	decodeHook := viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToWeakSliceHookFunc(","),
+		stringToMapHookFunc(),
+	))
+
+	if err := v.Unmarshal(&opts, decodeHook); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 
 	// Set pointer defaults if not set by environment variables
@@ -445,6 +1137,65 @@ func LoadConfigFromEnv() (*Options, error) {
 	return &opts, nil
 }
 
+// stringToMapHookFunc decodes the maps of the k3s configuration, such as the
+// registry mirrors and credentials, from the comma separated "key=value" pairs
+// of environment variables.
+func stringToMapHookFunc() mapstructure.DecodeHookFuncType {
+	return func(from reflect.Type, to reflect.Type, data any) (any, error) {
+		if from.Kind() != reflect.String {
+			return data, nil
+		}
+
+		// unset, as defaulted in LoadConfigFromEnv
+		if data.(string) == "" && to.Kind() == reflect.Map {
+			return reflect.Zero(to).Interface(), nil
+		}
+
+		switch to {
+		case reflect.TypeFor[map[string]string]():
+			return parseKeyValuePairs(data.(string))
+		case reflect.TypeFor[map[string]RegistryCredentials]():
+			pairs, err := parseKeyValuePairs(data.(string))
+			if err != nil {
+				return nil, err
+			}
+
+			creds := make(map[string]RegistryCredentials, len(pairs))
+			for registry, value := range pairs {
+				username, password, ok := strings.Cut(value, ":")
+				if !ok {
+					return nil, fmt.Errorf("invalid credentials for registry %s: expected username:password", registry)
+				}
+				creds[registry] = RegistryCredentials{Username: username, Password: password}
+			}
+
+			return creds, nil
+		default:
+			return data, nil
+		}
+	}
+}
+
+// parseKeyValuePairs parses comma separated "key=value" pairs.
+func parseKeyValuePairs(s string) (map[string]string, error) {
+	pairs := make(map[string]string)
+
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid entry %q: expected key=value", pair)
+		}
+		pairs[key] = value
+	}
+
+	return pairs, nil
+}
+
 // validate checks that all configuration values are valid.
 // Returns an error if any configuration is invalid or out of acceptable range.
 func (opts *Options) validate() error {
@@ -461,6 +1212,26 @@ func (opts *Options) validate() error {
 		return errors.New("k3s image cannot be empty")
 	}
 
+	// K3s startup settings
+	if opts.K3s.StartupRetries < 0 {
+		return fmt.Errorf("k3s startup retries cannot be negative, got %d", opts.K3s.StartupRetries)
+	}
+	if opts.K3s.StartTimeout <= 0 {
+		return fmt.Errorf("k3s start timeout must be positive, got %v", opts.K3s.StartTimeout)
+	}
+
+	// K3s pull policy must be known
+	if !slices.Contains(k3sPullPolicies, opts.K3s.PullPolicy) {
+		return fmt.Errorf("unknown k3s pull policy %q, must be one of %v", opts.K3s.PullPolicy, k3sPullPolicies)
+	}
+
+	// Disabled k3s components must be known
+	for _, arg := range opts.K3s.Args {
+		if component, ok := strings.CutPrefix(arg, "--disable="); ok && !slices.Contains(k3sComponents, component) {
+			return fmt.Errorf("unknown k3s component %q, must be one of %v", component, k3sComponents)
+		}
+	}
+
 	// Webhook timeouts must be positive
 	if opts.Webhook.ReadyTimeout <= 0 {
 		return fmt.Errorf("webhook ready timeout must be positive, got %v", opts.Webhook.ReadyTimeout)
@@ -469,6 +1240,16 @@ func (opts *Options) validate() error {
 		return fmt.Errorf("webhook health check timeout must be positive, got %v", opts.Webhook.HealthCheckTimeout)
 	}
 
+	// Webhook TLS settings must be known crypto/tls constants
+	if !slices.Contains(tlsVersions, opts.Webhook.TLS.MinVersion) {
+		return fmt.Errorf("unsupported webhook TLS min version 0x%04x", opts.Webhook.TLS.MinVersion)
+	}
+	for _, suite := range opts.Webhook.TLS.CipherSuites {
+		if !isKnownCipherSuite(suite) {
+			return fmt.Errorf("unsupported webhook TLS cipher suite 0x%04x", suite)
+		}
+	}
+
 	// CRD timeout must be positive
 	if opts.CRD.ReadyTimeout <= 0 {
 		return fmt.Errorf("CRD ready timeout must be positive, got %v", opts.CRD.ReadyTimeout)
@@ -494,6 +1275,45 @@ func (opts *Options) validate() error {
 		return fmt.Errorf("certificate validity must be positive, got %v", opts.Certificate.Validity)
 	}
 
+	// Certificate SANs must be valid hostnames (wildcards allowed) or IP addresses
+	if len(opts.Certificate.SANs) == 0 {
+		return errors.New("certificate SANs cannot be empty")
+	}
+	for _, san := range opts.Certificate.SANs {
+		if !isValidSAN(san) {
+			return fmt.Errorf("invalid certificate SAN %q: must be a hostname or IP address", san)
+		}
+	}
+
+	if err := opts.Certificate.KeyAlgorithm.Validate(); err != nil {
+		return fmt.Errorf("invalid certificate key algorithm: %w", err)
+	}
+
+	// Custom CA must be a CA certificate matching its private key
+	if len(opts.Certificate.CACert) > 0 || len(opts.Certificate.CAKey) > 0 {
+		if _, _, err := cert.ParseCA(opts.Certificate.CACert, opts.Certificate.CAKey); err != nil {
+			return fmt.Errorf("invalid custom CA: %w", err)
+		}
+	}
+
+	// Certificate files must all be provided and readable
+	if opts.Certificate.hasFiles() {
+		if len(opts.Certificate.CACert) > 0 || len(opts.Certificate.CAKey) > 0 {
+			return errors.New("custom CA and certificate files are mutually exclusive")
+		}
+
+		files := map[string]string{
+			"certificate": opts.Certificate.CertFile,
+			"key":         opts.Certificate.KeyFile,
+			"CA":          opts.Certificate.CAFile,
+		}
+		for name, file := range files {
+			if err := checkReadable(opts.Certificate.resolveFile(file)); err != nil {
+				return fmt.Errorf("invalid %s file: %w", name, err)
+			}
+		}
+	}
+
 	// Validate network configuration
 	if opts.K3s.Network != nil {
 		// Network mode validation (must be one of: bridge, host, none, or container:<name>)
@@ -512,3 +1332,63 @@ func (opts *Options) validate() error {
 
 	return nil
 }
+
+// k3sPullPolicies lists the supported k3s image pull policies.
+var k3sPullPolicies = []K3sPullPolicy{K3sPullIfNotPresent, K3sPullAlways, K3sPullNever}
+
+// k3sComponents lists the components bundled with k3s that can be disabled.
+var k3sComponents = []string{"traefik", "servicelb", "metrics-server", "local-storage", "coredns"}
+
+// tlsVersions lists the TLS versions accepted as webhook TLS min version.
+var tlsVersions = []uint16{tls.VersionTLS10, tls.VersionTLS11, tls.VersionTLS12, tls.VersionTLS13}
+
+// isKnownCipherSuite reports whether id is a cipher suite implemented by crypto/tls.
+func isKnownCipherSuite(id uint16) bool {
+	for _, suites := range [][]*tls.CipherSuite{tls.CipherSuites(), tls.InsecureCipherSuites()} {
+		for _, suite := range suites {
+			if suite.ID == id {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// checkReadable returns an error if the file is not set, does not exist or cannot be read.
+func checkReadable(file string) error {
+	if file == "" {
+		return errors.New("path cannot be empty")
+	}
+
+	// path is provided by the user on purpose
+	//nolint:gosec
+	f, err := os.Open(file)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", file, err)
+	}
+
+	return f.Close()
+}
+
+// sanLabelPattern matches a single DNS label or a wildcard label.
+var sanLabelPattern = regexp.MustCompile(`^(\*|[a-zA-Z0-9]([-a-zA-Z0-9]*[a-zA-Z0-9])?)$`)
+
+// isValidSAN reports whether san is an IP address or a (possibly wildcard) DNS name.
+func isValidSAN(san string) bool {
+	if net.ParseIP(san) != nil {
+		return true
+	}
+
+	if san == "" || len(san) > 253 {
+		return false
+	}
+
+	for label := range strings.SplitSeq(san, ".") {
+		if len(label) > 63 || !sanLabelPattern.MatchString(label) {
+			return false
+		}
+	}
+
+	return true
+}
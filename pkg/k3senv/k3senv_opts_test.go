@@ -1,12 +1,25 @@
 package k3senv_test
 
 import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/lburgazzoli/k3s-envtest/internal/cert"
 	"github.com/lburgazzoli/k3s-envtest/pkg/k3senv"
+	"github.com/mdelapenya/tlscert"
+	ctrlwebhook "sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	"k8s.io/apimachinery/pkg/runtime"
 
@@ -118,6 +131,39 @@ func TestK3sArgs_WithK3sArgs(t *testing.T) {
 	g.Expect(env).NotTo(BeNil())
 }
 
+func TestK3sArgs_WithK3sDisableComponents(t *testing.T) {
+	t.Run("Components are appended as disable arguments", func(t *testing.T) {
+		g := NewWithT(t)
+
+		env, err := k3senv.New(
+			k3senv.WithK3sArgs("--disable-network-policy"),
+			k3senv.WithK3sDisableComponents("traefik", "servicelb"),
+			k3senv.WithK3sDisableComponents("metrics-server"),
+			k3senv.WithCertPath(testCertPath),
+		)
+
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(env.Options().K3s.Args).To(Equal([]string{
+			"--disable-network-policy",
+			"--disable=traefik",
+			"--disable=servicelb",
+			"--disable=metrics-server",
+		}))
+	})
+
+	t.Run("Unknown components return an error", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := k3senv.New(
+			k3senv.WithK3sDisableComponents("local-storage", "dashboard"),
+			k3senv.WithCertPath(testCertPath),
+		)
+
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring(`unknown k3s component "dashboard"`))
+	})
+}
+
 func TestLogger_WithLogger(t *testing.T) {
 	g := NewWithT(t)
 	var logMessages []string
@@ -159,8 +205,8 @@ func TestNew_EnvironmentVariablePrecedence(t *testing.T) {
 
 	// Explicit option should override environment variable
 	g.Expect(env).NotTo(BeNil())
-	// We can't easily access internal options without exposing them,
-	// but we can verify the environment was created successfully with mixed config
+	g.Expect(env.WebhookPort()).To(Equal(9999))
+	g.Expect(env.Options().K3s.Image).To(Equal("rancher/k3s:env-test"))
 }
 
 func TestNew_EnvironmentVariablesOnly(t *testing.T) {
@@ -183,6 +229,41 @@ func TestNew_EnvironmentVariablesOnly(t *testing.T) {
 	g.Expect(env.CertPath()).To(Equal(testCertPath))
 }
 
+func TestK3sEnv_Options(t *testing.T) {
+	g := NewWithT(t)
+
+	t.Setenv("K3SENV_WEBHOOK_PORT", "8080")
+
+	env, err := k3senv.New(
+		k3senv.WithCertPath(testCertPath),
+		k3senv.WithCertSANs("localhost"),
+		k3senv.WithK3sArgs("--disable=traefik"),
+		k3senv.WithK3sNetwork("test-network"),
+		k3senv.WithAutoInstallWebhooks(true),
+	)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	opts := env.Options()
+	g.Expect(opts.Webhook.Port).To(Equal(8080))
+	g.Expect(env.WebhookPort()).To(Equal(8080))
+	g.Expect(opts.Certificate.Path).To(Equal(testCertPath))
+	g.Expect(opts.CRD.PollInterval).To(Equal(k3senv.DefaultCRDPollInterval))
+
+	// Mutating the returned options does not affect the environment
+	opts.Webhook.Port = 1234
+	opts.Certificate.SANs[0] = "mutated"
+	opts.K3s.Args[0] = "--mutated"
+	opts.K3s.Network.Name = "mutated"
+	*opts.Webhook.AutoInstall = false
+
+	current := env.Options()
+	g.Expect(current.Webhook.Port).To(Equal(8080))
+	g.Expect(current.Certificate.SANs).To(Equal([]string{"localhost"}))
+	g.Expect(current.K3s.Args).To(Equal([]string{"--disable=traefik"}))
+	g.Expect(current.K3s.Network.Name).To(Equal("test-network"))
+	g.Expect(*current.Webhook.AutoInstall).To(BeTrue())
+}
+
 func TestPollIntervals_ComponentSpecific(t *testing.T) {
 	g := NewWithT(t)
 
@@ -455,6 +536,243 @@ func TestNetworkConfig(t *testing.T) {
 	})
 }
 
+func TestCertificateSANs(t *testing.T) {
+	t.Run("Defaults to CertificateSANs", func(t *testing.T) {
+		g := NewWithT(t)
+
+		opts, err := k3senv.LoadConfigFromEnv()
+
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(opts.Certificate.SANs).To(Equal(k3senv.CertificateSANs))
+	})
+
+	t.Run("WithCertSANs replaces the default list", func(t *testing.T) {
+		g := NewWithT(t)
+
+		opts, err := k3senv.LoadConfigFromEnv()
+		g.Expect(err).NotTo(HaveOccurred())
+
+		opts.ApplyOptions([]k3senv.Option{
+			k3senv.WithCertSANs("webhook.example.com", "10.0.0.1"),
+		})
+
+		g.Expect(opts.Certificate.SANs).To(ConsistOf("webhook.example.com", "10.0.0.1"))
+	})
+
+	t.Run("WithAdditionalCertSANs appends to the default list", func(t *testing.T) {
+		g := NewWithT(t)
+
+		opts := &k3senv.Options{}
+		k3senv.WithAdditionalCertSANs("webhook.example.com").ApplyToOptions(opts)
+
+		g.Expect(opts.Certificate.SANs).To(HaveLen(len(k3senv.CertificateSANs) + 1))
+		g.Expect(opts.Certificate.SANs).To(ContainElements(k3senv.CertificateSANs))
+		g.Expect(opts.Certificate.SANs).To(ContainElement("webhook.example.com"))
+	})
+
+	t.Run("WithAdditionalCertSANs does not modify CertificateSANs", func(t *testing.T) {
+		g := NewWithT(t)
+		defaults := slices.Clone(k3senv.CertificateSANs)
+
+		opts := &k3senv.Options{}
+		k3senv.WithAdditionalCertSANs("webhook.example.com").ApplyToOptions(opts)
+
+		g.Expect(k3senv.CertificateSANs).To(Equal(defaults))
+	})
+
+	t.Run("Valid SANs pass validation", func(t *testing.T) {
+		g := NewWithT(t)
+
+		env, err := k3senv.New(
+			k3senv.WithCertSANs("localhost", "*.example.com", "192.168.1.10", "::1"),
+		)
+
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(env).NotTo(BeNil())
+	})
+
+	t.Run("Malformed SAN returns validation error", func(t *testing.T) {
+		g := NewWithT(t)
+
+		for _, san := range []string{"", "host name", "bad..host", "-leading.dash", "a,b"} {
+			_, err := k3senv.New(k3senv.WithCertSANs(san))
+
+			g.Expect(err).To(HaveOccurred(), "expected %q to be rejected", san)
+			g.Expect(err.Error()).To(ContainSubstring("invalid certificate SAN"))
+		}
+	})
+
+	t.Run("Empty SAN list returns validation error", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := k3senv.New(k3senv.WithCertSANs())
+
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("certificate SANs cannot be empty"))
+	})
+}
+
+func TestCustomCA(t *testing.T) {
+	t.Run("WithCustomCA stores the CA", func(t *testing.T) {
+		g := NewWithT(t)
+
+		ca := tlscert.SelfSignedCA("test-ca")
+		g.Expect(ca).NotTo(BeNil())
+
+		env, err := k3senv.New(k3senv.WithCustomCA(ca.Bytes, ca.KeyBytes))
+
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(env).NotTo(BeNil())
+	})
+
+	t.Run("Non-CA certificate returns validation error", func(t *testing.T) {
+		g := NewWithT(t)
+
+		leaf := tlscert.SelfSigned("localhost")
+		g.Expect(leaf).NotTo(BeNil())
+
+		_, err := k3senv.New(k3senv.WithCustomCA(leaf.Bytes, leaf.KeyBytes))
+
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("is not a CA"))
+	})
+
+	t.Run("Mismatched key returns validation error", func(t *testing.T) {
+		g := NewWithT(t)
+
+		ca := tlscert.SelfSignedCA("test-ca")
+		other := tlscert.SelfSignedCA("other-ca")
+		g.Expect(ca).NotTo(BeNil())
+		g.Expect(other).NotTo(BeNil())
+
+		_, err := k3senv.New(k3senv.WithCustomCA(ca.Bytes, other.KeyBytes))
+
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("invalid custom CA"))
+	})
+}
+
+func TestCertificateKeyAlgorithm(t *testing.T) {
+	t.Run("Defaults to RSA2048", func(t *testing.T) {
+		g := NewWithT(t)
+
+		opts, err := k3senv.LoadConfigFromEnv()
+
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(opts.Certificate.KeyAlgorithm).To(Equal(cert.RSA2048))
+	})
+
+	t.Run("WithCertKeyAlgorithm sets the algorithm", func(t *testing.T) {
+		g := NewWithT(t)
+
+		opts := &k3senv.Options{}
+		k3senv.WithCertKeyAlgorithm(cert.Ed25519).ApplyToOptions(opts)
+
+		g.Expect(opts.Certificate.KeyAlgorithm).To(Equal(cert.Ed25519))
+	})
+
+	t.Run("Environment variable sets the algorithm", func(t *testing.T) {
+		g := NewWithT(t)
+		t.Setenv("K3SENV_CERTIFICATE_KEY_ALGORITHM", "ECDSA256")
+
+		opts, err := k3senv.LoadConfigFromEnv()
+
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(opts.Certificate.KeyAlgorithm).To(Equal(cert.ECDSA256))
+	})
+
+	t.Run("Unsupported algorithm returns validation error", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := k3senv.New(k3senv.WithCertKeyAlgorithm("DSA1024"))
+
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("invalid certificate key algorithm"))
+	})
+}
+
+func TestCertFiles(t *testing.T) {
+	newCertDir := func(t *testing.T) string {
+		t.Helper()
+
+		dir := t.TempDir()
+		_, err := cert.New(dir, time.Hour, []string{"localhost"})
+		NewWithT(t).Expect(err).NotTo(HaveOccurred())
+
+		return dir
+	}
+
+	t.Run("Absolute paths pass validation", func(t *testing.T) {
+		g := NewWithT(t)
+		dir := newCertDir(t)
+
+		env, err := k3senv.New(k3senv.WithCertFiles(
+			filepath.Join(dir, cert.CertFileName),
+			filepath.Join(dir, cert.KeyFileName),
+			filepath.Join(dir, cert.CACertFileName),
+		))
+
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(env).NotTo(BeNil())
+	})
+
+	t.Run("Relative paths are resolved against the certificate path", func(t *testing.T) {
+		g := NewWithT(t)
+		dir := newCertDir(t)
+
+		env, err := k3senv.New(
+			k3senv.WithCertPath(dir),
+			k3senv.WithCertFiles(cert.CertFileName, cert.KeyFileName, cert.CACertFileName),
+		)
+
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(env).NotTo(BeNil())
+	})
+
+	t.Run("Missing file returns validation error", func(t *testing.T) {
+		g := NewWithT(t)
+		dir := newCertDir(t)
+
+		_, err := k3senv.New(k3senv.WithCertFiles(
+			filepath.Join(dir, "missing.pem"),
+			filepath.Join(dir, cert.KeyFileName),
+			filepath.Join(dir, cert.CACertFileName),
+		))
+
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("invalid certificate file"))
+		g.Expect(err.Error()).To(ContainSubstring("missing.pem"))
+	})
+
+	t.Run("Rotation is rejected", func(t *testing.T) {
+		g := NewWithT(t)
+		dir := newCertDir(t)
+
+		env, err := k3senv.New(
+			k3senv.WithCertPath(dir),
+			k3senv.WithCertFiles(cert.CertFileName, cert.KeyFileName, cert.CACertFileName),
+		)
+		g.Expect(err).NotTo(HaveOccurred())
+
+		err = env.RotateCertificates(t.Context())
+		g.Expect(err).To(MatchError(ContainSubstring("cannot rotate certificates loaded from files")))
+	})
+
+	t.Run("Empty path returns validation error", func(t *testing.T) {
+		g := NewWithT(t)
+		dir := newCertDir(t)
+
+		_, err := k3senv.New(k3senv.WithCertFiles(
+			filepath.Join(dir, cert.CertFileName),
+			filepath.Join(dir, cert.KeyFileName),
+			"",
+		))
+
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("invalid CA file"))
+	})
+}
+
 // mockLogger implements the Logger interface for testing.
 type mockLogger struct {
 	messages *[]string
@@ -463,3 +781,802 @@ type mockLogger struct {
 func (m *mockLogger) Logf(format string, args ...any) {
 	*m.messages = append(*m.messages, fmt.Sprintf(format, args...))
 }
+
+func TestWebhookTLS(t *testing.T) {
+	t.Run("Defaults to TLS 1.2 and default cipher suites", func(t *testing.T) {
+		g := NewWithT(t)
+
+		opts, err := k3senv.LoadConfigFromEnv()
+
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(opts.Webhook.TLS.MinVersion).To(Equal(uint16(tls.VersionTLS12)))
+		g.Expect(opts.Webhook.TLS.CipherSuites).To(BeEmpty())
+	})
+
+	t.Run("Options set min version and cipher suites", func(t *testing.T) {
+		g := NewWithT(t)
+
+		suites := []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256}
+
+		opts := &k3senv.Options{}
+		opts.ApplyOptions([]k3senv.Option{
+			k3senv.WithWebhookTLSMinVersion(tls.VersionTLS13),
+			k3senv.WithWebhookTLSCipherSuites(suites),
+		})
+
+		g.Expect(opts.Webhook.TLS.MinVersion).To(Equal(uint16(tls.VersionTLS13)))
+		g.Expect(opts.Webhook.TLS.CipherSuites).To(Equal(suites))
+	})
+
+	t.Run("Environment variables set min version and cipher suites", func(t *testing.T) {
+		g := NewWithT(t)
+		t.Setenv("K3SENV_WEBHOOK_TLS_MIN_VERSION", "0x0304")
+		t.Setenv("K3SENV_WEBHOOK_TLS_CIPHER_SUITES", "0xc02f,49200")
+
+		opts, err := k3senv.LoadConfigFromEnv()
+
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(opts.Webhook.TLS.MinVersion).To(Equal(uint16(tls.VersionTLS13)))
+		g.Expect(opts.Webhook.TLS.CipherSuites).To(Equal([]uint16{
+			tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+		}))
+	})
+
+	t.Run("Unsupported min version returns validation error", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := k3senv.New(k3senv.WithWebhookTLSMinVersion(0x0200))
+
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("unsupported webhook TLS min version"))
+	})
+
+	t.Run("Unsupported cipher suite returns validation error", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := k3senv.New(k3senv.WithWebhookTLSCipherSuites([]uint16{0xffff}))
+
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("unsupported webhook TLS cipher suite"))
+	})
+}
+
+func TestWebhookHostResolver(t *testing.T) {
+	t.Run("Defaults to host.containers.internal", func(t *testing.T) {
+		g := NewWithT(t)
+
+		env, err := k3senv.New(k3senv.WithWebhookPort(9443))
+		g.Expect(err).NotTo(HaveOccurred())
+
+		host, err := env.WebhookHost(t.Context())
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(host).To(Equal("host.containers.internal:9443"))
+	})
+
+	t.Run("Custom resolver result is cached", func(t *testing.T) {
+		g := NewWithT(t)
+
+		calls := 0
+		env, err := k3senv.New(
+			k3senv.WithWebhookPort(9443),
+			k3senv.WithWebhookHostResolver(func(context.Context) (string, error) {
+				calls++
+				return "172.17.0.1", nil
+			}),
+		)
+		g.Expect(err).NotTo(HaveOccurred())
+
+		for range 3 {
+			host, err := env.WebhookHost(t.Context())
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(host).To(Equal("172.17.0.1:9443"))
+		}
+
+		g.Expect(calls).To(Equal(1))
+	})
+
+	t.Run("Failed resolution is not cached", func(t *testing.T) {
+		g := NewWithT(t)
+
+		calls := 0
+		env, err := k3senv.New(
+			k3senv.WithWebhookHostResolver(func(context.Context) (string, error) {
+				calls++
+				if calls == 1 {
+					return "", errors.New("resolver failure")
+				}
+				return "host.docker.internal", nil
+			}),
+		)
+		g.Expect(err).NotTo(HaveOccurred())
+
+		_, err = env.WebhookHost(t.Context())
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("resolver failure"))
+
+		host, err := env.WebhookHost(t.Context())
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(host).To(HavePrefix("host.docker.internal:"))
+		g.Expect(calls).To(Equal(2))
+	})
+
+	t.Run("Empty host returns an error", func(t *testing.T) {
+		g := NewWithT(t)
+
+		env, err := k3senv.New(
+			k3senv.WithWebhookHostResolver(func(context.Context) (string, error) {
+				return "", nil
+			}),
+		)
+		g.Expect(err).NotTo(HaveOccurred())
+
+		_, err = env.WebhookHost(t.Context())
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("empty host"))
+	})
+}
+
+func TestLifecycleHooks(t *testing.T) {
+	t.Run("PreStart error aborts Start", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var calls []string
+		env, err := k3senv.New(
+			k3senv.WithPreStart(func(context.Context) error {
+				calls = append(calls, "first")
+				return errors.New("pre-start failure")
+			}),
+			k3senv.WithPreStart(func(context.Context) error {
+				calls = append(calls, "second")
+				return nil
+			}),
+		)
+		g.Expect(err).NotTo(HaveOccurred())
+
+		err = env.Start(t.Context())
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("pre-start failure"))
+		g.Expect(calls).To(Equal([]string{"first"}))
+		g.Expect(env.IsStarted()).To(BeFalse())
+		g.Expect(env.ContainerID()).To(BeEmpty())
+	})
+
+	t.Run("PreStop error aborts Stop", func(t *testing.T) {
+		g := NewWithT(t)
+
+		teardown := false
+		env, err := k3senv.New(
+			k3senv.WithPreStop(func(context.Context) error {
+				return errors.New("pre-stop failure")
+			}),
+		)
+		g.Expect(err).NotTo(HaveOccurred())
+
+		env.AddTeardown(func(context.Context) error {
+			teardown = true
+			return nil
+		})
+
+		err = env.Stop(t.Context())
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("pre-stop failure"))
+		g.Expect(teardown).To(BeFalse())
+		g.Expect(env.IsStopped()).To(BeFalse())
+	})
+
+	t.Run("Hooks are accumulated", func(t *testing.T) {
+		g := NewWithT(t)
+
+		env, err := k3senv.New(
+			&k3senv.Options{
+				Hooks: k3senv.HooksConfig{
+					PreStop: []k3senv.Hook{func(context.Context) error { return nil }},
+				},
+			},
+			k3senv.WithPreStop(func(context.Context) error { return nil }),
+			k3senv.WithPostStart(func(context.Context, *k3senv.K3sEnv) error { return nil }),
+		)
+		g.Expect(err).NotTo(HaveOccurred())
+
+		g.Expect(env.Options().Hooks.PreStart).To(BeEmpty())
+		g.Expect(env.Options().Hooks.PostStart).To(HaveLen(1))
+		g.Expect(env.Options().Hooks.PreStop).To(HaveLen(2))
+	})
+}
+
+type fakeTestingT struct {
+	logs     []string
+	cleanups []func()
+}
+
+func (f *fakeTestingT) Logf(format string, args ...any) {
+	f.logs = append(f.logs, fmt.Sprintf(format, args...))
+}
+
+func (f *fakeTestingT) Helper() {}
+
+func (f *fakeTestingT) Cleanup(fn func()) {
+	f.cleanups = append(f.cleanups, fn)
+}
+
+func (f *fakeTestingT) Fatalf(format string, args ...any) {
+	panic(fmt.Sprintf(format, args...))
+}
+
+func TestWithTestingT(t *testing.T) {
+	g := NewWithT(t)
+
+	ft := &fakeTestingT{}
+	env, err := k3senv.New(
+		k3senv.WithTestingT(ft),
+		k3senv.WithPreStart(func(context.Context) error {
+			return errors.New("pre-start failure")
+		}),
+	)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(env.Options().Logger).To(BeIdenticalTo(ft))
+	g.Expect(ft.cleanups).To(BeEmpty())
+
+	// The cleanup is registered by Start, once
+	for range 2 {
+		err = env.Start(t.Context())
+		g.Expect(err).To(HaveOccurred())
+	}
+
+	g.Expect(ft.cleanups).To(HaveLen(1))
+
+	ft.cleanups[0]()
+	g.Expect(env.IsStopped()).To(BeTrue())
+	g.Expect(ft.logs).To(ContainElement(ContainSubstring("Stopping k3s environment")))
+}
+
+func TestWithTestingT_PreStopFailure(t *testing.T) {
+	g := NewWithT(t)
+
+	ft := &fakeTestingT{}
+	env, err := k3senv.New(
+		k3senv.WithTestingT(ft),
+		k3senv.WithPreStart(func(context.Context) error {
+			return errors.New("pre-start failure")
+		}),
+		k3senv.WithPreStop(func(context.Context) error {
+			return errors.New("pre-stop failure")
+		}),
+	)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	teardown := false
+	env.AddTeardown(func(context.Context) error {
+		teardown = true
+		return nil
+	})
+
+	g.Expect(env.Start(t.Context())).NotTo(Succeed())
+	g.Expect(ft.cleanups).To(HaveLen(1))
+
+	// the cleanup stops the environment despite the failing hook
+	ft.cleanups[0]()
+	g.Expect(teardown).To(BeTrue())
+	g.Expect(env.IsStopped()).To(BeTrue())
+	g.Expect(ft.logs).To(ContainElement(ContainSubstring("pre-stop failure")))
+}
+
+func TestContainerStartup(t *testing.T) {
+	t.Run("Defaults to no retries and the default timeout", func(t *testing.T) {
+		g := NewWithT(t)
+
+		opts, err := k3senv.LoadConfigFromEnv()
+
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(opts.K3s.StartupRetries).To(Equal(0))
+		g.Expect(opts.K3s.StartTimeout).To(Equal(k3senv.DefaultK3sStartTimeout))
+	})
+
+	t.Run("Options set retries and timeout", func(t *testing.T) {
+		g := NewWithT(t)
+
+		opts := &k3senv.Options{}
+		k3senv.WithStartupRetries(3).ApplyToOptions(opts)
+		k3senv.WithContainerStartTimeout(2 * time.Minute).ApplyToOptions(opts)
+
+		g.Expect(opts.K3s.StartupRetries).To(Equal(3))
+		g.Expect(opts.K3s.StartTimeout).To(Equal(2 * time.Minute))
+	})
+
+	t.Run("Environment variables set retries and timeout", func(t *testing.T) {
+		g := NewWithT(t)
+		t.Setenv("K3SENV_K3S_STARTUP_RETRIES", "2")
+		t.Setenv("K3SENV_K3S_START_TIMEOUT", "90s")
+
+		opts, err := k3senv.LoadConfigFromEnv()
+
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(opts.K3s.StartupRetries).To(Equal(2))
+		g.Expect(opts.K3s.StartTimeout).To(Equal(90 * time.Second))
+	})
+
+	t.Run("Negative retries return validation error", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := k3senv.New(k3senv.WithCertPath(testCertPath), k3senv.WithStartupRetries(-1))
+
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("k3s startup retries cannot be negative"))
+	})
+
+	t.Run("Negative timeout returns validation error", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := k3senv.New(k3senv.WithCertPath(testCertPath), k3senv.WithContainerStartTimeout(-time.Second))
+
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("k3s start timeout must be positive"))
+	})
+}
+
+func TestPreExistingCluster(t *testing.T) {
+	t.Run("Defaults to a k3s container", func(t *testing.T) {
+		g := NewWithT(t)
+
+		opts, err := k3senv.LoadConfigFromEnv()
+
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(opts.Cluster.Kubeconfig).To(BeEmpty())
+	})
+
+	t.Run("WithPreExistingCluster sets the kubeconfig path", func(t *testing.T) {
+		g := NewWithT(t)
+
+		opts := &k3senv.Options{}
+		k3senv.WithPreExistingCluster("/tmp/kubeconfig").ApplyToOptions(opts)
+
+		g.Expect(opts.Cluster.Kubeconfig).To(Equal("/tmp/kubeconfig"))
+	})
+
+	t.Run("WithPreExistingClusterFromEnv reads KUBECONFIG", func(t *testing.T) {
+		g := NewWithT(t)
+		t.Setenv("KUBECONFIG", strings.Join([]string{"/tmp/first", "/tmp/second"}, string(filepath.ListSeparator)))
+
+		opts := &k3senv.Options{}
+		k3senv.WithPreExistingClusterFromEnv().ApplyToOptions(opts)
+
+		g.Expect(opts.Cluster.Kubeconfig).To(Equal("/tmp/first"))
+	})
+
+	t.Run("WithPreExistingClusterFromEnv without KUBECONFIG has no effect", func(t *testing.T) {
+		g := NewWithT(t)
+		t.Setenv("KUBECONFIG", "")
+
+		opts := &k3senv.Options{}
+		k3senv.WithPreExistingClusterFromEnv().ApplyToOptions(opts)
+
+		g.Expect(opts.Cluster.Kubeconfig).To(BeEmpty())
+	})
+
+	t.Run("Environment variable sets the kubeconfig path", func(t *testing.T) {
+		g := NewWithT(t)
+		t.Setenv("K3SENV_CLUSTER_KUBECONFIG", "/tmp/kubeconfig")
+
+		opts, err := k3senv.LoadConfigFromEnv()
+
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(opts.Cluster.Kubeconfig).To(Equal("/tmp/kubeconfig"))
+	})
+
+	t.Run("Missing kubeconfig fails Start", func(t *testing.T) {
+		g := NewWithT(t)
+
+		env, err := k3senv.New(k3senv.WithPreExistingCluster(filepath.Join(t.TempDir(), "missing")))
+		g.Expect(err).NotTo(HaveOccurred())
+
+		err = env.Start(context.Background())
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("failed to read kubeconfig"))
+	})
+}
+
+func TestRegistryMirrors(t *testing.T) {
+	t.Run("Defaults to no mirrors", func(t *testing.T) {
+		g := NewWithT(t)
+
+		opts, err := k3senv.LoadConfigFromEnv()
+
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(opts.K3s.RegistryMirrors).To(BeEmpty())
+		g.Expect(opts.K3s.RegistryCreds).To(BeEmpty())
+	})
+
+	t.Run("Options append mirrors and credentials", func(t *testing.T) {
+		g := NewWithT(t)
+
+		opts := &k3senv.Options{}
+		k3senv.WithK3sRegistryMirror("docker.io", "https://mirror.example.com").ApplyToOptions(opts)
+		k3senv.WithK3sRegistryMirror("quay.io", "https://quay-mirror.example.com").ApplyToOptions(opts)
+		k3senv.WithK3sRegistryCreds("mirror.example.com", "user", "secret").ApplyToOptions(opts)
+
+		g.Expect(opts.K3s.RegistryMirrors).To(Equal(map[string]string{
+			"docker.io": "https://mirror.example.com",
+			"quay.io":   "https://quay-mirror.example.com",
+		}))
+		g.Expect(opts.K3s.RegistryCreds).To(Equal(map[string]k3senv.RegistryCredentials{
+			"mirror.example.com": {Username: "user", Password: "secret"},
+		}))
+	})
+
+	t.Run("Explicit options are merged with environment variables", func(t *testing.T) {
+		g := NewWithT(t)
+		t.Setenv("K3SENV_K3S_REGISTRY_MIRRORS", "docker.io=https://env-mirror.example.com")
+
+		env, err := k3senv.New(
+			k3senv.WithCertPath(testCertPath),
+			k3senv.WithK3sRegistryMirror("quay.io", "https://quay-mirror.example.com"),
+		)
+
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(env.Options().K3s.RegistryMirrors).To(Equal(map[string]string{
+			"docker.io": "https://env-mirror.example.com",
+			"quay.io":   "https://quay-mirror.example.com",
+		}))
+	})
+
+	t.Run("Environment variables set mirrors and credentials", func(t *testing.T) {
+		g := NewWithT(t)
+		t.Setenv("K3SENV_K3S_REGISTRY_MIRRORS", "docker.io=https://mirror.example.com, quay.io=https://quay-mirror.example.com")
+		t.Setenv("K3SENV_K3S_REGISTRY_CREDS", "mirror.example.com=user:se:cret")
+
+		opts, err := k3senv.LoadConfigFromEnv()
+
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(opts.K3s.RegistryMirrors).To(Equal(map[string]string{
+			"docker.io": "https://mirror.example.com",
+			"quay.io":   "https://quay-mirror.example.com",
+		}))
+		g.Expect(opts.K3s.RegistryCreds).To(Equal(map[string]k3senv.RegistryCredentials{
+			"mirror.example.com": {Username: "user", Password: "se:cret"},
+		}))
+	})
+
+	t.Run("Invalid environment variables return an error", func(t *testing.T) {
+		g := NewWithT(t)
+		t.Setenv("K3SENV_K3S_REGISTRY_CREDS", "mirror.example.com=user")
+
+		_, err := k3senv.LoadConfigFromEnv()
+
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("expected username:password"))
+	})
+}
+
+func TestContainerMetadata(t *testing.T) {
+	t.Run("Options merge labels and environment variables", func(t *testing.T) {
+		g := NewWithT(t)
+
+		opts := &k3senv.Options{}
+		k3senv.WithK3sContainerLabels(map[string]string{"ci.job": "1", "team": "a"}).ApplyToOptions(opts)
+		k3senv.WithK3sContainerLabels(map[string]string{"team": "b"}).ApplyToOptions(opts)
+		k3senv.WithK3sContainerEnv(map[string]string{"K3S_NODE_NAME": "test"}).ApplyToOptions(opts)
+		k3senv.WithK3sContainerEnv(map[string]string{"K3S_DEBUG": "true"}).ApplyToOptions(opts)
+
+		g.Expect(opts.K3s.ContainerLabels).To(Equal(map[string]string{"ci.job": "1", "team": "b"}))
+		g.Expect(opts.K3s.ContainerEnv).To(Equal(map[string]string{"K3S_NODE_NAME": "test", "K3S_DEBUG": "true"}))
+	})
+
+	t.Run("Options do not share the given maps", func(t *testing.T) {
+		g := NewWithT(t)
+
+		labels := map[string]string{"team": "a"}
+
+		env, err := k3senv.New(
+			k3senv.WithCertPath(testCertPath),
+			k3senv.WithK3sContainerLabels(labels),
+		)
+		g.Expect(err).NotTo(HaveOccurred())
+
+		labels["team"] = "mutated"
+
+		g.Expect(env.Options().K3s.ContainerLabels).To(Equal(map[string]string{"team": "a"}))
+	})
+
+	t.Run("Environment variables set labels and environment variables", func(t *testing.T) {
+		g := NewWithT(t)
+		t.Setenv("K3SENV_K3S_CONTAINER_LABELS", "ci.job=1,team=a")
+		t.Setenv("K3SENV_K3S_CONTAINER_ENV", "K3S_NODE_NAME=test")
+
+		env, err := k3senv.New(
+			k3senv.WithCertPath(testCertPath),
+			k3senv.WithK3sContainerLabels(map[string]string{"team": "b"}),
+		)
+
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(env.Options().K3s.ContainerLabels).To(Equal(map[string]string{"ci.job": "1", "team": "b"}))
+		g.Expect(env.Options().K3s.ContainerEnv).To(Equal(map[string]string{"K3S_NODE_NAME": "test"}))
+	})
+
+	t.Run("Invalid environment variables return an error", func(t *testing.T) {
+		g := NewWithT(t)
+		t.Setenv("K3SENV_K3S_CONTAINER_ENV", "K3S_NODE_NAME")
+
+		_, err := k3senv.LoadConfigFromEnv()
+
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("expected key=value"))
+	})
+}
+
+func TestPullPolicy(t *testing.T) {
+	t.Run("Defaults to pulling missing images", func(t *testing.T) {
+		g := NewWithT(t)
+
+		opts, err := k3senv.LoadConfigFromEnv()
+
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(opts.K3s.PullPolicy).To(Equal(k3senv.K3sPullIfNotPresent))
+	})
+
+	t.Run("Options set the policy", func(t *testing.T) {
+		g := NewWithT(t)
+
+		opts := &k3senv.Options{}
+		k3senv.WithK3sAlwaysPull().ApplyToOptions(opts)
+		g.Expect(opts.K3s.PullPolicy).To(Equal(k3senv.K3sPullAlways))
+
+		k3senv.WithK3sNeverPull().ApplyToOptions(opts)
+		g.Expect(opts.K3s.PullPolicy).To(Equal(k3senv.K3sPullNever))
+
+		k3senv.WithK3sPullPolicy(k3senv.K3sPullIfNotPresent).ApplyToOptions(opts)
+		g.Expect(opts.K3s.PullPolicy).To(Equal(k3senv.K3sPullIfNotPresent))
+	})
+
+	t.Run("Environment variable sets the policy", func(t *testing.T) {
+		g := NewWithT(t)
+		t.Setenv("K3SENV_K3S_PULL_POLICY", "Never")
+
+		env, err := k3senv.New(k3senv.WithCertPath(testCertPath))
+
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(env.Options().K3s.PullPolicy).To(Equal(k3senv.K3sPullNever))
+	})
+
+	t.Run("Unknown policy returns an error", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := k3senv.New(
+			k3senv.WithCertPath(testCertPath),
+			k3senv.WithK3sPullPolicy("Sometimes"),
+		)
+
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring(`unknown k3s pull policy "Sometimes"`))
+	})
+
+	t.Run("Never pull fails if the image is missing", func(t *testing.T) {
+		g := NewWithT(t)
+
+		env, err := k3senv.New(
+			k3senv.WithCertPath(testCertPath),
+			k3senv.WithK3sImage("rancher/k3s:v0.0.0-k3senv-missing"),
+			k3senv.WithK3sNeverPull(),
+		)
+		g.Expect(err).NotTo(HaveOccurred())
+
+		err = env.Start(context.Background())
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("is not present locally"))
+	})
+}
+
+func TestConfigFile(t *testing.T) {
+	writeConfig := func(t *testing.T, name string, content string) string {
+		t.Helper()
+
+		path := filepath.Join(t.TempDir(), name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		return path
+	}
+
+	yamlConfig := `
+webhook:
+  port: 8443
+k3s:
+  image: rancher/k3s:v1.31.0-k3s1
+  args:
+    - --disable=traefik
+    - --disable=metrics-server
+  start_timeout: 2m
+  registry_mirrors: docker.io=https://mirror.example.com
+certificate:
+  sans:
+    - localhost
+    - k3senv.example.com
+`
+
+	t.Run("YAML file", func(t *testing.T) {
+		g := NewWithT(t)
+
+		opts, err := k3senv.LoadConfigFromFile(writeConfig(t, "k3senv.yaml", yamlConfig))
+
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(opts.Webhook.Port).To(Equal(8443))
+		g.Expect(opts.K3s.Image).To(Equal("rancher/k3s:v1.31.0-k3s1"))
+		g.Expect(opts.K3s.Args).To(Equal([]string{"--disable=traefik", "--disable=metrics-server"}))
+		g.Expect(opts.K3s.StartTimeout).To(Equal(2 * time.Minute))
+		g.Expect(opts.K3s.RegistryMirrors).To(HaveKeyWithValue("docker.io", "https://mirror.example.com"))
+		g.Expect(opts.Certificate.SANs).To(Equal([]string{"localhost", "k3senv.example.com"}))
+
+		// unset values keep their defaults
+		g.Expect(opts.CRD.PollInterval).To(Equal(k3senv.DefaultCRDPollInterval))
+	})
+
+	t.Run("TOML file", func(t *testing.T) {
+		g := NewWithT(t)
+
+		path := writeConfig(t, "k3senv.toml", `
+[webhook]
+port = 8443
+
+[k3s]
+args = ["--disable=traefik"]
+`)
+
+		opts, err := k3senv.LoadConfigFromFile(path)
+
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(opts.Webhook.Port).To(Equal(8443))
+		g.Expect(opts.K3s.Args).To(Equal([]string{"--disable=traefik"}))
+	})
+
+	t.Run("Environment variables take precedence", func(t *testing.T) {
+		g := NewWithT(t)
+		t.Setenv("K3SENV_WEBHOOK_PORT", "9999")
+
+		opts, err := k3senv.LoadConfigFromFile(writeConfig(t, "k3senv.yaml", yamlConfig))
+
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(opts.Webhook.Port).To(Equal(9999))
+		g.Expect(opts.K3s.Image).To(Equal("rancher/k3s:v1.31.0-k3s1"))
+	})
+
+	t.Run("Config file environment variable", func(t *testing.T) {
+		g := NewWithT(t)
+		t.Setenv(k3senv.ConfigFileEnv, writeConfig(t, "k3senv.yaml", yamlConfig))
+
+		env, err := k3senv.New(
+			k3senv.WithCertPath(testCertPath),
+			k3senv.WithWebhookPort(7443),
+		)
+
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(env.Options().Webhook.Port).To(Equal(7443))
+		g.Expect(env.Options().K3s.Image).To(Equal("rancher/k3s:v1.31.0-k3s1"))
+	})
+
+	t.Run("Invalid files return an error", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := k3senv.LoadConfigFromFile("")
+		g.Expect(err).To(MatchError(ContainSubstring("cannot be empty")))
+
+		_, err = k3senv.LoadConfigFromFile(filepath.Join(t.TempDir(), "missing.yaml"))
+		g.Expect(err).To(MatchError(ContainSubstring("failed to read config file")))
+
+		t.Setenv(k3senv.ConfigFileEnv, writeConfig(t, "k3senv.yaml", "webhook: [invalid"))
+		_, err = k3senv.LoadConfigFromEnv()
+		g.Expect(err).To(MatchError(ContainSubstring("failed to read config file")))
+	})
+}
+
+func TestWebhookServerOptions(t *testing.T) {
+	g := NewWithT(t)
+
+	env, err := k3senv.New(
+		k3senv.WithCertPath(testCertPath),
+		k3senv.WithWebhookPort(9444),
+		k3senv.WithWebhookServerOptions(func(o *ctrlwebhook.Options) {
+			o.ClientCAName = "client-ca.crt"
+			o.Port = 1234
+			o.CertDir = "/other"
+		}),
+	)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(env.Options().Webhook.OptionsMutator).NotTo(BeNil())
+
+	server, ok := env.WebhookServer().(*ctrlwebhook.DefaultServer)
+	g.Expect(ok).To(BeTrue())
+
+	// the mutator is applied, except for the port and certificate fields
+	g.Expect(server.Options.ClientCAName).To(Equal("client-ca.crt"))
+	g.Expect(server.Options.Port).To(Equal(9444))
+	g.Expect(server.Options.CertDir).To(Equal(testCertPath))
+	g.Expect(server.Options.TLSOpts).To(HaveLen(1))
+}
+
+func TestSlogHandler(t *testing.T) {
+	t.Run("Options set the handler", func(t *testing.T) {
+		g := NewWithT(t)
+
+		handler := slog.NewTextHandler(io.Discard, nil)
+
+		opts := &k3senv.Options{}
+		k3senv.WithSlogHandler(handler).ApplyToOptions(opts)
+		g.Expect(opts.SlogHandler).To(BeIdenticalTo(handler))
+
+		logger := slog.New(slog.NewJSONHandler(io.Discard, nil))
+		k3senv.WithSlogLogger(logger).ApplyToOptions(opts)
+		g.Expect(opts.SlogHandler).To(BeIdenticalTo(logger.Handler()))
+	})
+
+	t.Run("Handler takes precedence over Logger", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var buf bytes.Buffer
+		var logMessages []string
+
+		env, err := k3senv.New(
+			k3senv.WithPreExistingCluster(filepath.Join(t.TempDir(), "missing")),
+			k3senv.WithLogger(&mockLogger{messages: &logMessages}),
+			k3senv.WithSlogHandler(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})),
+		)
+		g.Expect(err).NotTo(HaveOccurred())
+
+		// Start logs before failing to read the kubeconfig
+		g.Expect(env.Start(context.Background())).NotTo(Succeed())
+
+		g.Expect(logMessages).To(BeEmpty())
+
+		record := map[string]any{}
+		line, _, _ := strings.Cut(buf.String(), "\n")
+		g.Expect(json.Unmarshal([]byte(line), &record)).To(Succeed())
+		g.Expect(record).To(HaveKeyWithValue("level", "DEBUG"))
+		g.Expect(record).To(HaveKeyWithValue("component", "k3senv"))
+		g.Expect(record).To(HaveKeyWithValue("msg", ContainSubstring("Starting k3s environment")))
+	})
+
+	t.Run("Disabled debug level is not logged", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var buf bytes.Buffer
+
+		env, err := k3senv.New(
+			k3senv.WithPreExistingCluster(filepath.Join(t.TempDir(), "missing")),
+			k3senv.WithSlogHandler(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo})),
+		)
+		g.Expect(err).NotTo(HaveOccurred())
+
+		g.Expect(env.Start(context.Background())).NotTo(Succeed())
+		g.Expect(buf.String()).To(BeEmpty())
+	})
+}
+
+func TestSetLogger(t *testing.T) {
+	g := NewWithT(t)
+
+	var initialMessages []string
+	var messages []string
+	initial := &mockLogger{messages: &initialMessages}
+	logger := &mockLogger{messages: &messages}
+
+	env, err := k3senv.New(k3senv.WithPreExistingCluster(filepath.Join(t.TempDir(), "missing")))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(env.GetLogger()).To(BeNil())
+
+	env.SetLogger(initial)
+	g.Expect(env.GetLogger()).To(BeIdenticalTo(initial))
+
+	env.SetLogger(logger)
+	g.Expect(env.GetLogger()).To(BeIdenticalTo(logger))
+	g.Expect(env.Options().Logger).To(BeIdenticalTo(logger))
+
+	// Start logs before failing to read the kubeconfig
+	g.Expect(env.Start(context.Background())).NotTo(Succeed())
+
+	g.Expect(initialMessages).To(BeEmpty())
+	g.Expect(messages).To(ContainElement(HavePrefix("[k3senv] Starting k3s environment")))
+
+	env.SetLogger(nil)
+	g.Expect(env.GetLogger()).To(BeNil())
+}
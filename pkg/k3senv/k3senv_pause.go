@@ -0,0 +1,66 @@
+package k3senv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Pause freezes the k3s container through the Docker pause API, making the API
+// server unavailable. While paused, every operation of the client returned by
+// Client fails with ErrEnvironmentPaused. Pausing an already paused environment
+// is a no-op.
+func (e *K3sEnv) Pause(ctx context.Context) error {
+	if !e.IsStarted() {
+		return errors.New("cluster not started - call Start() first")
+	}
+
+	if e.container == nil {
+		return errors.New("pause is not supported with a pre-existing cluster")
+	}
+
+	if e.IsPaused() {
+		return nil
+	}
+
+	e.debugf("Pausing k3s container")
+
+	if err := e.setContainerPaused(ctx, true); err != nil {
+		return err
+	}
+
+	e.paused.Store(true)
+
+	return nil
+}
+
+// Resume unpauses the k3s container paused by Pause and waits for the API
+// server to be ready again, up to the K3s StartTimeout. Resuming an
+// environment that is not paused is a no-op.
+func (e *K3sEnv) Resume(ctx context.Context) error {
+	if !e.IsPaused() {
+		return nil
+	}
+
+	e.debugf("Resuming k3s container")
+
+	if err := e.setContainerPaused(ctx, false); err != nil {
+		return err
+	}
+
+	e.paused.Store(false)
+
+	if err := e.waitForAPIServer(ctx, e.options.K3s.StartTimeout); err != nil {
+		return fmt.Errorf("failed to resume environment: %w", err)
+	}
+
+	e.debugf("k3s container resumed successfully")
+
+	return nil
+}
+
+// IsPaused reports whether the environment has been paused by Pause and not
+// resumed since.
+func (e *K3sEnv) IsPaused() bool {
+	return e.paused.Load()
+}
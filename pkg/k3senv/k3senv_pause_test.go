@@ -0,0 +1,63 @@
+package k3senv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lburgazzoli/k3s-envtest/pkg/k3senv"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestPauseResume(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	g.Expect(apiextensionsv1.AddToScheme(scheme)).To(Succeed())
+
+	crd := newTestCRDNonConvertible()
+
+	env, err := k3senv.New(
+		k3senv.WithScheme(scheme),
+		k3senv.WithObjects(crd),
+	)
+	g.Expect(err).NotTo(HaveOccurred())
+	t.Cleanup(func() {
+		_ = env.Stop(ctx)
+	})
+
+	err = env.Start(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	err = env.Pause(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(env.IsPaused()).To(BeTrue())
+
+	err = env.Client().Get(ctx, client.ObjectKey{Name: crd.Name}, &apiextensionsv1.CustomResourceDefinition{})
+	g.Expect(err).To(MatchError(k3senv.ErrEnvironmentPaused))
+
+	err = env.Resume(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(env.IsPaused()).To(BeFalse())
+
+	err = env.Client().Get(ctx, client.ObjectKey{Name: crd.Name}, &apiextensionsv1.CustomResourceDefinition{})
+	g.Expect(err).NotTo(HaveOccurred())
+}
+
+func TestPause_BeforeStart(t *testing.T) {
+	g := NewWithT(t)
+
+	env, err := k3senv.New()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	err = env.Pause(context.Background())
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("call Start() first"))
+
+	g.Expect(env.Resume(context.Background())).To(Succeed())
+}
@@ -0,0 +1,308 @@
+package k3senv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+	"time"
+)
+
+// PoolOption configures a Pool.
+type PoolOption interface {
+	ApplyToPoolOptions(opts *PoolOptions)
+}
+
+// poolOptionFunc is an adapter that allows a simple function to be used as a PoolOption.
+type poolOptionFunc func(*PoolOptions)
+
+func (f poolOptionFunc) ApplyToPoolOptions(o *PoolOptions) {
+	f(o)
+}
+
+// PoolOptions groups the configuration of a Pool.
+type PoolOptions struct {
+	// MaxSize is the maximum number of environments managed by the pool.
+	// Defaults to 0 (unlimited).
+	MaxSize int
+
+	// IdleTimeout is the time after which an idle environment is stopped and
+	// removed from the pool. Defaults to 0 (never).
+	IdleTimeout time.Duration
+
+	// EnvOptions are applied to every environment created by the pool, before
+	// the options given to Acquire.
+	EnvOptions []Option
+}
+
+func (o *PoolOptions) ApplyToPoolOptions(target *PoolOptions) {
+	if o.MaxSize != 0 {
+		target.MaxSize = o.MaxSize
+	}
+	if o.IdleTimeout != 0 {
+		target.IdleTimeout = o.IdleTimeout
+	}
+	target.EnvOptions = append(target.EnvOptions, o.EnvOptions...)
+}
+
+var _ PoolOption = &PoolOptions{}
+
+// WithMaxSize sets the maximum number of environments managed by the pool.
+func WithMaxSize(n int) PoolOption {
+	return poolOptionFunc(func(o *PoolOptions) { o.MaxSize = n })
+}
+
+// WithIdleTimeout sets the time after which an idle environment is stopped
+// and removed from the pool.
+func WithIdleTimeout(d time.Duration) PoolOption {
+	return poolOptionFunc(func(o *PoolOptions) { o.IdleTimeout = d })
+}
+
+// WithEnvOptions sets the options applied to every environment created by the pool.
+func WithEnvOptions(opts ...Option) PoolOption {
+	return poolOptionFunc(func(o *PoolOptions) { o.EnvOptions = append(o.EnvOptions, opts...) })
+}
+
+// Pool manages started environments so that a k3s container can be reused
+// across tests instead of being started for each of them:
+//
+//	pool := k3senv.NewPool(k3senv.WithMaxSize(2))
+//	defer func() {
+//	    _ = pool.Close(ctx)
+//	}()
+//
+//	env, err := pool.Acquire(ctx, k3senv.WithObjects(crd))
+//	if err != nil {
+//	    return err
+//	}
+//	defer func() {
+//	    _ = pool.Release(ctx, env)
+//	}()
+//
+// Idle environments exceeding IdleTimeout are stopped the next time the pool
+// is used. A Pool is safe for concurrent use.
+type Pool struct {
+	options PoolOptions
+
+	mu      sync.Mutex
+	entries []*poolEntry
+}
+
+type poolEntry struct {
+	env       *K3sEnv
+	key       Options
+	inUse     bool
+	idleSince time.Time
+}
+
+// NewPool creates an empty pool of environments.
+func NewPool(opts ...PoolOption) *Pool {
+	p := &Pool{}
+
+	for _, opt := range opts {
+		opt.ApplyToPoolOptions(&p.options)
+	}
+
+	return p
+}
+
+// Acquire returns an idle environment created with the same options, or
+// creates and starts a new one. The environment must be given back with
+// Release once done.
+//
+// Loggers, hooks and resolvers are not compared: an idle environment may have
+// been created with different ones, and the PreStart and PostStart hooks only
+// run when a new environment is started. WithTestingT is rejected, as the
+// environments are shared across tests and stopped by Close instead.
+//
+// An error is returned if the pool reached MaxSize and no idle environment
+// can be evicted to make room for a new one.
+func (p *Pool) Acquire(ctx context.Context, envOpts ...Option) (*K3sEnv, error) {
+	opts := slices.Concat(p.options.EnvOptions, envOpts)
+
+	options := Options{}
+	options.ApplyOptions(opts)
+
+	if options.TestingT != nil {
+		return nil, errors.New("pooled environments do not support WithTestingT - release them with Release instead")
+	}
+
+	key := poolKey(options)
+
+	p.mu.Lock()
+
+	evicted := p.evictExpired()
+
+	idx := slices.IndexFunc(p.entries, func(entry *poolEntry) bool {
+		return !entry.inUse && reflect.DeepEqual(entry.key, key)
+	})
+	if idx >= 0 {
+		entry := p.entries[idx]
+		entry.inUse = true
+
+		p.mu.Unlock()
+
+		return entry.env, stopAll(ctx, evicted)
+	}
+
+	if p.options.MaxSize > 0 && len(p.entries) >= p.options.MaxSize {
+		idle := slices.IndexFunc(p.entries, func(entry *poolEntry) bool {
+			return !entry.inUse
+		})
+		if idle < 0 {
+			p.mu.Unlock()
+
+			return nil, errors.Join(
+				fmt.Errorf("pool exhausted: all %d environments are in use", p.options.MaxSize),
+				stopAll(ctx, evicted),
+			)
+		}
+
+		evicted = append(evicted, p.entries[idle].env)
+		p.entries = slices.Delete(p.entries, idle, idle+1)
+	}
+
+	// reserve the slot, so that concurrent calls honor MaxSize while the
+	// environment is starting
+	entry := &poolEntry{key: key, inUse: true}
+	p.entries = append(p.entries, entry)
+
+	p.mu.Unlock()
+
+	if err := stopAll(ctx, evicted); err != nil {
+		p.remove(entry)
+		return nil, err
+	}
+
+	env, err := New(opts...)
+	if err != nil {
+		p.remove(entry)
+		return nil, err
+	}
+
+	if err := env.Start(ctx); err != nil {
+		p.remove(entry)
+		return nil, errors.Join(err, env.Stop(ctx))
+	}
+
+	p.mu.Lock()
+
+	// the pool may have been closed while the environment was starting, in
+	// which case nothing would stop it afterwards
+	if !slices.Contains(p.entries, entry) {
+		p.mu.Unlock()
+
+		return nil, errors.Join(errors.New("pool closed"), env.Stop(ctx))
+	}
+
+	entry.env = env
+
+	p.mu.Unlock()
+
+	return env, nil
+}
+
+// Release resets the environment with K3sEnv.Reset and returns it to the pool.
+// If the reset fails, the environment is stopped and removed from the pool.
+func (p *Pool) Release(ctx context.Context, env *K3sEnv) error {
+	p.mu.Lock()
+	idx := slices.IndexFunc(p.entries, func(entry *poolEntry) bool {
+		return entry.env == env && entry.inUse
+	})
+	if idx < 0 {
+		p.mu.Unlock()
+		return errors.New("environment not acquired from this pool")
+	}
+	entry := p.entries[idx]
+	p.mu.Unlock()
+
+	if err := env.Reset(ctx); err != nil {
+		p.remove(entry)
+		return errors.Join(fmt.Errorf("failed to release environment: %w", err), env.Stop(ctx))
+	}
+
+	p.mu.Lock()
+	entry.inUse = false
+	entry.idleSince = time.Now()
+	evicted := p.evictExpired()
+	p.mu.Unlock()
+
+	return stopAll(ctx, evicted)
+}
+
+// Close stops all the environments of the pool, including the ones in use.
+// Environments still starting are stopped by Acquire, which returns an error.
+func (p *Pool) Close(ctx context.Context) error {
+	p.mu.Lock()
+	envs := make([]*K3sEnv, 0, len(p.entries))
+	for _, entry := range p.entries {
+		if entry.env != nil {
+			envs = append(envs, entry.env)
+		}
+	}
+	p.entries = nil
+	p.mu.Unlock()
+
+	return stopAll(ctx, envs)
+}
+
+// evictExpired removes the idle entries exceeding IdleTimeout, returning
+// their environments. It must be called with the lock held.
+func (p *Pool) evictExpired() []*K3sEnv {
+	if p.options.IdleTimeout <= 0 {
+		return nil
+	}
+
+	var evicted []*K3sEnv
+
+	p.entries = slices.DeleteFunc(p.entries, func(entry *poolEntry) bool {
+		if entry.inUse || time.Since(entry.idleSince) < p.options.IdleTimeout {
+			return false
+		}
+
+		evicted = append(evicted, entry.env)
+
+		return true
+	})
+
+	return evicted
+}
+
+func (p *Pool) remove(entry *poolEntry) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.entries = slices.DeleteFunc(p.entries, func(e *poolEntry) bool {
+		return e == entry
+	})
+}
+
+func stopAll(ctx context.Context, envs []*K3sEnv) error {
+	var errs []error
+
+	for _, env := range envs {
+		if err := env.Stop(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// poolKey returns the options used to match the environments of a pool:
+// the explicit options, without the ones that cannot be compared such as
+// loggers, hooks and resolvers. Objects are copied, as they may be modified
+// by the environment once started.
+func poolKey(options Options) Options {
+	key := *options.DeepCopy()
+
+	key.Logger = nil
+	key.SlogHandler = nil
+	key.Hooks = HooksConfig{}
+	key.Webhook.HostResolver = nil
+	key.Webhook.OptionsMutator = nil
+
+	return key
+}
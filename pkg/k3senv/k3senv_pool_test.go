@@ -0,0 +1,120 @@
+package k3senv_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lburgazzoli/k3s-envtest/pkg/k3senv"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestPoolOptions(t *testing.T) {
+	g := NewWithT(t)
+
+	target := &k3senv.PoolOptions{}
+
+	k3senv.WithMaxSize(3).ApplyToPoolOptions(target)
+	k3senv.WithIdleTimeout(time.Minute).ApplyToPoolOptions(target)
+	k3senv.WithEnvOptions(k3senv.WithCertPath(testCertPath)).ApplyToPoolOptions(target)
+	k3senv.WithEnvOptions(k3senv.WithK3sImage("rancher/k3s:latest")).ApplyToPoolOptions(target)
+
+	g.Expect(target.MaxSize).To(Equal(3))
+	g.Expect(target.IdleTimeout).To(Equal(time.Minute))
+	g.Expect(target.EnvOptions).To(HaveLen(2))
+}
+
+func TestPool_ReleaseUnknownEnvironment(t *testing.T) {
+	g := NewWithT(t)
+
+	env, err := k3senv.New()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	pool := k3senv.NewPool()
+
+	err = pool.Release(context.Background(), env)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("not acquired from this pool"))
+}
+
+func TestPool_RejectsTestingT(t *testing.T) {
+	g := NewWithT(t)
+
+	pool := k3senv.NewPool()
+
+	_, err := pool.Acquire(context.Background(), k3senv.WithTestingT(t))
+	g.Expect(err).To(MatchError(ContainSubstring("do not support WithTestingT")))
+
+	pool = k3senv.NewPool(k3senv.WithEnvOptions(k3senv.WithTestingT(t)))
+
+	_, err = pool.Acquire(context.Background())
+	g.Expect(err).To(MatchError(ContainSubstring("do not support WithTestingT")))
+}
+
+func TestPool_CloseWhileStarting(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	var pool *k3senv.Pool
+	var started *k3senv.K3sEnv
+
+	pool = k3senv.NewPool(k3senv.WithEnvOptions(
+		k3senv.WithCertPath(t.TempDir()),
+		k3senv.WithPostStart(func(ctx context.Context, env *k3senv.K3sEnv) error {
+			started = env
+			return pool.Close(ctx)
+		}),
+	))
+
+	_, err := pool.Acquire(ctx)
+	g.Expect(err).To(MatchError(ContainSubstring("pool closed")))
+	g.Expect(started).NotTo(BeNil())
+	g.Expect(started.IsStopped()).To(BeTrue())
+}
+
+func TestPool_ReusesEnvironment(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	g.Expect(apiextensionsv1.AddToScheme(scheme)).To(Succeed())
+
+	crd := newTestCRDNonConvertible()
+
+	pool := k3senv.NewPool(
+		k3senv.WithMaxSize(1),
+		k3senv.WithEnvOptions(k3senv.WithScheme(scheme)),
+	)
+	t.Cleanup(func() {
+		_ = pool.Close(ctx)
+	})
+
+	env, err := pool.Acquire(ctx, k3senv.WithObjects(crd))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(env.IsStarted()).To(BeTrue())
+
+	containerID := env.ContainerID()
+
+	_, err = pool.Acquire(ctx, k3senv.WithObjects(crd))
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("pool exhausted"))
+
+	err = pool.Release(ctx, env)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	reused, err := pool.Acquire(ctx, k3senv.WithObjects(newTestCRDNonConvertible()))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(reused).To(BeIdenticalTo(env))
+	g.Expect(reused.ContainerID()).To(Equal(containerID))
+
+	err = reused.Client().Get(ctx, client.ObjectKey{Name: crd.Name}, &apiextensionsv1.CustomResourceDefinition{})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	err = pool.Release(ctx, reused)
+	g.Expect(err).NotTo(HaveOccurred())
+}
@@ -0,0 +1,69 @@
+package k3senv
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/lburgazzoli/k3s-envtest/internal/cert"
+	"github.com/testcontainers/testcontainers-go"
+	"gopkg.in/yaml.v3"
+)
+
+// k3sRegistries is the content of the registries.yaml file of k3s, see
+// https://docs.k3s.io/installation/private-registry.
+type k3sRegistries struct {
+	Mirrors map[string]k3sRegistryMirror `yaml:"mirrors,omitempty"`
+	Configs map[string]k3sRegistryConfig `yaml:"configs,omitempty"`
+}
+
+type k3sRegistryMirror struct {
+	Endpoint []string `yaml:"endpoint"`
+}
+
+type k3sRegistryConfig struct {
+	Auth k3sRegistryAuth `yaml:"auth"`
+}
+
+type k3sRegistryAuth struct {
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+}
+
+// registriesConfig generates the registries.yaml file of k3s from the registry
+// mirrors and credentials.
+func registriesConfig(cfg K3sConfig) ([]byte, error) {
+	registries := k3sRegistries{
+		Mirrors: make(map[string]k3sRegistryMirror, len(cfg.RegistryMirrors)),
+		Configs: make(map[string]k3sRegistryConfig, len(cfg.RegistryCreds)),
+	}
+
+	for registry, mirrorURL := range cfg.RegistryMirrors {
+		registries.Mirrors[registry] = k3sRegistryMirror{Endpoint: []string{mirrorURL}}
+	}
+	for registry, creds := range cfg.RegistryCreds {
+		registries.Configs[registry] = k3sRegistryConfig{
+			Auth: k3sRegistryAuth{Username: creds.Username, Password: creds.Password},
+		}
+	}
+
+	data, err := yaml.Marshal(registries)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal k3s registries configuration: %w", err)
+	}
+
+	return data, nil
+}
+
+// withRegistriesConfig creates a customizer that copies the registries.yaml
+// file into the container before it starts. A new reader is created each time
+// the customizer is applied, as the container start may be retried.
+func withRegistriesConfig(data []byte) testcontainers.ContainerCustomizer {
+	return testcontainers.CustomizeRequestOption(func(req *testcontainers.GenericContainerRequest) error {
+		req.Files = append(req.Files, testcontainers.ContainerFile{
+			Reader:            bytes.NewReader(data),
+			ContainerFilePath: k3sRegistriesConfigPath,
+			FileMode:          cert.DefaultFilePermission,
+		})
+		return nil
+	})
+}
@@ -0,0 +1,33 @@
+package k3senv
+
+import (
+	"context"
+	"errors"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// SSAObject applies obj with server-side apply on behalf of fieldOwner. The
+// GroupVersionKind of obj is resolved from the scheme if not set.
+//
+// Server-side apply is the preferred way to install resources in tests: unlike
+// Create it is idempotent, and conflicts with the fields managed by other
+// owners are reported in detail. Use ForceSSAObject to take the ownership of
+// conflicting fields instead.
+func (e *K3sEnv) SSAObject(ctx context.Context, obj client.Object, fieldOwner string) error {
+	if e.cli == nil {
+		return errors.New("cluster not started - call Start() first")
+	}
+
+	return e.ssaObject(ctx, obj, fieldOwner)
+}
+
+// ForceSSAObject is like SSAObject, taking the ownership of the fields managed
+// by other owners in case of conflict.
+func (e *K3sEnv) ForceSSAObject(ctx context.Context, obj client.Object, fieldOwner string) error {
+	if e.cli == nil {
+		return errors.New("cluster not started - call Start() first")
+	}
+
+	return e.ssaObject(ctx, obj, fieldOwner, client.ForceOwnership)
+}
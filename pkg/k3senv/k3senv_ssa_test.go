@@ -0,0 +1,61 @@
+package k3senv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lburgazzoli/k3s-envtest/pkg/k3senv"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestSSAObject(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	newConfigMap := func(value string) *unstructured.Unstructured {
+		cm := &unstructured.Unstructured{}
+		cm.SetAPIVersion("v1")
+		cm.SetKind("ConfigMap")
+		cm.SetNamespace(metav1.NamespaceDefault)
+		cm.SetName("ssa-config")
+		g.Expect(unstructured.SetNestedField(cm.Object, value, "data", "key")).To(Succeed())
+
+		return cm
+	}
+
+	env, err := k3senv.New()
+	g.Expect(err).NotTo(HaveOccurred())
+	t.Cleanup(func() {
+		_ = env.Stop(ctx)
+	})
+
+	err = env.SSAObject(ctx, newConfigMap("v1"), "owner-a")
+	g.Expect(err).To(MatchError(ContainSubstring("call Start() first")))
+
+	err = env.Start(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(env.SSAObject(ctx, newConfigMap("v1"), "owner-a")).To(Succeed())
+	g.Expect(env.SSAObject(ctx, newConfigMap("v1"), "owner-a")).To(Succeed())
+
+	err = env.SSAObject(ctx, newConfigMap("v2"), "owner-b")
+	g.Expect(k8serr.IsConflict(err)).To(BeTrue(), "expected a conflict, got: %v", err)
+
+	g.Expect(env.ForceSSAObject(ctx, newConfigMap("v2"), "owner-b")).To(Succeed())
+
+	found := &unstructured.Unstructured{}
+	found.SetAPIVersion("v1")
+	found.SetKind("ConfigMap")
+
+	err = env.Client().Get(ctx, client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: "ssa-config"}, found)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	value, _, _ := unstructured.NestedString(found.Object, "data", "key")
+	g.Expect(value).To(Equal("v2"))
+}
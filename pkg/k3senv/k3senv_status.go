@@ -0,0 +1,75 @@
+package k3senv
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// EnvironmentStatus is a snapshot of the state of a K3sEnv, see Status.
+type EnvironmentStatus struct {
+	Started           bool
+	ContainerID       string
+	APIServerURL      string
+	InstalledCRDs     []string
+	InstalledWebhooks []string
+	CertPath          string
+	CertExpiresAt     time.Time
+}
+
+// Status returns a snapshot of the state of the environment, useful to
+// diagnose test failures. It is safe to call before Start and does not make
+// any network call.
+func (e *K3sEnv) Status() EnvironmentStatus {
+	status := EnvironmentStatus{
+		Started:     e.IsStarted(),
+		ContainerID: e.ContainerID(),
+		CertPath:    e.options.Certificate.Path,
+	}
+
+	if e.cfg != nil {
+		status.APIServerURL = e.cfg.Host
+	}
+
+	if status.Started {
+		for _, crd := range e.manifests.CustomResourceDefinitions {
+			status.InstalledCRDs = append(status.InstalledCRDs, crd.GetName())
+		}
+	}
+
+	for _, wh := range e.installedWebhooks {
+		status.InstalledWebhooks = append(status.InstalledWebhooks, wh.GetName())
+	}
+
+	if e.certData != nil {
+		if expiresAt, err := e.certData.ExpiresAt(); err == nil {
+			status.CertExpiresAt = expiresAt
+		}
+	}
+
+	return status
+}
+
+// DebugInfo formats Status into a human-readable multi-line string, suitable
+// for t.Logf.
+func (e *K3sEnv) DebugInfo() string {
+	status := e.Status()
+
+	var sb strings.Builder
+
+	sb.WriteString("k3s environment:\n")
+	fmt.Fprintf(&sb, "  Started:            %t\n", status.Started)
+	fmt.Fprintf(&sb, "  Container ID:       %s\n", status.ContainerID)
+	fmt.Fprintf(&sb, "  API server URL:     %s\n", status.APIServerURL)
+	fmt.Fprintf(&sb, "  Installed CRDs:     %s\n", strings.Join(status.InstalledCRDs, ", "))
+	fmt.Fprintf(&sb, "  Installed webhooks: %s\n", strings.Join(status.InstalledWebhooks, ", "))
+	fmt.Fprintf(&sb, "  Certificate path:   %s\n", status.CertPath)
+
+	if status.CertExpiresAt.IsZero() {
+		sb.WriteString("  Certificate expiry: \n")
+	} else {
+		fmt.Fprintf(&sb, "  Certificate expiry: %s\n", status.CertExpiresAt.Format(time.RFC3339))
+	}
+
+	return sb.String()
+}
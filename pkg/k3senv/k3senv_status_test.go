@@ -0,0 +1,65 @@
+package k3senv_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lburgazzoli/k3s-envtest/pkg/k3senv"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestStatus(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	g.Expect(apiextensionsv1.AddToScheme(scheme)).To(Succeed())
+
+	crd := newTestCRDNonConvertible()
+
+	env, err := k3senv.New(
+		k3senv.WithScheme(scheme),
+		k3senv.WithObjects(crd),
+	)
+	g.Expect(err).NotTo(HaveOccurred())
+	t.Cleanup(func() {
+		_ = env.Stop(ctx)
+	})
+
+	err = env.Start(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	status := env.Status()
+	g.Expect(status.Started).To(BeTrue())
+	g.Expect(status.ContainerID).To(Equal(env.ContainerID()))
+	g.Expect(status.APIServerURL).To(Equal(env.Config().Host))
+	g.Expect(status.InstalledCRDs).To(ConsistOf(crd.Name))
+	g.Expect(status.InstalledWebhooks).To(BeEmpty())
+	g.Expect(status.CertPath).To(Equal(env.CertPath()))
+	g.Expect(status.CertExpiresAt).To(BeTemporally(">", time.Now()))
+
+	info := env.DebugInfo()
+	g.Expect(info).To(ContainSubstring(status.ContainerID))
+	g.Expect(info).To(ContainSubstring(crd.Name))
+}
+
+func TestStatus_BeforeStart(t *testing.T) {
+	g := NewWithT(t)
+
+	env, err := k3senv.New()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	status := env.Status()
+	g.Expect(status.Started).To(BeFalse())
+	g.Expect(status.ContainerID).To(BeEmpty())
+	g.Expect(status.APIServerURL).To(BeEmpty())
+	g.Expect(status.InstalledCRDs).To(BeEmpty())
+	g.Expect(status.CertExpiresAt.IsZero()).To(BeTrue())
+
+	g.Expect(env.DebugInfo()).To(ContainSubstring("Started:            false"))
+}
@@ -2,11 +2,15 @@ package k3senv
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"slices"
 
 	"github.com/lburgazzoli/k3s-envtest/internal/resources"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 func (e *K3sEnv) installCRDs(ctx context.Context) error {
@@ -15,12 +19,52 @@ func (e *K3sEnv) installCRDs(ctx context.Context) error {
 		return nil
 	}
 
+	// apply all the CRDs first, so that they are established concurrently
+	names := make([]string, 0, len(crds))
+
 	for i := range crds {
-		if err := e.InstallCRD(ctx, &crds[i]); err != nil {
+		e.debugf("Installing CRD %s", crds[i].GetName())
+
+		if err := e.ForceSSAObject(ctx, &crds[i], DefaultFieldOwner); err != nil {
 			return err
 		}
+
+		names = append(names, crds[i].GetName())
+	}
+
+	e.debugf("Waiting for %d CRDs to be established...", len(names))
+
+	return e.WaitForAllCRDs(ctx, names)
+}
+
+// uninstallCRDs deletes the CRDs loaded from the manifests and waits for them
+// to be gone, so that they can be installed again.
+func (e *K3sEnv) uninstallCRDs(ctx context.Context) error {
+	crds := e.CustomResourceDefinitions()
+
+	for i := range crds {
+		if err := e.cli.Delete(ctx, &crds[i]); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete CRD %s: %w", crds[i].GetName(), err)
+		}
+	}
+
+	for i := range crds {
+		err := resources.WaitForCRDDeleted(
+			ctx,
+			e.cli,
+			crds[i].GetName(),
+			e.options.CRD.PollInterval,
+			e.options.CRD.ReadyTimeout,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to wait for CRD to be deleted: %w", err)
+		}
+
+		e.debugf("CRD %s deleted", crds[i].GetName())
 	}
 
+	e.conversionCRDs = nil
+
 	return nil
 }
 
@@ -37,7 +81,71 @@ func (e *K3sEnv) patchAndUpdateCRDConversions(
 		if err := e.InstallCRD(ctx, &convertibleCRDs[i]); err != nil {
 			return err
 		}
+
+		if !slices.Contains(e.conversionCRDs, convertibleCRDs[i].Name) {
+			e.conversionCRDs = append(e.conversionCRDs, convertibleCRDs[i].Name)
+		}
 	}
 
 	return nil
 }
+
+// patchCRDConversionCABundle patches the CA bundle of the conversion webhook of
+// the named CRD, leaving the rest of its conversion configuration untouched.
+// The bundle is the PEM encoded CA certificate, as set by PatchCRDConversion,
+// which is base64 encoded by the JSON marshaling of the patch.
+func (e *K3sEnv) patchCRDConversionCABundle(
+	ctx context.Context,
+	name string,
+	caBundle []byte,
+) error {
+	data, err := json.Marshal(map[string]any{
+		"spec": map[string]any{
+			"conversion": map[string]any{
+				"webhook": map[string]any{
+					"clientConfig": map[string]any{
+						"caBundle": caBundle,
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal CA bundle patch for CRD %s: %w", name, err)
+	}
+
+	crd := apiextensionsv1.CustomResourceDefinition{}
+	crd.SetName(name)
+
+	if err := e.cli.Patch(ctx, &crd, client.RawPatch(types.MergePatchType, data)); err != nil {
+		return fmt.Errorf("failed to patch CA bundle of CRD %s: %w", name, err)
+	}
+
+	e.debugf("CRD %s conversion CA bundle updated", name)
+
+	return nil
+}
+
+// conversionFields returns the sorted top-level fields of the given objects
+// compared by ValidateCRDConversion, which are all but the type, metadata and
+// status ones.
+func conversionFields(objs ...map[string]any) []string {
+	fields := make([]string, 0)
+
+	for _, obj := range objs {
+		for field := range obj {
+			switch field {
+			case "apiVersion", "kind", "metadata", "status":
+				continue
+			}
+
+			if !slices.Contains(fields, field) {
+				fields = append(fields, field)
+			}
+		}
+	}
+
+	slices.Sort(fields)
+
+	return fields
+}
@@ -1,7 +1,9 @@
 package k3senv
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
 	"regexp"
 	"strings"
 
@@ -25,7 +27,7 @@ func (lc *loggerConsumer) Accept(log testcontainers.Log) {
 	if lc.logger != nil {
 		message := strings.TrimSpace(string(log.Content))
 		if message != "" {
-			lc.logger.Logf("[k3s] %s", message)
+			lc.logger.Logf("%s", message)
 		}
 	}
 }
@@ -46,7 +48,7 @@ func (tcl *testcontainersLogger) Printf(format string, v ...any) {
 	message = strings.TrimSpace(message)
 
 	if message != "" {
-		tcl.logger.Logf("[testcontainers] %s", message)
+		tcl.logger.Logf("%s", message)
 	}
 }
 
@@ -69,9 +71,39 @@ func (e *K3sEnv) configureTestcontainersLogger() {
 		return
 	}
 
-	if e.options.Logger != nil {
-		tclog.SetDefault(&testcontainersLogger{logger: e.options.Logger})
+	if logger := e.sourceLogger("testcontainers"); logger != nil {
+		tclog.SetDefault(&testcontainersLogger{logger: logger})
 	} else {
 		tclog.SetDefault(noopLogger{})
 	}
 }
+
+// slogLogger adapts a slog handler to the Logger interface, logging messages
+// at debug level.
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+func (l *slogLogger) Logf(format string, args ...any) {
+	l.logger.Log(context.Background(), slog.LevelDebug, fmt.Sprintf(format, args...))
+}
+
+// sourceLogger returns the logger of the logs of the given source, such as
+// the k3s container: with a "source" attribute for a SlogHandler, which takes
+// precedence, or a "[source]" prefix for a Logger. It returns nil if neither
+// is set.
+func (e *K3sEnv) sourceLogger(source string) Logger {
+	if e.options.SlogHandler != nil {
+		return &slogLogger{
+			logger: slog.New(e.options.SlogHandler.WithAttrs([]slog.Attr{slog.String("source", source)})),
+		}
+	}
+
+	if logger := e.GetLogger(); logger != nil {
+		return LoggerFunc(func(format string, args ...any) {
+			logger.Logf("["+source+"] "+format, args...)
+		})
+	}
+
+	return nil
+}
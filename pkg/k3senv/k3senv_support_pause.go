@@ -0,0 +1,159 @@
+package k3senv
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/discovery"
+)
+
+// apiServerPollInterval is the interval at which Resume checks whether the
+// API server is ready again.
+const apiServerPollInterval = 500 * time.Millisecond
+
+// setContainerPaused pauses or unpauses the k3s container through the Docker API.
+func (e *K3sEnv) setContainerPaused(ctx context.Context, paused bool) error {
+	dockerClient, err := testcontainers.NewDockerClientWithOpts(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create docker client: %w", err)
+	}
+	defer func() {
+		_ = dockerClient.Close()
+	}()
+
+	id := e.container.GetContainerID()
+
+	if paused {
+		if err := dockerClient.ContainerPause(ctx, id); err != nil {
+			return fmt.Errorf("failed to pause container %s: %w", id, err)
+		}
+	} else {
+		if err := dockerClient.ContainerUnpause(ctx, id); err != nil {
+			return fmt.Errorf("failed to unpause container %s: %w", id, err)
+		}
+	}
+
+	return nil
+}
+
+// waitForAPIServer polls the /readyz endpoint of the API server until it
+// responds successfully or the timeout expires.
+func (e *K3sEnv) waitForAPIServer(ctx context.Context, timeout time.Duration) error {
+	dc, err := discovery.NewDiscoveryClientForConfig(e.cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create discovery client: %w", err)
+	}
+
+	err = wait.PollUntilContextTimeout(ctx, apiServerPollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+		if err := dc.RESTClient().Get().AbsPath("/readyz").Do(ctx).Error(); err != nil {
+			e.debugf("API server not ready: %v", err)
+			return false, nil
+		}
+
+		return true, nil
+	})
+	if err != nil {
+		return fmt.Errorf("API server not ready after %v: %w", timeout, err)
+	}
+
+	return nil
+}
+
+// pausedInterceptor returns the interceptor functions making every client
+// operation fail with ErrEnvironmentPaused while the environment is paused.
+func (e *K3sEnv) pausedInterceptor() interceptor.Funcs {
+	return interceptor.Funcs{
+		Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			if e.IsPaused() {
+				return ErrEnvironmentPaused
+			}
+			return c.Get(ctx, key, obj, opts...)
+		},
+		List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+			if e.IsPaused() {
+				return ErrEnvironmentPaused
+			}
+			return c.List(ctx, list, opts...)
+		},
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			if e.IsPaused() {
+				return ErrEnvironmentPaused
+			}
+			return c.Create(ctx, obj, opts...)
+		},
+		Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+			if e.IsPaused() {
+				return ErrEnvironmentPaused
+			}
+			return c.Delete(ctx, obj, opts...)
+		},
+		DeleteAllOf: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteAllOfOption) error {
+			if e.IsPaused() {
+				return ErrEnvironmentPaused
+			}
+			return c.DeleteAllOf(ctx, obj, opts...)
+		},
+		Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			if e.IsPaused() {
+				return ErrEnvironmentPaused
+			}
+			return c.Update(ctx, obj, opts...)
+		},
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			if e.IsPaused() {
+				return ErrEnvironmentPaused
+			}
+			return c.Patch(ctx, obj, patch, opts...)
+		},
+		Apply: func(ctx context.Context, c client.WithWatch, obj runtime.ApplyConfiguration, opts ...client.ApplyOption) error {
+			if e.IsPaused() {
+				return ErrEnvironmentPaused
+			}
+			return c.Apply(ctx, obj, opts...)
+		},
+		Watch: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) (watch.Interface, error) {
+			if e.IsPaused() {
+				return nil, ErrEnvironmentPaused
+			}
+			return c.Watch(ctx, list, opts...)
+		},
+		SubResourceGet: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, subResource client.Object, opts ...client.SubResourceGetOption) error {
+			if e.IsPaused() {
+				return ErrEnvironmentPaused
+			}
+			return c.SubResource(subResourceName).Get(ctx, obj, subResource, opts...)
+		},
+		SubResourceCreate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+			if e.IsPaused() {
+				return ErrEnvironmentPaused
+			}
+			return c.SubResource(subResourceName).Create(ctx, obj, subResource, opts...)
+		},
+		SubResourceUpdate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+			if e.IsPaused() {
+				return ErrEnvironmentPaused
+			}
+			return c.SubResource(subResourceName).Update(ctx, obj, opts...)
+		},
+		SubResourcePatch: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+			if e.IsPaused() {
+				return ErrEnvironmentPaused
+			}
+			return c.SubResource(subResourceName).Patch(ctx, obj, patch, opts...)
+		},
+		SubResourceApply: func(ctx context.Context, c client.Client, subResourceName string, obj runtime.ApplyConfiguration, opts ...client.SubResourceApplyOption) error {
+			if e.IsPaused() {
+				return ErrEnvironmentPaused
+			}
+			return c.SubResource(subResourceName).Apply(ctx, obj, opts...)
+		},
+	}
+}
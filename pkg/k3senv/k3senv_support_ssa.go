@@ -0,0 +1,39 @@
+package k3senv
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lburgazzoli/k3s-envtest/internal/resources"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ssaObject applies obj with server-side apply on behalf of fieldOwner.
+func (e *K3sEnv) ssaObject(
+	ctx context.Context,
+	obj client.Object,
+	fieldOwner string,
+	opts ...client.ApplyOption,
+) error {
+	if err := resources.EnsureGroupVersionKind(e.options.Scheme, obj); err != nil {
+		return fmt.Errorf("failed to set GVK for %s: %w", obj.GetName(), err)
+	}
+
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
+
+	// Convert to unstructured for apply configuration
+	unstructuredObj, err := resources.ToUnstructured(obj)
+	if err != nil {
+		return fmt.Errorf("failed to convert %s %s to unstructured: %w", kind, obj.GetName(), err)
+	}
+
+	applyConfig := client.ApplyConfigurationFromUnstructured(unstructuredObj)
+	err = e.cli.Apply(ctx, applyConfig, append(opts, client.FieldOwner(fieldOwner))...)
+	if err != nil {
+		return fmt.Errorf("failed to apply %s %s: %w", kind, obj.GetName(), err)
+	}
+
+	e.debugf("%s %s applied", kind, obj.GetName())
+
+	return nil
+}
@@ -2,13 +2,18 @@ package k3senv
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/url"
+	"slices"
 
 	"github.com/lburgazzoli/k3s-envtest/internal/resources"
 	"github.com/lburgazzoli/k3s-envtest/internal/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/ptr"
 )
 
@@ -17,6 +22,29 @@ func (e *K3sEnv) installWebhook(
 	webhook client.Object,
 	baseURL string,
 	caBundle string,
+) error {
+	if err := e.applyWebhook(ctx, webhook, baseURL, caBundle); err != nil {
+		return err
+	}
+
+	if !ptr.Deref(e.options.Webhook.CheckReadiness, false) {
+		return nil
+	}
+
+	if err := e.waitForWebhookEndpointsReady(ctx, webhook, e.options.Webhook.Port); err != nil {
+		return fmt.Errorf("webhook config %s endpoints not ready: %w", webhook.GetName(), err)
+	}
+
+	return nil
+}
+
+// applyWebhook patches the client configuration of the webhook with the given
+// base URL and CA bundle, applies it and tracks it as installed.
+func (e *K3sEnv) applyWebhook(
+	ctx context.Context,
+	webhook client.Object,
+	baseURL string,
+	caBundle string,
 ) error {
 	switch wh := webhook.(type) {
 	case *admissionregistrationv1.MutatingWebhookConfiguration:
@@ -27,35 +55,44 @@ func (e *K3sEnv) installWebhook(
 		return fmt.Errorf("unsupported webhook type: %T", webhook)
 	}
 
-	if err := resources.EnsureGroupVersionKind(e.options.Scheme, webhook); err != nil {
-		return fmt.Errorf("failed to set GVK for webhook %s: %w", webhook.GetName(), err)
-	}
-
-	// Convert to unstructured for apply configuration
-	unstructuredWebhook, err := resources.ToUnstructured(webhook)
-	if err != nil {
-		return fmt.Errorf("failed to convert webhook %s to unstructured: %w", webhook.GetName(), err)
+	if err := e.ForceSSAObject(ctx, webhook, DefaultFieldOwner); err != nil {
+		return err
 	}
 
-	applyConfig := client.ApplyConfigurationFromUnstructured(unstructuredWebhook)
-	err = e.cli.Apply(ctx, applyConfig, client.ForceOwnership, client.FieldOwner("k3s-envtest"))
-	if err != nil {
-		return fmt.Errorf("failed to apply webhook %s: %w", webhook.GetName(), err)
-	}
+	e.trackWebhook(webhook)
 
-	e.debugf("Webhook configuration %s applied", webhook.GetName())
+	return nil
+}
 
-	if !ptr.Deref(e.options.Webhook.CheckReadiness, false) {
-		return nil
+func (e *K3sEnv) uninstallWebhook(
+	ctx context.Context,
+	webhook client.Object,
+) error {
+	if err := e.cli.Delete(ctx, webhook); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to delete webhook %s: %w", webhook.GetName(), err)
 	}
 
-	if err := e.waitForWebhookEndpointsReady(ctx, webhook, e.options.Webhook.Port); err != nil {
-		return fmt.Errorf("webhook config %s endpoints not ready: %w", webhook.GetName(), err)
-	}
+	e.untrackWebhook(webhook)
+
+	e.debugf("Webhook configuration %s deleted", webhook.GetName())
 
 	return nil
 }
 
+// trackWebhook records a webhook configuration as installed by the environment,
+// replacing any previous record of the same kind and name.
+func (e *K3sEnv) trackWebhook(webhook client.Object) {
+	e.untrackWebhook(webhook)
+	e.installedWebhooks = append(e.installedWebhooks, webhook)
+}
+
+func (e *K3sEnv) untrackWebhook(webhook client.Object) {
+	e.installedWebhooks = slices.DeleteFunc(e.installedWebhooks, func(o client.Object) bool {
+		return o.GetName() == webhook.GetName() &&
+			o.GetObjectKind().GroupVersionKind() == webhook.GetObjectKind().GroupVersionKind()
+	})
+}
+
 func (e *K3sEnv) installWebhooks(
 	ctx context.Context,
 	hostPort string,
@@ -106,13 +143,19 @@ func (e *K3sEnv) waitForWebhookEndpointsReady(
 		return fmt.Errorf("failed to create webhook client: %w", err)
 	}
 
-	if err := webhookClient.WaitForEndpoints(
-		ctx,
-		webhookURLs,
+	waitOpts := []webhook.WaitOption{
 		webhook.WithPollInterval(e.options.Webhook.PollInterval),
 		webhook.WithReadyTimeout(e.options.Webhook.ReadyTimeout),
 		webhook.WithWaitCallTimeout(e.options.Webhook.HealthCheckTimeout),
-	); err != nil {
+	}
+
+	if len(webhookURLs) > 1 {
+		err = webhook.WaitForMultipleEndpoints(ctx, []*webhook.Client{webhookClient}, webhookURLs, waitOpts...)
+	} else {
+		err = webhookClient.WaitForEndpoints(ctx, webhookURLs, waitOpts...)
+	}
+
+	if err != nil {
 		return fmt.Errorf("webhook endpoints not ready: %w", err)
 	}
 
@@ -120,3 +163,128 @@ func (e *K3sEnv) waitForWebhookEndpointsReady(
 
 	return nil
 }
+
+func (e *K3sEnv) checkWebhookEndpoints(
+	ctx context.Context,
+	webhookClient *webhook.Client,
+) error {
+	configs := make([]client.Object, 0)
+
+	mutating := e.MutatingWebhookConfigurations()
+	for i := range mutating {
+		configs = append(configs, &mutating[i])
+	}
+
+	validating := e.ValidatingWebhookConfigurations()
+	for i := range validating {
+		configs = append(configs, &validating[i])
+	}
+
+	for _, config := range configs {
+		webhookURLs, err := resources.ExtractWebhookURLs(config)
+		if err != nil {
+			return fmt.Errorf("failed to extract webhook URLs: %w", err)
+		}
+
+		for _, webhookURL := range webhookURLs {
+			parsedURL, err := url.Parse(webhookURL)
+			if err != nil {
+				return fmt.Errorf("invalid webhook URL %s: %w", webhookURL, err)
+			}
+
+			if err := webhookClient.CheckEndpoint(ctx, parsedURL.Path); err != nil {
+				return fmt.Errorf("webhook %s not ready: %w", config.GetName(), err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// patchWebhookCABundle patches the caBundle of every webhook of the given
+// webhook configuration with a strategic merge patch. Webhooks are merged
+// by name, so that the rest of their configuration is preserved.
+func (e *K3sEnv) patchWebhookCABundle(
+	ctx context.Context,
+	webhookConfig client.Object,
+	caBundle []byte,
+) error {
+	var names []string
+
+	switch wh := webhookConfig.(type) {
+	case *admissionregistrationv1.MutatingWebhookConfiguration:
+		for _, w := range wh.Webhooks {
+			names = append(names, w.Name)
+		}
+	case *admissionregistrationv1.ValidatingWebhookConfiguration:
+		for _, w := range wh.Webhooks {
+			names = append(names, w.Name)
+		}
+	default:
+		return fmt.Errorf("unsupported webhook type: %T", webhookConfig)
+	}
+
+	webhooks := make([]map[string]any, 0, len(names))
+	for _, name := range names {
+		webhooks = append(webhooks, map[string]any{
+			"name": name,
+			"clientConfig": map[string]any{
+				"caBundle": caBundle,
+			},
+		})
+	}
+
+	data, err := json.Marshal(map[string]any{"webhooks": webhooks})
+	if err != nil {
+		return fmt.Errorf("failed to marshal CA bundle patch for webhook %s: %w", webhookConfig.GetName(), err)
+	}
+
+	err = e.cli.Patch(ctx, webhookConfig, client.RawPatch(types.StrategicMergePatchType, data))
+	if err != nil {
+		return fmt.Errorf("failed to patch CA bundle of webhook %s: %w", webhookConfig.GetName(), err)
+	}
+
+	e.debugf("Webhook configuration %s CA bundle updated", webhookConfig.GetName())
+
+	return nil
+}
+
+// getWebhookClientConfigs returns the client configurations, keyed by webhook
+// name, of the mutating and validating webhook configurations with the given name.
+func (e *K3sEnv) getWebhookClientConfigs(
+	ctx context.Context,
+	webhookConfigName string,
+) (map[string]admissionregistrationv1.WebhookClientConfig, error) {
+	configs := make(map[string]admissionregistrationv1.WebhookClientConfig)
+	found := false
+
+	mutating := admissionregistrationv1.MutatingWebhookConfiguration{}
+	err := e.cli.Get(ctx, client.ObjectKey{Name: webhookConfigName}, &mutating)
+	switch {
+	case err == nil:
+		found = true
+		for _, wh := range mutating.Webhooks {
+			configs[wh.Name] = wh.ClientConfig
+		}
+	case !k8serr.IsNotFound(err):
+		return nil, fmt.Errorf("failed to get mutating webhook configuration %s: %w", webhookConfigName, err)
+	}
+
+	validating := admissionregistrationv1.ValidatingWebhookConfiguration{}
+	err = e.cli.Get(ctx, client.ObjectKey{Name: webhookConfigName}, &validating)
+	switch {
+	case err == nil:
+		found = true
+		for _, wh := range validating.Webhooks {
+			configs[wh.Name] = wh.ClientConfig
+		}
+	case !k8serr.IsNotFound(err):
+		return nil, fmt.Errorf("failed to get validating webhook configuration %s: %w", webhookConfigName, err)
+	}
+
+	if !found {
+		return nil, fmt.Errorf("webhook configuration %q not found", webhookConfigName)
+	}
+
+	return configs, nil
+}
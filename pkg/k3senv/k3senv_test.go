@@ -2,12 +2,19 @@ package k3senv_test
 
 import (
 	"context"
+	"encoding/base64"
+	"errors"
 	"io"
 	"net"
 	"net/http"
+	"os"
+	"path/filepath"
 	"strconv"
 	"testing"
+	"time"
 
+	"github.com/lburgazzoli/k3s-envtest/internal/cert"
+	"github.com/lburgazzoli/k3s-envtest/internal/resources"
 	"github.com/lburgazzoli/k3s-envtest/internal/testdata/v1alpha1"
 	"github.com/lburgazzoli/k3s-envtest/internal/testdata/v1beta1"
 	"github.com/lburgazzoli/k3s-envtest/pkg/k3senv"
@@ -18,7 +25,9 @@ import (
 
 	admissionv1 "k8s.io/api/admissionregistration/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/utils/ptr"
@@ -446,16 +455,14 @@ func TestInstallWebhooks_ConvertibleCRD_ConfiguresConversionEndpoint(t *testing.
 	err = env.Client().Get(ctx, client.ObjectKey{Name: crd.Name}, updatedCRD)
 	g.Expect(err).NotTo(HaveOccurred())
 
-	g.Expect(updatedCRD.Spec.Conversion).To(PointTo(MatchFields(IgnoreExtras, Fields{
-		"Strategy": Equal(apiextensionsv1.WebhookConverter),
-		"Webhook": PointTo(MatchFields(IgnoreExtras, Fields{
-			"ClientConfig": PointTo(MatchFields(IgnoreExtras, Fields{
-				"URL":      PointTo(ContainSubstring("https://host.containers.internal:9443/convert")),
-				"CABundle": Not(BeEmpty()),
-			})),
-			"ConversionReviewVersions": ContainElement("v1"),
-		})),
-	})))
+	g.Expect(resources.HasConversionWebhook(updatedCRD)).To(BeTrue())
+
+	conversionURL, err := resources.GetConversionURL(updatedCRD)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(conversionURL).To(ContainSubstring("https://host.containers.internal:9443/convert"))
+
+	g.Expect(updatedCRD.Spec.Conversion.Webhook.ClientConfig.CABundle).NotTo(BeEmpty())
+	g.Expect(updatedCRD.Spec.Conversion.Webhook.ConversionReviewVersions).To(ContainElement("v1"))
 }
 
 func TestInstallWebhooks_NonConvertibleCRD_SkipsConversion(t *testing.T) {
@@ -665,6 +672,775 @@ func TestInstallWebhooks_MultipleWebhooks_ConfiguresAll(t *testing.T) {
 	g.Expect(installedWebhook.Webhooks[0].ClientConfig.CABundle).To(Equal(installedWebhook.Webhooks[1].ClientConfig.CABundle))
 }
 
+func TestRotateCertificates_UpdatesWebhookCABundle(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	err := admissionv1.AddToScheme(scheme)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	webhook := newTestValidatingWebhook("test-rotate-webhook", testWebhookValidatePath)
+
+	env, err := k3senv.New(
+		k3senv.WithScheme(scheme),
+		k3senv.WithObjects(webhook),
+		k3senv.WithCertPath(t.TempDir()),
+		k3senv.WithWebhookCheckReadiness(false),
+	)
+	g.Expect(err).NotTo(HaveOccurred())
+	t.Cleanup(func() {
+		_ = env.Stop(ctx)
+	})
+
+	err = env.Start(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	err = env.InstallWebhooks(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	oldCABundle := env.CABundle()
+
+	err = env.RotateCertificates(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(env.CABundle()).NotTo(Equal(oldCABundle))
+
+	installedWebhook := &admissionv1.ValidatingWebhookConfiguration{}
+	err = env.Client().Get(ctx, client.ObjectKey{Name: webhook.Name}, installedWebhook)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(installedWebhook.Webhooks[0].ClientConfig.CABundle).To(Equal(env.CABundle()))
+}
+
+func TestRefreshCABundle_AllWebhooksUseNewCAAfterRotation(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	scheme := setupTestScheme(t)
+	g.Expect(admissionv1.AddToScheme(scheme)).To(Succeed())
+
+	crd := newTestCRDWithConversion()
+	validating := newTestValidatingWebhook("test-refresh-validating", testWebhookValidatePath)
+	mutating := newTestMutatingWebhook("test-refresh-mutating", testWebhookMutatePath)
+
+	env, err := k3senv.New(
+		k3senv.WithScheme(scheme),
+		k3senv.WithObjects(crd, validating, mutating),
+		k3senv.WithCertPath(t.TempDir()),
+		k3senv.WithWebhookCheckReadiness(false),
+	)
+	g.Expect(err).NotTo(HaveOccurred())
+	t.Cleanup(func() {
+		_ = env.Stop(ctx)
+	})
+
+	err = env.Start(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	err = env.InstallWebhooks(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	oldCABundle := env.CABundle()
+
+	err = env.RotateCertificates(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	newCABundle := env.CABundle()
+	g.Expect(newCABundle).NotTo(Equal(oldCABundle))
+
+	installedValidating := &admissionv1.ValidatingWebhookConfiguration{}
+	err = env.Client().Get(ctx, client.ObjectKey{Name: validating.Name}, installedValidating)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(installedValidating.Webhooks[0].ClientConfig.CABundle).To(Equal(newCABundle))
+
+	installedMutating := &admissionv1.MutatingWebhookConfiguration{}
+	err = env.Client().Get(ctx, client.ObjectKey{Name: mutating.Name}, installedMutating)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(installedMutating.Webhooks[0].ClientConfig.CABundle).To(Equal(newCABundle))
+
+	installedCRD := &apiextensionsv1.CustomResourceDefinition{}
+	err = env.Client().Get(ctx, client.ObjectKey{Name: crd.Name}, installedCRD)
+	g.Expect(err).NotTo(HaveOccurred())
+	newCACert, err := base64.StdEncoding.DecodeString(string(newCABundle))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(installedCRD.Spec.Conversion.Webhook.ClientConfig.CABundle).To(Equal(newCACert))
+	g.Expect(installedCRD.Spec.Conversion.Webhook.ClientConfig.URL).To(
+		PointTo(ContainSubstring("https://host.containers.internal:9443/convert")))
+}
+
+func TestRefreshCABundle_BeforeStart(t *testing.T) {
+	g := NewWithT(t)
+
+	env, err := k3senv.New()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	err = env.RefreshCABundle(context.Background())
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("call Start() first"))
+}
+
+func TestRotateCertificates_BeforeStart(t *testing.T) {
+	g := NewWithT(t)
+
+	env, err := k3senv.New()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	err = env.RotateCertificates(context.Background())
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("call Start() first"))
+}
+
+func TestGetWebhookURL(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	err := admissionv1.AddToScheme(scheme)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	validating := newTestValidatingWebhook("test-url-validating", testWebhookValidatePath)
+	mutating := newTestMutatingWebhook("test-url-mutating", testWebhookMutatePath)
+
+	env, err := k3senv.New(
+		k3senv.WithScheme(scheme),
+		k3senv.WithObjects(validating, mutating),
+		k3senv.WithCertPath(t.TempDir()),
+		k3senv.WithWebhookCheckReadiness(false),
+	)
+	g.Expect(err).NotTo(HaveOccurred())
+	t.Cleanup(func() {
+		_ = env.Stop(ctx)
+	})
+
+	err = env.Start(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	err = env.InstallWebhooks(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	url, err := env.GetWebhookURL(ctx, validating.Name, validating.Webhooks[0].Name)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(url).To(Equal("https://host.containers.internal:9443" + testWebhookValidatePath))
+
+	url, err = env.GetWebhookURL(ctx, mutating.Name, mutating.Webhooks[0].Name)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(url).To(Equal("https://host.containers.internal:9443" + testWebhookMutatePath))
+
+	_, err = env.GetWebhookURL(ctx, "missing-config", "validate.example.com")
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring(`webhook configuration "missing-config" not found`))
+
+	_, err = env.GetWebhookURL(ctx, validating.Name, "missing.example.com")
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring(`webhook "missing.example.com" not found`))
+}
+
+func TestUpdateWebhookCA_PatchesOnlyCABundle(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	err := admissionv1.AddToScheme(scheme)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	webhook := newTestMutatingWebhook("test-update-ca-webhook", testWebhookMutatePath)
+
+	env, err := k3senv.New(
+		k3senv.WithScheme(scheme),
+		k3senv.WithObjects(webhook),
+		k3senv.WithCertPath(t.TempDir()),
+		k3senv.WithWebhookCheckReadiness(false),
+	)
+	g.Expect(err).NotTo(HaveOccurred())
+	t.Cleanup(func() {
+		_ = env.Stop(ctx)
+	})
+
+	err = env.Start(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	err = env.InstallWebhooks(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	before := &admissionv1.MutatingWebhookConfiguration{}
+	err = env.Client().Get(ctx, client.ObjectKey{Name: webhook.Name}, before)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	newCA, err := cert.New(t.TempDir(), time.Hour, []string{"localhost"})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	err = env.UpdateWebhookCA(ctx, webhook.Name, newCA.CABundle())
+	g.Expect(err).NotTo(HaveOccurred())
+
+	after := &admissionv1.MutatingWebhookConfiguration{}
+	err = env.Client().Get(ctx, client.ObjectKey{Name: webhook.Name}, after)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(after.Webhooks).To(HaveLen(1))
+	g.Expect(after.Webhooks[0].ClientConfig.CABundle).To(Equal(newCA.CABundle()))
+	g.Expect(after.Webhooks[0].ClientConfig.URL).To(Equal(before.Webhooks[0].ClientConfig.URL))
+	g.Expect(after.Webhooks[0].Rules).To(Equal(before.Webhooks[0].Rules))
+	g.Expect(after.Webhooks[0].FailurePolicy).To(Equal(before.Webhooks[0].FailurePolicy))
+
+	err = env.UpdateWebhookCA(ctx, "missing-config", newCA.CABundle())
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring(`webhook configuration "missing-config" not found`))
+}
+
+func TestReinstallWebhooks_RestoresClientConfig(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	err := admissionv1.AddToScheme(scheme)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	webhook := newTestValidatingWebhook("test-reinstall-webhook", testWebhookValidatePath)
+
+	env, err := k3senv.New(
+		k3senv.WithScheme(scheme),
+		k3senv.WithObjects(webhook),
+		k3senv.WithCertPath(t.TempDir()),
+		k3senv.WithWebhookCheckReadiness(false),
+	)
+	g.Expect(err).NotTo(HaveOccurred())
+	t.Cleanup(func() {
+		_ = env.Stop(ctx)
+	})
+
+	err = env.Start(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	err = env.InstallWebhooks(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// Simulate a stale client configuration
+	installedWebhook := &admissionv1.ValidatingWebhookConfiguration{}
+	err = env.Client().Get(ctx, client.ObjectKey{Name: webhook.Name}, installedWebhook)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	installedWebhook.Webhooks[0].ClientConfig.URL = ptr.To("https://stale.example.com:9443" + testWebhookValidatePath)
+	installedWebhook.Webhooks[0].ClientConfig.CABundle = nil
+	err = env.Client().Update(ctx, installedWebhook)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	err = env.ReinstallWebhooks(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	reinstalledWebhook := &admissionv1.ValidatingWebhookConfiguration{}
+	err = env.Client().Get(ctx, client.ObjectKey{Name: webhook.Name}, reinstalledWebhook)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(reinstalledWebhook.Webhooks).To(HaveLen(1))
+	g.Expect(reinstalledWebhook.Webhooks[0].ClientConfig.URL).To(
+		PointTo(Equal("https://host.containers.internal:9443" + testWebhookValidatePath)))
+	g.Expect(reinstalledWebhook.Webhooks[0].ClientConfig.CABundle).To(Equal(env.CABundle()))
+	g.Expect(reinstalledWebhook.Webhooks[0].Rules).To(Equal(webhook.Webhooks[0].Rules))
+	g.Expect(reinstalledWebhook.Webhooks[0].FailurePolicy).To(Equal(webhook.Webhooks[0].FailurePolicy))
+}
+
+func TestReinstallWebhooks_BeforeStart(t *testing.T) {
+	g := NewWithT(t)
+
+	env, err := k3senv.New()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	err = env.ReinstallWebhooks(context.Background())
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("call Start() first"))
+}
+
+func TestUninstallWebhooks_DeletesOnlyInstalledWebhooks(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	err := admissionv1.AddToScheme(scheme)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	validating := newTestValidatingWebhook("test-uninstall-validating", testWebhookValidatePath)
+	mutating := newTestMutatingWebhook("test-uninstall-mutating", testWebhookValidatePath)
+
+	env, err := k3senv.New(
+		k3senv.WithScheme(scheme),
+		k3senv.WithObjects(validating, mutating),
+		k3senv.WithCertPath(t.TempDir()),
+		k3senv.WithWebhookCheckReadiness(false),
+	)
+	g.Expect(err).NotTo(HaveOccurred())
+	t.Cleanup(func() {
+		_ = env.Stop(ctx)
+	})
+
+	err = env.Start(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	preExisting := newTestValidatingWebhook("test-pre-existing", testWebhookValidatePath)
+	err = env.Client().Create(ctx, preExisting)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	err = env.InstallWebhooks(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	err = env.UninstallWebhooks(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	err = env.Client().Get(ctx, client.ObjectKey{Name: validating.Name}, &admissionv1.ValidatingWebhookConfiguration{})
+	g.Expect(k8serr.IsNotFound(err)).To(BeTrue())
+
+	err = env.Client().Get(ctx, client.ObjectKey{Name: mutating.Name}, &admissionv1.MutatingWebhookConfiguration{})
+	g.Expect(k8serr.IsNotFound(err)).To(BeTrue())
+
+	err = env.Client().Get(ctx, client.ObjectKey{Name: preExisting.Name}, &admissionv1.ValidatingWebhookConfiguration{})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// Uninstalling again is a no-op
+	err = env.UninstallWebhooks(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+}
+
+func TestUninstallWebhook_DeletesSingleWebhook(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	err := admissionv1.AddToScheme(scheme)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	first := newTestValidatingWebhook("test-uninstall-first", testWebhookValidatePath)
+	second := newTestValidatingWebhook("test-uninstall-second", testWebhookValidatePath)
+
+	env, err := k3senv.New(
+		k3senv.WithScheme(scheme),
+		k3senv.WithObjects(first, second),
+		k3senv.WithCertPath(t.TempDir()),
+		k3senv.WithWebhookCheckReadiness(false),
+	)
+	g.Expect(err).NotTo(HaveOccurred())
+	t.Cleanup(func() {
+		_ = env.Stop(ctx)
+	})
+
+	err = env.Start(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	err = env.InstallWebhooks(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	err = env.UninstallWebhook(ctx, first.Name)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	err = env.Client().Get(ctx, client.ObjectKey{Name: first.Name}, &admissionv1.ValidatingWebhookConfiguration{})
+	g.Expect(k8serr.IsNotFound(err)).To(BeTrue())
+
+	err = env.Client().Get(ctx, client.ObjectKey{Name: second.Name}, &admissionv1.ValidatingWebhookConfiguration{})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// Already deleted or unknown webhooks are ignored
+	err = env.UninstallWebhook(ctx, first.Name)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	err = env.UninstallWebhook(ctx, "unknown")
+	g.Expect(err).NotTo(HaveOccurred())
+}
+
+func TestUninstallWebhooks_BeforeStart(t *testing.T) {
+	g := NewWithT(t)
+
+	env, err := k3senv.New()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	err = env.UninstallWebhooks(context.Background())
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("call Start() first"))
+}
+
+func TestMustStart(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	var env *k3senv.K3sEnv
+
+	t.Run("start", func(t *testing.T) {
+		var err error
+
+		env, err = k3senv.New(k3senv.WithCertPath(t.TempDir()))
+		g.Expect(err).NotTo(HaveOccurred())
+
+		env.MustStart(ctx, t)
+
+		g.Expect(env.IsStarted()).To(BeTrue())
+		g.Expect(env.Client()).NotTo(BeNil())
+	})
+
+	// Stop has been registered as a cleanup of the subtest
+	g.Expect(env.IsStopped()).To(BeTrue())
+}
+
+func TestLifecycleState(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	env, err := k3senv.New(k3senv.WithCertPath(t.TempDir()))
+	g.Expect(err).NotTo(HaveOccurred())
+	t.Cleanup(func() {
+		_ = env.Stop(ctx)
+	})
+
+	g.Expect(env.IsStarting()).To(BeFalse())
+	g.Expect(env.IsStarted()).To(BeFalse())
+	g.Expect(env.IsStopped()).To(BeFalse())
+
+	err = env.Start(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(env.IsStarting()).To(BeFalse())
+	g.Expect(env.IsStarted()).To(BeTrue())
+	g.Expect(env.IsStopped()).To(BeFalse())
+
+	err = env.Start(ctx)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("already started"))
+
+	err = env.Stop(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(env.IsStarting()).To(BeFalse())
+	g.Expect(env.IsStarted()).To(BeFalse())
+	g.Expect(env.IsStopped()).To(BeTrue())
+
+	err = env.Reset(ctx)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("call Start() first"))
+}
+
+func TestLifecycleState_FailedStart(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	env, err := k3senv.New(
+		k3senv.WithCertPath(t.TempDir()),
+		k3senv.WithK3sImage("invalid image reference"),
+	)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	err = env.Start(ctx)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(env.IsFailed()).To(BeTrue())
+	g.Expect(env.IsStarted()).To(BeFalse())
+
+	err = env.Start(ctx)
+	g.Expect(err).To(MatchError(ContainSubstring("call Stop() first")))
+
+	g.Expect(env.Stop(ctx)).To(Succeed())
+	g.Expect(env.IsFailed()).To(BeFalse())
+	g.Expect(env.IsStopped()).To(BeTrue())
+
+	err = env.Start(ctx)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err).NotTo(MatchError(ContainSubstring("call Stop() first")))
+}
+
+func TestLifecycleHooks_Order(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	var calls []string
+	env, err := k3senv.New(
+		k3senv.WithCertPath(t.TempDir()),
+		k3senv.WithPreStart(func(context.Context) error {
+			calls = append(calls, "pre-start")
+			return nil
+		}),
+		k3senv.WithPostStart(func(_ context.Context, env *k3senv.K3sEnv) error {
+			calls = append(calls, "post-start")
+			if !env.IsStarted() || env.Client() == nil {
+				return errors.New("environment not initialized")
+			}
+			return nil
+		}),
+		k3senv.WithPreStop(func(context.Context) error {
+			calls = append(calls, "pre-stop")
+			return nil
+		}),
+	)
+	g.Expect(err).NotTo(HaveOccurred())
+	t.Cleanup(func() {
+		_ = env.Stop(ctx)
+	})
+
+	err = env.Start(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	env.AddTeardown(func(context.Context) error {
+		calls = append(calls, "teardown")
+		return nil
+	})
+
+	err = env.Stop(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(calls).To(Equal([]string{"pre-start", "post-start", "pre-stop", "teardown"}))
+}
+
+func TestReset_RecreatesCRDs(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	g.Expect(apiextensionsv1.AddToScheme(scheme)).To(Succeed())
+
+	crd := newTestCRDNonConvertible()
+
+	env, err := k3senv.New(
+		k3senv.WithScheme(scheme),
+		k3senv.WithObjects(crd),
+		k3senv.WithCertPath(t.TempDir()),
+	)
+	g.Expect(err).NotTo(HaveOccurred())
+	t.Cleanup(func() {
+		_ = env.Stop(ctx)
+	})
+
+	err = env.Start(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	cr := &unstructured.Unstructured{}
+	cr.SetAPIVersion("example.com/v1")
+	cr.SetKind("NonConvertible")
+	cr.SetNamespace("default")
+	cr.SetName("test-reset")
+
+	err = env.Client().Create(ctx, cr)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	containerID := env.ContainerID()
+
+	err = env.Reset(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(env.ContainerID()).To(Equal(containerID))
+
+	updatedCRD := &apiextensionsv1.CustomResourceDefinition{}
+	err = env.Client().Get(ctx, client.ObjectKey{Name: crd.Name}, updatedCRD)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(resources.IsCRDEstablished(updatedCRD)).To(BeTrue())
+
+	err = env.Client().Get(ctx, client.ObjectKeyFromObject(cr), cr.DeepCopy())
+	g.Expect(k8serr.IsNotFound(err)).To(BeTrue())
+}
+
+func TestReset_BeforeStart(t *testing.T) {
+	g := NewWithT(t)
+
+	env, err := k3senv.New()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	err = env.Reset(context.Background())
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("call Start() first"))
+}
+
+func TestRestart_ReusesContainer(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	g.Expect(apiextensionsv1.AddToScheme(scheme)).To(Succeed())
+
+	crd := newTestCRDNonConvertible()
+
+	env, err := k3senv.New(
+		k3senv.WithScheme(scheme),
+		k3senv.WithObjects(crd),
+	)
+	g.Expect(err).NotTo(HaveOccurred())
+	t.Cleanup(func() {
+		_ = env.Stop(ctx)
+	})
+
+	err = env.Start(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	containerID := env.ContainerID()
+	caBundle := env.CABundle()
+
+	teardownCalls := 0
+	env.AddTeardown(func(context.Context) error {
+		teardownCalls++
+		return nil
+	})
+
+	err = env.Restart(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(teardownCalls).To(Equal(1))
+	g.Expect(env.ContainerID()).To(Equal(containerID))
+	g.Expect(env.CABundle()).NotTo(Equal(caBundle))
+	g.Expect(env.CertificatePaths().CAFile).To(BeAnExistingFile())
+
+	updatedCRD := &apiextensionsv1.CustomResourceDefinition{}
+	err = env.Client().Get(ctx, client.ObjectKey{Name: crd.Name}, updatedCRD)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(resources.IsCRDEstablished(updatedCRD)).To(BeTrue())
+
+	certDir := env.CertificatePaths().Dir
+
+	err = env.Stop(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(teardownCalls).To(Equal(1))
+	g.Expect(certDir).NotTo(BeADirectory())
+}
+
+func TestRestart_BeforeStart(t *testing.T) {
+	g := NewWithT(t)
+
+	env, err := k3senv.New()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	err = env.Restart(context.Background())
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("call Start() first"))
+}
+
+func TestPreExistingCluster_InstallsManifests(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	// the pre-existing cluster is provided by another environment
+	cluster, err := k3senv.New()
+	g.Expect(err).NotTo(HaveOccurred())
+	t.Cleanup(func() {
+		_ = cluster.Stop(ctx)
+	})
+
+	err = cluster.Start(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	kubeconfig, err := cluster.GetKubeconfig(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	kubeconfigPath := filepath.Join(t.TempDir(), "kubeconfig")
+	g.Expect(os.WriteFile(kubeconfigPath, kubeconfig, 0o600)).To(Succeed())
+
+	scheme := runtime.NewScheme()
+	g.Expect(apiextensionsv1.AddToScheme(scheme)).To(Succeed())
+
+	crd := newTestCRDNonConvertible()
+
+	env, err := k3senv.New(
+		k3senv.WithPreExistingCluster(kubeconfigPath),
+		k3senv.WithScheme(scheme),
+		k3senv.WithObjects(crd),
+	)
+	g.Expect(err).NotTo(HaveOccurred())
+	t.Cleanup(func() {
+		_ = env.Stop(ctx)
+	})
+
+	err = env.Start(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(env.ContainerID()).To(BeEmpty())
+	g.Expect(env.CertificatePaths().CAFile).To(BeAnExistingFile())
+
+	loaded, err := env.GetKubeconfig(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(loaded).To(Equal(kubeconfig))
+
+	installed := &apiextensionsv1.CustomResourceDefinition{}
+	err = env.Client().Get(ctx, client.ObjectKey{Name: crd.Name}, installed)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(resources.IsCRDEstablished(installed)).To(BeTrue())
+
+	certDir := env.CertificatePaths().Dir
+
+	err = env.Stop(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(certDir).NotTo(BeADirectory())
+
+	// the cluster is left running
+	err = cluster.Client().List(ctx, &unstructured.UnstructuredList{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "NamespaceList",
+	}})
+	g.Expect(err).NotTo(HaveOccurred())
+}
+
+func TestInstallValidatingAdmissionPolicies(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	err := admissionv1.AddToScheme(scheme)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	policy := &admissionv1.ValidatingAdmissionPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-policy",
+		},
+		Spec: admissionv1.ValidatingAdmissionPolicySpec{
+			FailurePolicy: ptr.To(admissionv1.Fail),
+			MatchConstraints: &admissionv1.MatchResources{
+				ResourceRules: []admissionv1.NamedRuleWithOperations{
+					{
+						RuleWithOperations: admissionv1.RuleWithOperations{
+							Operations: []admissionv1.OperationType{admissionv1.Create},
+							Rule: admissionv1.Rule{
+								APIGroups:   []string{""},
+								APIVersions: []string{"v1"},
+								Resources:   []string{"configmaps"},
+							},
+						},
+					},
+				},
+			},
+			Validations: []admissionv1.Validation{
+				{Expression: "object.metadata.name != 'forbidden'"},
+			},
+		},
+	}
+
+	binding := &admissionv1.ValidatingAdmissionPolicyBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-policy-binding",
+		},
+		Spec: admissionv1.ValidatingAdmissionPolicyBindingSpec{
+			PolicyName:        policy.Name,
+			ValidationActions: []admissionv1.ValidationAction{admissionv1.Deny},
+		},
+	}
+
+	env, err := k3senv.New(
+		k3senv.WithScheme(scheme),
+		k3senv.WithObjects(policy, binding),
+		k3senv.WithCertPath(t.TempDir()),
+	)
+	g.Expect(err).NotTo(HaveOccurred())
+	t.Cleanup(func() {
+		_ = env.Stop(ctx)
+	})
+
+	err = env.Start(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(env.ValidatingAdmissionPolicies()).To(HaveLen(1))
+	g.Expect(env.ValidatingAdmissionPolicyBindings()).To(HaveLen(1))
+
+	err = env.InstallValidatingAdmissionPolicies(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	installedPolicy := &admissionv1.ValidatingAdmissionPolicy{}
+	err = env.Client().Get(ctx, client.ObjectKey{Name: policy.Name}, installedPolicy)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(installedPolicy.Spec.Validations).To(HaveLen(1))
+
+	installedBinding := &admissionv1.ValidatingAdmissionPolicyBinding{}
+	err = env.Client().Get(ctx, client.ObjectKey{Name: binding.Name}, installedBinding)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(installedBinding.Spec.PolicyName).To(Equal(policy.Name))
+}
+
 // Validation Tests
 
 func TestNew_InvalidPort(t *testing.T) {
@@ -0,0 +1,252 @@
+package k3senv
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/lburgazzoli/k3s-envtest/internal/gvk"
+	"github.com/lburgazzoli/k3s-envtest/internal/resources"
+	"github.com/lburgazzoli/k3s-envtest/internal/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiservervalidation "k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/uuid"
+)
+
+// DryRun validates the given objects against the API server without persisting
+// them, by creating them in dry-run mode. Objects already present in the
+// cluster are validated as updates instead, and CRDs are always validated as
+// updates too when present, so that changes to their conversion are checked.
+// The errors of all the objects are returned joined.
+func (e *K3sEnv) DryRun(ctx context.Context, objs ...client.Object) error {
+	if e.cli == nil {
+		return errors.New("cluster not started - call Start() first")
+	}
+
+	var errs []error
+
+	for _, obj := range objs {
+		if err := e.dryRun(ctx, obj); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (e *K3sEnv) dryRun(ctx context.Context, obj client.Object) error {
+	// the client updates the object with the response, so work on a copy
+	// to leave the given object untouched
+	copied, ok := obj.DeepCopyObject().(client.Object)
+	if !ok {
+		return fmt.Errorf("failed to copy object %T", obj)
+	}
+
+	obj = copied
+
+	if err := resources.EnsureGroupVersionKind(e.options.Scheme, obj); err != nil {
+		return fmt.Errorf("failed to set GVK for %s: %w", obj.GetName(), err)
+	}
+
+	// the GVK may be cleared when the object is updated with the response
+	objGVK := obj.GetObjectKind().GroupVersionKind()
+	ref := resources.FormatObjectReference(obj)
+	isCRD := resources.IsCRD(obj)
+
+	err := e.cli.Create(ctx, obj, client.DryRunAll)
+	switch {
+	case k8serr.IsAlreadyExists(err):
+		return e.dryRunUpdate(ctx, obj, objGVK, ref)
+	case err != nil:
+		return fmt.Errorf("dry-run create of %s failed: %w", ref, err)
+	case isCRD:
+		return e.dryRunUpdate(ctx, obj, objGVK, ref)
+	default:
+		return nil
+	}
+}
+
+// dryRunUpdate validates the update of obj in dry-run mode, if it exists.
+func (e *K3sEnv) dryRunUpdate(ctx context.Context, obj client.Object, objGVK schema.GroupVersionKind, ref string) error {
+	current := unstructured.Unstructured{}
+	current.SetGroupVersionKind(objGVK)
+
+	if err := e.cli.Get(ctx, client.ObjectKeyFromObject(obj), &current); err != nil {
+		if k8serr.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get %s: %w", ref, err)
+	}
+
+	obj.GetObjectKind().SetGroupVersionKind(objGVK)
+	obj.SetResourceVersion(current.GetResourceVersion())
+
+	if err := e.cli.Update(ctx, obj, client.DryRunAll); err != nil {
+		return fmt.Errorf("dry-run update of %s failed: %w", ref, err)
+	}
+
+	return nil
+}
+
+// ValidateObject validates obj against the OpenAPI schema of the version of its
+// CRD installed in the cluster, without sending it to the API server, e.g. to
+// check that the output of a mutating webhook is valid. Fields unknown to the
+// schema are not reported, as the API server prunes them. It returns nil if
+// the type of obj is not defined by a CRD, or the CRD version has no schema.
+func (e *K3sEnv) ValidateObject(ctx context.Context, obj *unstructured.Unstructured) error {
+	if e.cli == nil {
+		return errors.New("cluster not started - call Start() first")
+	}
+
+	objGVK := obj.GroupVersionKind()
+
+	mapping, err := e.mapper.RESTMapping(objGVK.GroupKind(), objGVK.Version)
+	if err != nil {
+		return fmt.Errorf("failed to get REST mapping for %s: %w", objGVK, err)
+	}
+
+	name := mapping.Resource.GroupResource().String()
+
+	crd, err := e.getInstalledCRD(ctx, name)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	idx := slices.IndexFunc(crd.Spec.Versions, func(v apiextensionsv1.CustomResourceDefinitionVersion) bool {
+		return v.Name == objGVK.Version
+	})
+	if idx < 0 {
+		return fmt.Errorf("version %s not found in CRD %s", objGVK.Version, name)
+	}
+
+	version := crd.Spec.Versions[idx]
+	if version.Schema == nil || version.Schema.OpenAPIV3Schema == nil {
+		return nil
+	}
+
+	internal := apiextensions.JSONSchemaProps{}
+
+	err = apiextensionsv1.Convert_v1_JSONSchemaProps_To_apiextensions_JSONSchemaProps(
+		version.Schema.OpenAPIV3Schema, &internal, nil)
+	if err != nil {
+		return fmt.Errorf("failed to convert schema of CRD %s version %s: %w", name, objGVK.Version, err)
+	}
+
+	validator, _, err := apiservervalidation.NewSchemaValidator(&internal)
+	if err != nil {
+		return fmt.Errorf("failed to create validator for CRD %s version %s: %w", name, objGVK.Version, err)
+	}
+
+	if errs := apiservervalidation.ValidateCustomResource(nil, obj.UnstructuredContent(), validator); len(errs) > 0 {
+		return fmt.Errorf("%s is invalid: %w", resources.FormatObjectReference(obj), errs.ToAggregate())
+	}
+
+	return nil
+}
+
+// SimulateAdmission sends an AdmissionReview for the given operation on obj
+// directly to the webhook server endpoint at path, bypassing the API server so
+// that the cluster state is not modified, and returns the admission response.
+// For Update operations, obj is sent as both the new and the old object, and
+// for Delete operations as the old object only.
+//
+// The webhook server must be running on the webhook port, see WebhookServer.
+func (e *K3sEnv) SimulateAdmission(
+	ctx context.Context,
+	path string,
+	obj runtime.Object,
+	operation admissionv1.Operation,
+) (*admissionv1.AdmissionResponse, error) {
+	if e.certData == nil {
+		return nil, errors.New("certificates not generated - call Start() first")
+	}
+
+	objGVK, err := gvk.FromObject(e.options.Scheme, obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve GVK of object: %w", err)
+	}
+
+	// the webhook decodes the object by its apiVersion and kind, so make
+	// sure they are set without modifying the given object
+	obj = obj.DeepCopyObject()
+	obj.GetObjectKind().SetGroupVersionKind(objGVK)
+
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to access object metadata: %w", err)
+	}
+
+	raw, err := json.Marshal(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal object: %w", err)
+	}
+
+	request := &admissionv1.AdmissionRequest{
+		UID:       uuid.NewUUID(),
+		Kind:      metav1.GroupVersionKind{Group: objGVK.Group, Version: objGVK.Version, Kind: objGVK.Kind},
+		Name:      accessor.GetName(),
+		Namespace: accessor.GetNamespace(),
+		Operation: operation,
+	}
+
+	if e.mapper != nil {
+		if mapping, err := e.mapper.RESTMapping(objGVK.GroupKind(), objGVK.Version); err == nil {
+			request.Resource = metav1.GroupVersionResource{
+				Group:    mapping.Resource.Group,
+				Version:  mapping.Resource.Version,
+				Resource: mapping.Resource.Resource,
+			}
+		}
+	}
+
+	switch operation {
+	case admissionv1.Delete:
+		request.OldObject = runtime.RawExtension{Raw: raw}
+	case admissionv1.Update:
+		request.Object = runtime.RawExtension{Raw: raw}
+		request.OldObject = runtime.RawExtension{Raw: raw}
+	default:
+		request.Object = runtime.RawExtension{Raw: raw}
+	}
+
+	webhookClient, err := webhook.NewClient(
+		"127.0.0.1",
+		e.options.Webhook.Port,
+		webhook.WithClientCACert(e.certData.CACert),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create webhook client: %w", err)
+	}
+
+	review, err := webhookClient.Call(ctx, path, admissionv1.AdmissionReview{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: admissionv1.SchemeGroupVersion.String(),
+			Kind:       "AdmissionReview",
+		},
+		Request: request,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to call webhook %s: %w", path, err)
+	}
+
+	if review.Response == nil {
+		return nil, fmt.Errorf("webhook %s returned no admission response", path)
+	}
+
+	return review.Response, nil
+}
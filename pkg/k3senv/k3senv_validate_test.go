@@ -0,0 +1,239 @@
+package k3senv_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lburgazzoli/k3s-envtest/pkg/k3senv"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlwebhook "sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	admissionapiv1 "k8s.io/api/admission/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestSimulateAdmission(t *testing.T) {
+	g := NewWithT(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	port, err := k3senv.FindAvailablePort()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	env, err := k3senv.New(k3senv.WithWebhookPort(port))
+	g.Expect(err).NotTo(HaveOccurred())
+	t.Cleanup(func() {
+		_ = env.Stop(context.Background())
+	})
+
+	cm := &unstructured.Unstructured{}
+	cm.SetAPIVersion("v1")
+	cm.SetKind("ConfigMap")
+	cm.SetNamespace(metav1.NamespaceDefault)
+	cm.SetName("forbidden")
+
+	_, err = env.SimulateAdmission(ctx, "/validate", cm, admissionapiv1.Create)
+	g.Expect(err).To(MatchError(ContainSubstring("call Start() first")))
+
+	err = env.Start(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	var received atomic.Pointer[admission.Request]
+
+	server := env.WebhookServer()
+	server.Register("/validate", &ctrlwebhook.Admission{
+		Handler: admission.HandlerFunc(func(_ context.Context, req admission.Request) admission.Response {
+			received.Store(&req)
+
+			if req.Name == "forbidden" {
+				return admission.Denied("forbidden name")
+			}
+
+			return admission.Allowed("")
+		}),
+	})
+
+	go func() {
+		_ = server.Start(ctx)
+	}()
+
+	var resp *admissionapiv1.AdmissionResponse
+	g.Eventually(func() error {
+		resp, err = env.SimulateAdmission(ctx, "/validate", cm, admissionapiv1.Create)
+		return err
+	}).WithTimeout(30 * time.Second).Should(Succeed())
+
+	g.Expect(resp.Allowed).To(BeFalse())
+	g.Expect(resp.Result.Message).To(ContainSubstring("forbidden name"))
+
+	req := received.Load()
+	g.Expect(req).NotTo(BeNil())
+	g.Expect(req.Kind.Kind).To(Equal("ConfigMap"))
+	g.Expect(req.Resource.Resource).To(Equal("configmaps"))
+	g.Expect(req.Namespace).To(Equal(metav1.NamespaceDefault))
+
+	cm.SetName("allowed")
+
+	resp, err = env.SimulateAdmission(ctx, "/validate", cm, admissionapiv1.Create)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(resp.Allowed).To(BeTrue())
+
+	// the cluster state is not modified
+	err = env.Client().Get(ctx, client.ObjectKeyFromObject(cm), cm.DeepCopy())
+	g.Expect(k8serr.IsNotFound(err)).To(BeTrue())
+}
+
+func TestDryRun(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	newConfigMap := func(name string) *unstructured.Unstructured {
+		cm := &unstructured.Unstructured{}
+		cm.SetAPIVersion("v1")
+		cm.SetKind("ConfigMap")
+		cm.SetNamespace(metav1.NamespaceDefault)
+		cm.SetName(name)
+
+		return cm
+	}
+
+	scheme := runtime.NewScheme()
+	g.Expect(apiextensionsv1.AddToScheme(scheme)).To(Succeed())
+
+	env, err := k3senv.New(
+		k3senv.WithScheme(scheme),
+		k3senv.WithObjects(newTestCRDNonConvertible()),
+	)
+	g.Expect(err).NotTo(HaveOccurred())
+	t.Cleanup(func() {
+		_ = env.Stop(ctx)
+	})
+
+	err = env.DryRun(ctx, newConfigMap("dry-run"))
+	g.Expect(err).To(MatchError(ContainSubstring("call Start() first")))
+
+	err = env.Start(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	t.Run("Valid object is not persisted", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(env.DryRun(ctx, newConfigMap("dry-run"))).To(Succeed())
+
+		err := env.Client().Get(ctx, client.ObjectKeyFromObject(newConfigMap("dry-run")), newConfigMap(""))
+		g.Expect(k8serr.IsNotFound(err)).To(BeTrue(), "expected not found, got: %v", err)
+	})
+
+	t.Run("Existing object is validated as update", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(env.Client().Create(ctx, newConfigMap("dry-run-existing"))).To(Succeed())
+
+		cm := newConfigMap("dry-run-existing")
+		g.Expect(unstructured.SetNestedField(cm.Object, "value", "data", "key")).To(Succeed())
+		g.Expect(env.DryRun(ctx, cm)).To(Succeed())
+
+		found := newConfigMap("")
+		g.Expect(env.Client().Get(ctx, client.ObjectKeyFromObject(cm), found)).To(Succeed())
+		g.Expect(found.Object).NotTo(HaveKey("data"))
+	})
+
+	t.Run("Installed CRD", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(env.DryRun(ctx, newTestCRDNonConvertible())).To(Succeed())
+	})
+
+	t.Run("Invalid objects errors are joined", func(t *testing.T) {
+		g := NewWithT(t)
+
+		err := env.DryRun(ctx, newConfigMap("Invalid_Name"), newConfigMap("valid"), newConfigMap("Another_Invalid"))
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("Invalid_Name"))
+		g.Expect(err.Error()).To(ContainSubstring("Another_Invalid"))
+		g.Expect(err.Error()).NotTo(ContainSubstring("ConfigMap default/valid"))
+	})
+}
+
+func TestValidateObject(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	crd := newTestCRDNonConvertible()
+	crd.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties = map[string]apiextensionsv1.JSONSchemaProps{
+		"spec": {
+			Type:     "object",
+			Required: []string{"name"},
+			Properties: map[string]apiextensionsv1.JSONSchemaProps{
+				"name":     {Type: "string"},
+				"replicas": {Type: "integer", Minimum: ptr.To(1.0)},
+			},
+		},
+	}
+
+	newObject := func(spec map[string]any) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion("example.com/v1")
+		obj.SetKind("NonConvertible")
+		obj.SetNamespace(metav1.NamespaceDefault)
+		obj.SetName("validated")
+		obj.Object["spec"] = spec
+
+		return obj
+	}
+
+	scheme := runtime.NewScheme()
+	g.Expect(apiextensionsv1.AddToScheme(scheme)).To(Succeed())
+
+	env, err := k3senv.New(
+		k3senv.WithScheme(scheme),
+		k3senv.WithObjects(crd),
+	)
+	g.Expect(err).NotTo(HaveOccurred())
+	t.Cleanup(func() {
+		_ = env.Stop(ctx)
+	})
+
+	err = env.ValidateObject(ctx, newObject(map[string]any{"name": "test"}))
+	g.Expect(err).To(MatchError(ContainSubstring("call Start() first")))
+
+	err = env.Start(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	t.Run("Valid object", func(t *testing.T) {
+		g := NewWithT(t)
+
+		err := env.ValidateObject(ctx, newObject(map[string]any{"name": "test", "replicas": int64(2)}))
+		g.Expect(err).NotTo(HaveOccurred())
+	})
+
+	t.Run("Invalid object", func(t *testing.T) {
+		g := NewWithT(t)
+
+		err := env.ValidateObject(ctx, newObject(map[string]any{"replicas": int64(0)}))
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("spec.name"))
+		g.Expect(err.Error()).To(ContainSubstring("spec.replicas"))
+	})
+
+	t.Run("Type without CRD", func(t *testing.T) {
+		g := NewWithT(t)
+
+		cm := &unstructured.Unstructured{}
+		cm.SetAPIVersion("v1")
+		cm.SetKind("ConfigMap")
+		cm.SetName("not-a-custom-resource")
+
+		g.Expect(env.ValidateObject(ctx, cm)).To(Succeed())
+	})
+}
@@ -0,0 +1,99 @@
+package k3senv
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/version"
+)
+
+// GetKubernetesVersion returns the version of the API server, e.g. to skip
+// tests requiring a more recent Kubernetes version. The version is fetched
+// from the /version endpoint on first call and cached.
+func (e *K3sEnv) GetKubernetesVersion(ctx context.Context) (*version.Info, error) {
+	e.versionMu.Lock()
+	defer e.versionMu.Unlock()
+
+	if e.kubernetesVersion != nil {
+		info := *e.kubernetesVersion
+		return &info, nil
+	}
+
+	dc, err := e.GetDiscoveryClient()
+	if err != nil {
+		return nil, err
+	}
+
+	// as dc.ServerVersion, which does not take a context
+	body, err := dc.RESTClient().Get().AbsPath("/version").Do(ctx).Raw()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get server version: %w", err)
+	}
+
+	info := version.Info{}
+	if err := json.Unmarshal(body, &info); err != nil {
+		return nil, fmt.Errorf("failed to decode server version: %w", err)
+	}
+
+	e.kubernetesVersion = &info
+
+	result := info
+
+	return &result, nil
+}
+
+// GetK3sVersion returns the version of k3s, e.g. "v1.32.9-k3s1", parsed from
+// the image tag of the running container on first call and cached. It is not
+// supported with a pre-existing cluster.
+func (e *K3sEnv) GetK3sVersion(ctx context.Context) (string, error) {
+	e.versionMu.Lock()
+	defer e.versionMu.Unlock()
+
+	if e.k3sVersion != "" {
+		return e.k3sVersion, nil
+	}
+
+	if !e.IsStarted() {
+		return "", errors.New("cluster not started - call Start() first")
+	}
+
+	if e.container == nil {
+		return "", errors.New("k3s version is not available with a pre-existing cluster")
+	}
+
+	info, err := e.container.Inspect(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to inspect k3s container: %w", err)
+	}
+
+	tag, err := imageTag(info.Config.Image)
+	if err != nil {
+		return "", err
+	}
+
+	e.k3sVersion = tag
+
+	return tag, nil
+}
+
+// imageTag returns the tag of the given image reference, e.g. "v1.32.9-k3s1"
+// for "rancher/k3s:v1.32.9-k3s1@sha256:...".
+func imageTag(image string) (string, error) {
+	name, _, _ := strings.Cut(image, "@")
+
+	// the registry host may have a port, so the tag is looked up in the
+	// last path component only
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+
+	_, tag, ok := strings.Cut(name, ":")
+	if !ok || tag == "" {
+		return "", fmt.Errorf("k3s image %q has no tag", image)
+	}
+
+	return tag, nil
+}
@@ -0,0 +1,50 @@
+package k3senv_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/lburgazzoli/k3s-envtest/pkg/k3senv"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestVersions(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	env, err := k3senv.New()
+	g.Expect(err).NotTo(HaveOccurred())
+	t.Cleanup(func() {
+		_ = env.Stop(ctx)
+	})
+
+	_, err = env.GetKubernetesVersion(ctx)
+	g.Expect(err).To(MatchError(ContainSubstring("call Start() first")))
+
+	_, err = env.GetK3sVersion(ctx)
+	g.Expect(err).To(MatchError(ContainSubstring("call Start() first")))
+
+	err = env.Start(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// e.g. v1.32.9-k3s1 for rancher/k3s:v1.32.9-k3s1
+	_, expected, _ := strings.Cut(k3senv.DefaultK3sImage, ":")
+
+	k3sVersion, err := env.GetK3sVersion(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(k3sVersion).To(Equal(expected))
+
+	info, err := env.GetKubernetesVersion(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(info.Major).To(Equal("1"))
+	g.Expect(info.GitVersion).To(Equal(strings.Replace(expected, "-", "+", 1)))
+
+	// the cached version is not affected by changes to the returned one
+	info.Major = "0"
+
+	info, err = env.GetKubernetesVersion(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(info.Major).To(Equal("1"))
+}
@@ -0,0 +1,340 @@
+package k3senv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/lburgazzoli/k3s-envtest/internal/gvk"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+var (
+	// ErrWaitTimeout is returned by the WaitFor methods when the condition is
+	// not met within the timeout.
+	ErrWaitTimeout = errors.New("timed out waiting for condition")
+
+	// ErrPodFailed is returned by WaitForPodRunning when the pod terminated
+	// without reaching the Running phase.
+	ErrPodFailed = errors.New("pod failed")
+)
+
+// WaitOption configures the WaitFor methods of K3sEnv.
+type WaitOption interface {
+	ApplyToWaitOptions(opts *WaitOptions)
+}
+
+type waitOptionFunc func(*WaitOptions)
+
+func (f waitOptionFunc) ApplyToWaitOptions(opts *WaitOptions) {
+	f(opts)
+}
+
+// WaitOptions contains the configuration of the WaitFor methods of K3sEnv.
+type WaitOptions struct {
+	// PollInterval is how often the condition is checked.
+	// Defaults to the CRD poll interval of the environment.
+	PollInterval time.Duration
+
+	// Timeout is the maximum time to wait for the condition.
+	// Defaults to the CRD ready timeout of the environment.
+	Timeout time.Duration
+
+	// Finalizers are removed from the object by WaitForDeleted before waiting.
+	Finalizers []string
+}
+
+func (opts *WaitOptions) ApplyOptions(options []WaitOption) {
+	for _, opt := range options {
+		opt.ApplyToWaitOptions(opts)
+	}
+}
+
+// WithWaitPollInterval sets how often the condition is checked.
+func WithWaitPollInterval(interval time.Duration) WaitOption {
+	return waitOptionFunc(func(opts *WaitOptions) {
+		opts.PollInterval = interval
+	})
+}
+
+// WithWaitTimeout sets the maximum time to wait for the condition.
+func WithWaitTimeout(timeout time.Duration) WaitOption {
+	return waitOptionFunc(func(opts *WaitOptions) {
+		opts.Timeout = timeout
+	})
+}
+
+// WithFinalizer makes WaitForDeleted remove the named finalizer from the
+// object before waiting for it to be deleted.
+func WithFinalizer(name string) WaitOption {
+	return waitOptionFunc(func(opts *WaitOptions) {
+		opts.Finalizers = append(opts.Finalizers, name)
+	})
+}
+
+func (e *K3sEnv) newWaitOptions(opts []WaitOption) *WaitOptions {
+	waitOpts := &WaitOptions{
+		PollInterval: e.options.CRD.PollInterval,
+		Timeout:      e.options.CRD.ReadyTimeout,
+	}
+
+	waitOpts.ApplyOptions(opts)
+
+	return waitOpts
+}
+
+// WaitForPodRunning waits for the pod to reach the Running phase. It returns
+// an error wrapping ErrPodFailed if the pod terminates instead, or
+// ErrWaitTimeout if it is not running within the timeout.
+func (e *K3sEnv) WaitForPodRunning(ctx context.Context, namespace string, name string, opts ...WaitOption) error {
+	if e.cli == nil {
+		return errors.New("cluster not started - call Start() first")
+	}
+
+	var phase string
+
+	err := e.poll(ctx, e.newWaitOptions(opts), func(ctx context.Context) (bool, error) {
+		pod := unstructured.Unstructured{}
+		pod.SetGroupVersionKind(gvk.Pod)
+
+		if err := e.cli.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, &pod); err != nil {
+			if k8serr.IsNotFound(err) {
+				return false, nil
+			}
+			return false, fmt.Errorf("failed to get pod %s/%s: %w", namespace, name, err)
+		}
+
+		phase, _, _ = unstructured.NestedString(pod.Object, "status", "phase")
+
+		switch phase {
+		case "Running":
+			return true, nil
+		case "Failed", "Succeeded":
+			reason, _, _ := unstructured.NestedString(pod.Object, "status", "reason")
+			message, _, _ := unstructured.NestedString(pod.Object, "status", "message")
+
+			return false, fmt.Errorf("%w: pod %s/%s terminated with phase %s (reason: %s, message: %s)",
+				ErrPodFailed, namespace, name, phase, reason, message)
+		default:
+			return false, nil
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("pod %s/%s not running (phase: %s): %w", namespace, name, phase, err)
+	}
+
+	return nil
+}
+
+// WaitForDeploymentReady waits for all the replicas of the deployment to be
+// available. It returns ErrWaitTimeout if they are not within the timeout.
+func (e *K3sEnv) WaitForDeploymentReady(ctx context.Context, namespace string, name string, opts ...WaitOption) error {
+	if e.cli == nil {
+		return errors.New("cluster not started - call Start() first")
+	}
+
+	var replicas, available int64
+
+	err := e.poll(ctx, e.newWaitOptions(opts), func(ctx context.Context) (bool, error) {
+		deployment := unstructured.Unstructured{}
+		deployment.SetGroupVersionKind(gvk.Deployment)
+
+		if err := e.cli.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, &deployment); err != nil {
+			if k8serr.IsNotFound(err) {
+				return false, nil
+			}
+			return false, fmt.Errorf("failed to get deployment %s/%s: %w", namespace, name, err)
+		}
+
+		// spec.replicas defaults to 1 when not set
+		replicas = 1
+		if r, found, _ := unstructured.NestedInt64(deployment.Object, "spec", "replicas"); found {
+			replicas = r
+		}
+
+		available, _, _ = unstructured.NestedInt64(deployment.Object, "status", "availableReplicas")
+		observed, _, _ := unstructured.NestedInt64(deployment.Object, "status", "observedGeneration")
+
+		return observed >= deployment.GetGeneration() && available == replicas, nil
+	})
+	if err != nil {
+		return fmt.Errorf("deployment %s/%s not ready (%d/%d replicas available): %w",
+			namespace, name, available, replicas, err)
+	}
+
+	return nil
+}
+
+// WaitForCondition waits for the object identified by obj to have the
+// condition of the given type with the given status. The condition is looked
+// up in status.conditions, so that any resource following the Kubernetes
+// conditions convention is supported, including custom resources whose type
+// is not registered in the scheme as long as obj carries its GVK. It returns
+// ErrWaitTimeout if the condition is not met within the timeout.
+func (e *K3sEnv) WaitForCondition(
+	ctx context.Context,
+	obj client.Object,
+	conditionType string,
+	conditionStatus metav1.ConditionStatus,
+	opts ...WaitOption,
+) error {
+	if e.cli == nil {
+		return errors.New("cluster not started - call Start() first")
+	}
+
+	objGVK, err := gvk.FromObject(e.options.Scheme, obj)
+	if err != nil {
+		return fmt.Errorf("failed to resolve GVK of %s: %w", obj.GetName(), err)
+	}
+
+	key := client.ObjectKeyFromObject(obj)
+	observed := "<not found>"
+
+	err = e.poll(ctx, e.newWaitOptions(opts), func(ctx context.Context) (bool, error) {
+		u := unstructured.Unstructured{}
+		u.SetGroupVersionKind(objGVK)
+
+		if err := e.cli.Get(ctx, key, &u); err != nil {
+			if k8serr.IsNotFound(err) {
+				return false, nil
+			}
+			return false, fmt.Errorf("failed to get %s %s: %w", objGVK.Kind, key, err)
+		}
+
+		status, found := findConditionStatus(u, conditionType)
+		if !found {
+			observed = "<not set>"
+			return false, nil
+		}
+
+		observed = status
+
+		return status == string(conditionStatus), nil
+	})
+	if err != nil {
+		return fmt.Errorf("%s %s condition %s is not %s (observed: %s): %w",
+			objGVK.Kind, key, conditionType, conditionStatus, observed, err)
+	}
+
+	return nil
+}
+
+// WaitForDeleted waits for the object identified by obj to be gone. It only
+// observes the deletion, which must have been requested by the caller, and
+// finalizers blocking it must be removed by the caller as well, unless they
+// are given with WithFinalizer. It returns ErrWaitTimeout, along with the last
+// observed resource version, if the object is still present after the timeout.
+func (e *K3sEnv) WaitForDeleted(ctx context.Context, obj client.Object, opts ...WaitOption) error {
+	if e.cli == nil {
+		return errors.New("cluster not started - call Start() first")
+	}
+
+	objGVK, err := gvk.FromObject(e.options.Scheme, obj)
+	if err != nil {
+		return fmt.Errorf("failed to resolve GVK of %s: %w", obj.GetName(), err)
+	}
+
+	waitOpts := e.newWaitOptions(opts)
+	key := client.ObjectKeyFromObject(obj)
+
+	if len(waitOpts.Finalizers) > 0 {
+		if err := e.removeFinalizers(ctx, objGVK, key, waitOpts.Finalizers); err != nil {
+			return err
+		}
+	}
+
+	var resourceVersion string
+
+	err = e.poll(ctx, waitOpts, func(ctx context.Context) (bool, error) {
+		u := unstructured.Unstructured{}
+		u.SetGroupVersionKind(objGVK)
+
+		if err := e.cli.Get(ctx, key, &u); err != nil {
+			if k8serr.IsNotFound(err) {
+				return true, nil
+			}
+			return false, fmt.Errorf("failed to get %s %s: %w", objGVK.Kind, key, err)
+		}
+
+		resourceVersion = u.GetResourceVersion()
+
+		return false, nil
+	})
+	if err != nil {
+		return fmt.Errorf("%s %s not deleted (resource version: %s): %w", objGVK.Kind, key, resourceVersion, err)
+	}
+
+	return nil
+}
+
+// removeFinalizers patches the given finalizers out of the object, if present.
+func (e *K3sEnv) removeFinalizers(
+	ctx context.Context,
+	objGVK schema.GroupVersionKind,
+	key client.ObjectKey,
+	finalizers []string,
+) error {
+	u := unstructured.Unstructured{}
+	u.SetGroupVersionKind(objGVK)
+
+	if err := e.cli.Get(ctx, key, &u); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	original := u.DeepCopy()
+
+	u.SetFinalizers(slices.DeleteFunc(u.GetFinalizers(), func(f string) bool {
+		return slices.Contains(finalizers, f)
+	}))
+
+	if len(u.GetFinalizers()) == len(original.GetFinalizers()) {
+		return nil
+	}
+
+	patch := client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{})
+	if err := e.cli.Patch(ctx, &u, patch); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to remove finalizers from %s %s: %w", objGVK.Kind, key, err)
+	}
+
+	e.debugf("Finalizers %v removed from %s %s", finalizers, objGVK.Kind, key)
+
+	return nil
+}
+
+// findConditionStatus returns the status of the condition of the given type
+// found in status.conditions.
+func findConditionStatus(u unstructured.Unstructured, conditionType string) (string, bool) {
+	conditions, _, _ := unstructured.NestedSlice(u.Object, "status", "conditions")
+
+	for _, c := range conditions {
+		condition, ok := c.(map[string]any)
+		if !ok || condition["type"] != conditionType {
+			continue
+		}
+
+		status, _ := condition["status"].(string)
+
+		return status, true
+	}
+
+	return "", false
+}
+
+// poll runs the condition until it is met, it fails or the timeout expires,
+// in which case an error wrapping ErrWaitTimeout is returned.
+func (e *K3sEnv) poll(ctx context.Context, waitOpts *WaitOptions, condition wait.ConditionWithContextFunc) error {
+	err := wait.PollUntilContextTimeout(ctx, waitOpts.PollInterval, waitOpts.Timeout, true, condition)
+	if err != nil && wait.Interrupted(err) {
+		return fmt.Errorf("%w after %v", ErrWaitTimeout, waitOpts.Timeout)
+	}
+
+	return err
+}
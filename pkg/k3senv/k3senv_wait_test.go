@@ -0,0 +1,228 @@
+package k3senv_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lburgazzoli/k3s-envtest/pkg/k3senv"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	. "github.com/onsi/gomega"
+)
+
+const testPauseImage = "rancher/mirrored-pause:3.6"
+
+func newTestPod(namespace string, name string, image string, command ...string) *unstructured.Unstructured {
+	container := map[string]any{
+		"name":  "main",
+		"image": image,
+	}
+	if len(command) > 0 {
+		cmd := make([]any, 0, len(command))
+		for _, c := range command {
+			cmd = append(cmd, c)
+		}
+		container["command"] = cmd
+	}
+
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata": map[string]any{
+			"namespace": namespace,
+			"name":      name,
+		},
+		"spec": map[string]any{
+			"restartPolicy": "Never",
+			"containers":    []any{container},
+		},
+	}}
+}
+
+func newTestDeployment(namespace string, name string, replicas int64) *unstructured.Unstructured {
+	labels := map[string]any{"app": name}
+
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]any{
+			"namespace": namespace,
+			"name":      name,
+		},
+		"spec": map[string]any{
+			"replicas": replicas,
+			"selector": map[string]any{"matchLabels": labels},
+			"template": map[string]any{
+				"metadata": map[string]any{"labels": labels},
+				"spec": map[string]any{
+					"containers": []any{
+						map[string]any{"name": "main", "image": testPauseImage},
+					},
+				},
+			},
+		},
+	}}
+}
+
+func TestWaitOptions(t *testing.T) {
+	g := NewWithT(t)
+
+	opts := &k3senv.WaitOptions{}
+	opts.ApplyOptions([]k3senv.WaitOption{
+		k3senv.WithWaitPollInterval(time.Second),
+		k3senv.WithWaitTimeout(time.Minute),
+		k3senv.WithFinalizer("example.com/a"),
+		k3senv.WithFinalizer("example.com/b"),
+	})
+
+	g.Expect(opts.PollInterval).To(Equal(time.Second))
+	g.Expect(opts.Timeout).To(Equal(time.Minute))
+	g.Expect(opts.Finalizers).To(Equal([]string{"example.com/a", "example.com/b"}))
+}
+
+func TestWaitForWorkloads(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	env, err := k3senv.New()
+	g.Expect(err).NotTo(HaveOccurred())
+	t.Cleanup(func() {
+		_ = env.Stop(ctx)
+	})
+
+	err = env.WaitForPodRunning(ctx, "default", "missing")
+	g.Expect(err).To(MatchError(ContainSubstring("call Start() first")))
+
+	err = env.Start(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	ns, err := env.CreateTestNamespace(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	t.Run("Pod running", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(env.Client().Create(ctx, newTestPod(ns, "running", testPauseImage))).To(Succeed())
+
+		err := env.WaitForPodRunning(ctx, ns, "running", k3senv.WithWaitTimeout(2*time.Minute))
+		g.Expect(err).NotTo(HaveOccurred())
+	})
+
+	t.Run("Pod failed", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(env.Client().Create(ctx, newTestPod(ns, "failed", "busybox:1.36", "sh", "-c", "exit 1"))).To(Succeed())
+
+		err := env.WaitForPodRunning(ctx, ns, "failed", k3senv.WithWaitTimeout(2*time.Minute))
+		g.Expect(err).To(MatchError(k3senv.ErrPodFailed))
+		g.Expect(err).NotTo(MatchError(k3senv.ErrWaitTimeout))
+	})
+
+	t.Run("Pod timeout", func(t *testing.T) {
+		g := NewWithT(t)
+
+		err := env.WaitForPodRunning(ctx, ns, "missing", k3senv.WithWaitTimeout(time.Second))
+		g.Expect(err).To(MatchError(k3senv.ErrWaitTimeout))
+		g.Expect(err).NotTo(MatchError(k3senv.ErrPodFailed))
+	})
+
+	t.Run("Deployment ready", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(env.Client().Create(ctx, newTestDeployment(ns, "ready", 2))).To(Succeed())
+
+		err := env.WaitForDeploymentReady(ctx, ns, "ready", k3senv.WithWaitTimeout(2*time.Minute))
+		g.Expect(err).NotTo(HaveOccurred())
+	})
+}
+
+func TestWaitForCondition(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	g.Expect(apiextensionsv1.AddToScheme(scheme)).To(Succeed())
+
+	crd := newTestCRDNonConvertible()
+
+	env, err := k3senv.New(
+		k3senv.WithScheme(scheme),
+		k3senv.WithObjects(crd),
+	)
+	g.Expect(err).NotTo(HaveOccurred())
+	t.Cleanup(func() {
+		_ = env.Stop(ctx)
+	})
+
+	err = env.Start(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	t.Run("Condition met", func(t *testing.T) {
+		g := NewWithT(t)
+
+		err := env.WaitForCondition(ctx, newTestCRDNonConvertible(), "Established", metav1.ConditionTrue)
+		g.Expect(err).NotTo(HaveOccurred())
+	})
+
+	t.Run("Condition not met", func(t *testing.T) {
+		g := NewWithT(t)
+
+		err := env.WaitForCondition(ctx, newTestCRDNonConvertible(), "Established", metav1.ConditionFalse,
+			k3senv.WithWaitTimeout(time.Second),
+		)
+		g.Expect(err).To(MatchError(k3senv.ErrWaitTimeout))
+		g.Expect(err.Error()).To(ContainSubstring("observed: True"))
+	})
+
+	t.Run("Unstructured custom resource", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion("apiextensions.k8s.io/v1")
+		obj.SetKind("CustomResourceDefinition")
+		obj.SetName(crd.Name)
+
+		err := env.WaitForCondition(ctx, obj, "NamesAccepted", metav1.ConditionTrue)
+		g.Expect(err).NotTo(HaveOccurred())
+	})
+}
+
+func TestWaitForDeleted(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	env, err := k3senv.New()
+	g.Expect(err).NotTo(HaveOccurred())
+	t.Cleanup(func() {
+		_ = env.Stop(ctx)
+	})
+
+	err = env.Start(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	cm := &unstructured.Unstructured{}
+	cm.SetAPIVersion("v1")
+	cm.SetKind("ConfigMap")
+	cm.SetNamespace(metav1.NamespaceDefault)
+	cm.SetName("finalized")
+	cm.SetFinalizers([]string{"example.com/block"})
+
+	g.Expect(env.Client().Create(ctx, cm)).To(Succeed())
+	g.Expect(env.Client().Delete(ctx, cm)).To(Succeed())
+
+	err = env.WaitForDeleted(ctx, cm, k3senv.WithWaitTimeout(time.Second))
+	g.Expect(err).To(MatchError(k3senv.ErrWaitTimeout))
+	g.Expect(err.Error()).To(ContainSubstring("resource version"))
+
+	err = env.WaitForDeleted(ctx, cm, k3senv.WithFinalizer("example.com/block"))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// waiting for a deleted object returns immediately
+	err = env.WaitForDeleted(ctx, cm)
+	g.Expect(err).NotTo(HaveOccurred())
+}
@@ -0,0 +1,97 @@
+package k3senv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	ctrlwebhook "sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// StartWebhookServer starts the server returned by WebhookServer, with the
+// handlers registered by registrar, and waits for it to accept connections,
+// up to the webhook ReadyTimeout. The server is stopped when ctx is cancelled
+// or by a teardown task on Stop:
+//
+//	err := env.StartWebhookServer(ctx, func(s ctrlwebhook.Server) {
+//	    s.Register("/validate", &ctrlwebhook.Admission{Handler: myValidator})
+//	})
+func (e *K3sEnv) StartWebhookServer(ctx context.Context, registrar func(ctrlwebhook.Server)) error {
+	if !e.IsStarted() {
+		return errors.New("cluster not started - call Start() first")
+	}
+
+	server := e.WebhookServer()
+	if registrar != nil {
+		registrar(server)
+	}
+
+	serverCtx, cancel := context.WithCancel(ctx)
+	stopped := make(chan struct{})
+
+	var serveErr error
+
+	go func() {
+		defer close(stopped)
+		serveErr = server.Start(serverCtx)
+	}()
+
+	stop := func() error {
+		cancel()
+		<-stopped
+
+		return serveErr
+	}
+
+	if err := e.waitForWebhookServer(ctx, stopped); err != nil {
+		if stopErr := stop(); stopErr != nil {
+			err = fmt.Errorf("%w: %w", err, stopErr)
+		}
+		return err
+	}
+
+	e.debugf("Webhook server listening on port %d", e.options.Webhook.Port)
+
+	e.AddTeardown(func(context.Context) error {
+		if err := stop(); err != nil {
+			return fmt.Errorf("failed to stop webhook server: %w", err)
+		}
+		return nil
+	})
+
+	return nil
+}
+
+// waitForWebhookServer dials the webhook port until the server accepts
+// connections, it stops, or the webhook ReadyTimeout expires.
+func (e *K3sEnv) waitForWebhookServer(ctx context.Context, stopped <-chan struct{}) error {
+	addr := net.JoinHostPort("127.0.0.1", strconv.Itoa(e.options.Webhook.Port))
+	dialer := net.Dialer{Timeout: e.options.Webhook.PollInterval}
+
+	timeout := time.NewTimer(e.options.Webhook.ReadyTimeout)
+	defer timeout.Stop()
+
+	ticker := time.NewTicker(e.options.Webhook.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		conn, err := dialer.DialContext(ctx, "tcp", addr)
+		if err == nil {
+			_ = conn.Close()
+			return nil
+		}
+
+		select {
+		case <-stopped:
+			return errors.New("webhook server stopped before accepting connections")
+		case <-ctx.Done():
+			return fmt.Errorf("webhook server not listening on %s: %w", addr, ctx.Err())
+		case <-timeout.C:
+			return fmt.Errorf("webhook server not listening on %s after %v: %w", addr, e.options.Webhook.ReadyTimeout, err)
+		case <-ticker.C:
+		}
+	}
+}
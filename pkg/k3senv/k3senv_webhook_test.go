@@ -0,0 +1,67 @@
+package k3senv_test
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+
+	"github.com/lburgazzoli/k3s-envtest/pkg/k3senv"
+	ctrlwebhook "sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	admissionapiv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestStartWebhookServer(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	port, err := k3senv.FindAvailablePort()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	env, err := k3senv.New(k3senv.WithWebhookPort(port))
+	g.Expect(err).NotTo(HaveOccurred())
+	t.Cleanup(func() {
+		_ = env.Stop(ctx)
+	})
+
+	registrar := func(s ctrlwebhook.Server) {
+		s.Register("/validate", &ctrlwebhook.Admission{
+			Handler: admission.HandlerFunc(func(_ context.Context, _ admission.Request) admission.Response {
+				return admission.Denied("denied by test")
+			}),
+		})
+	}
+
+	err = env.StartWebhookServer(ctx, registrar)
+	g.Expect(err).To(MatchError(ContainSubstring("call Start() first")))
+
+	err = env.Start(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(env.StartWebhookServer(ctx, registrar)).To(Succeed())
+
+	cm := &unstructured.Unstructured{}
+	cm.SetAPIVersion("v1")
+	cm.SetKind("ConfigMap")
+	cm.SetNamespace(metav1.NamespaceDefault)
+	cm.SetName("test")
+
+	// the server is listening once started
+	resp, err := env.SimulateAdmission(ctx, "/validate", cm, admissionapiv1.Create)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(resp.Allowed).To(BeFalse())
+	g.Expect(resp.Result.Message).To(ContainSubstring("denied by test"))
+
+	// the server is shut down on Stop, releasing the port
+	g.Expect(env.Stop(ctx)).To(Succeed())
+
+	l, err := net.Listen("tcp", net.JoinHostPort("0.0.0.0", strconv.Itoa(port)))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(l.Close()).To(Succeed())
+}